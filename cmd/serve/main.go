@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
@@ -14,6 +15,9 @@ import (
 )
 
 func main() {
+	validateTemplates := flag.Bool("validate-templates", false, "validate the pod templates against the cluster and exit")
+	flag.Parse()
+
 	log := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
 	if err := config.LoadConf(); err != nil {
@@ -21,6 +25,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *validateTemplates {
+		os.Exit(runValidateTemplates(log))
+	}
+
 	quitCh := make(chan os.Signal, 1)
 	signal.Notify(
 		quitCh,
@@ -46,4 +54,29 @@ func main() {
 		log.Error(fmt.Sprintf("failed to start server: %v", err))
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// runValidateTemplates prints the problems of each instance type and returns the exit code.
+func runValidateTemplates(log *slog.Logger) int {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	results, err := server.ValidateTemplates(ctx, log)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to validate templates: %v", err))
+		return 1
+	}
+
+	code := 0
+	for _, result := range results {
+		if len(result.Problems) == 0 {
+			fmt.Printf("%s: ok\n", result.InstanceType)
+			continue
+		}
+		code = 1
+		for _, p := range result.Problems {
+			fmt.Printf("%s: [%s] %s\n", result.InstanceType, p.Check, p.Message)
+		}
+	}
+	return code
+}
@@ -39,6 +39,19 @@ type Invoker interface {
 	//
 	// DELETE /instances/{instanceId}
 	DeleteInstance(ctx context.Context, params DeleteInstanceParams) (DeleteInstanceRes, error)
+	// DeleteVolume invokes deleteVolume operation.
+	//
+	// Delete a volume.
+	//
+	// DELETE /volumes/{volumeId}
+	DeleteVolume(ctx context.Context, params DeleteVolumeParams) (DeleteVolumeRes, error)
+	// ExtendInstance invokes extendInstance operation.
+	//
+	// Marks the instance as active and keeps the reaping policies away from it for a while, cancelling a
+	// pending expiry.
+	//
+	// POST /instances/{instanceId}/extend
+	ExtendInstance(ctx context.Context, params ExtendInstanceParams) (ExtendInstanceRes, error)
 	// GetAuthMe invokes getAuthMe operation.
 	//
 	// Get current user status.
@@ -51,6 +64,13 @@ type Invoker interface {
 	//
 	// GET /configuration
 	GetConfiguration(ctx context.Context) (*Configuration, error)
+	// GetInstance invokes getInstance operation.
+	//
+	// Lets a workspace check its own status and expiry using the HAKONIWA_INSTANCE_ID injected into its
+	// pod.
+	//
+	// GET /instances/{instanceId}
+	GetInstance(ctx context.Context, params GetInstanceParams) (GetInstanceRes, error)
 	// ListInstanceTypes invokes listInstanceTypes operation.
 	//
 	// List available instance types.
@@ -63,6 +83,12 @@ type Invoker interface {
 	//
 	// GET /instances
 	ListInstances(ctx context.Context) ([]Instance, error)
+	// ListVolumes invokes listVolumes operation.
+	//
+	// List user volumes.
+	//
+	// GET /volumes
+	ListVolumes(ctx context.Context) ([]Volume, error)
 	// LoginAnonymous invokes loginAnonymous operation.
 	//
 	// Login anonymously (creates session only).
@@ -87,6 +113,32 @@ type Invoker interface {
 	//
 	// GET /auth/oidc/callback
 	OidcCallback(ctx context.Context, params OidcCallbackParams) (*OidcCallbackFound, error)
+	// ReloadInstanceTypes invokes reloadInstanceTypes operation.
+	//
+	// Reads the pod template again and atomically replaces the instance types. Existing instances keep
+	// the template they were created with.
+	//
+	// POST /admin/instance-types/reload
+	ReloadInstanceTypes(ctx context.Context) (ReloadInstanceTypesRes, error)
+	// StartInstance invokes startInstance operation.
+	//
+	// Start a stopped instance.
+	//
+	// POST /instances/{instanceId}/start
+	StartInstance(ctx context.Context, params StartInstanceParams) (StartInstanceRes, error)
+	// StopInstance invokes stopInstance operation.
+	//
+	// Deletes the instance pod but keeps the instance record and its volumes so it can be started again.
+	//
+	// POST /instances/{instanceId}/stop
+	StopInstance(ctx context.Context, params StopInstanceParams) (StopInstanceRes, error)
+	// ValidateInstanceTypes invokes validateInstanceTypes operation.
+	//
+	// Validates the pod template of each instance type with a server-side dry-run create and
+	// Hakoniwa-specific checks. Nothing is created.
+	//
+	// GET /admin/instance-types/validation
+	ValidateInstanceTypes(ctx context.Context) (ValidateInstanceTypesRes, error)
 }
 
 // Client implements OAS client.
@@ -299,21 +351,21 @@ func (c *Client) sendDeleteInstance(ctx context.Context, params DeleteInstancePa
 	return result, nil
 }
 
-// GetAuthMe invokes getAuthMe operation.
+// DeleteVolume invokes deleteVolume operation.
 //
-// Get current user status.
+// Delete a volume.
 //
-// GET /auth/me
-func (c *Client) GetAuthMe(ctx context.Context) (GetAuthMeRes, error) {
-	res, err := c.sendGetAuthMe(ctx)
+// DELETE /volumes/{volumeId}
+func (c *Client) DeleteVolume(ctx context.Context, params DeleteVolumeParams) (DeleteVolumeRes, error) {
+	res, err := c.sendDeleteVolume(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendGetAuthMe(ctx context.Context) (res GetAuthMeRes, err error) {
+func (c *Client) sendDeleteVolume(ctx context.Context, params DeleteVolumeParams) (res DeleteVolumeRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("getAuthMe"),
-		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/auth/me"),
+		otelogen.OperationID("deleteVolume"),
+		semconv.HTTPRequestMethodKey.String("DELETE"),
+		semconv.URLTemplateKey.String("/volumes/{volumeId}"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -329,7 +381,7 @@ func (c *Client) sendGetAuthMe(ctx context.Context) (res GetAuthMeRes, err error
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, GetAuthMeOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, DeleteVolumeOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -346,12 +398,30 @@ func (c *Client) sendGetAuthMe(ctx context.Context) (res GetAuthMeRes, err error
 
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
-	var pathParts [1]string
-	pathParts[0] = "/auth/me"
+	var pathParts [2]string
+	pathParts[0] = "/volumes/"
+	{
+		// Encode "volumeId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "volumeId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.VolumeId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "GET", u)
+	r, err := ht.NewRequest(ctx, "DELETE", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
@@ -364,7 +434,7 @@ func (c *Client) sendGetAuthMe(ctx context.Context) (res GetAuthMeRes, err error
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeGetAuthMeResponse(resp)
+	result, err := decodeDeleteVolumeResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -372,21 +442,22 @@ func (c *Client) sendGetAuthMe(ctx context.Context) (res GetAuthMeRes, err error
 	return result, nil
 }
 
-// GetConfiguration invokes getConfiguration operation.
+// ExtendInstance invokes extendInstance operation.
 //
-// Get application configuration.
+// Marks the instance as active and keeps the reaping policies away from it for a while, cancelling a
+// pending expiry.
 //
-// GET /configuration
-func (c *Client) GetConfiguration(ctx context.Context) (*Configuration, error) {
-	res, err := c.sendGetConfiguration(ctx)
+// POST /instances/{instanceId}/extend
+func (c *Client) ExtendInstance(ctx context.Context, params ExtendInstanceParams) (ExtendInstanceRes, error) {
+	res, err := c.sendExtendInstance(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendGetConfiguration(ctx context.Context) (res *Configuration, err error) {
+func (c *Client) sendExtendInstance(ctx context.Context, params ExtendInstanceParams) (res ExtendInstanceRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("getConfiguration"),
-		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/configuration"),
+		otelogen.OperationID("extendInstance"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/instances/{instanceId}/extend"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -402,7 +473,7 @@ func (c *Client) sendGetConfiguration(ctx context.Context) (res *Configuration,
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, GetConfigurationOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, ExtendInstanceOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -419,12 +490,31 @@ func (c *Client) sendGetConfiguration(ctx context.Context) (res *Configuration,
 
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
-	var pathParts [1]string
-	pathParts[0] = "/configuration"
+	var pathParts [3]string
+	pathParts[0] = "/instances/"
+	{
+		// Encode "instanceId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "instanceId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.InstanceId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
+	pathParts[2] = "/extend"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "GET", u)
+	r, err := ht.NewRequest(ctx, "POST", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
@@ -437,7 +527,7 @@ func (c *Client) sendGetConfiguration(ctx context.Context) (res *Configuration,
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeGetConfigurationResponse(resp)
+	result, err := decodeExtendInstanceResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -445,21 +535,21 @@ func (c *Client) sendGetConfiguration(ctx context.Context) (res *Configuration,
 	return result, nil
 }
 
-// ListInstanceTypes invokes listInstanceTypes operation.
+// GetAuthMe invokes getAuthMe operation.
 //
-// List available instance types.
+// Get current user status.
 //
-// GET /instance-types
-func (c *Client) ListInstanceTypes(ctx context.Context) ([]InstanceType, error) {
-	res, err := c.sendListInstanceTypes(ctx)
+// GET /auth/me
+func (c *Client) GetAuthMe(ctx context.Context) (GetAuthMeRes, error) {
+	res, err := c.sendGetAuthMe(ctx)
 	return res, err
 }
 
-func (c *Client) sendListInstanceTypes(ctx context.Context) (res []InstanceType, err error) {
+func (c *Client) sendGetAuthMe(ctx context.Context) (res GetAuthMeRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("listInstanceTypes"),
+		otelogen.OperationID("getAuthMe"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/instance-types"),
+		semconv.URLTemplateKey.String("/auth/me"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -475,7 +565,7 @@ func (c *Client) sendListInstanceTypes(ctx context.Context) (res []InstanceType,
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, ListInstanceTypesOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetAuthMeOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -493,7 +583,7 @@ func (c *Client) sendListInstanceTypes(ctx context.Context) (res []InstanceType,
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/instance-types"
+	pathParts[0] = "/auth/me"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -510,7 +600,7 @@ func (c *Client) sendListInstanceTypes(ctx context.Context) (res []InstanceType,
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeListInstanceTypesResponse(resp)
+	result, err := decodeGetAuthMeResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -518,21 +608,21 @@ func (c *Client) sendListInstanceTypes(ctx context.Context) (res []InstanceType,
 	return result, nil
 }
 
-// ListInstances invokes listInstances operation.
+// GetConfiguration invokes getConfiguration operation.
 //
-// List user instances.
+// Get application configuration.
 //
-// GET /instances
-func (c *Client) ListInstances(ctx context.Context) ([]Instance, error) {
-	res, err := c.sendListInstances(ctx)
+// GET /configuration
+func (c *Client) GetConfiguration(ctx context.Context) (*Configuration, error) {
+	res, err := c.sendGetConfiguration(ctx)
 	return res, err
 }
 
-func (c *Client) sendListInstances(ctx context.Context) (res []Instance, err error) {
+func (c *Client) sendGetConfiguration(ctx context.Context) (res *Configuration, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("listInstances"),
+		otelogen.OperationID("getConfiguration"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/instances"),
+		semconv.URLTemplateKey.String("/configuration"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -548,7 +638,7 @@ func (c *Client) sendListInstances(ctx context.Context) (res []Instance, err err
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, ListInstancesOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetConfigurationOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -566,7 +656,7 @@ func (c *Client) sendListInstances(ctx context.Context) (res []Instance, err err
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/instances"
+	pathParts[0] = "/configuration"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -583,7 +673,7 @@ func (c *Client) sendListInstances(ctx context.Context) (res []Instance, err err
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeListInstancesResponse(resp)
+	result, err := decodeGetConfigurationResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -591,21 +681,22 @@ func (c *Client) sendListInstances(ctx context.Context) (res []Instance, err err
 	return result, nil
 }
 
-// LoginAnonymous invokes loginAnonymous operation.
+// GetInstance invokes getInstance operation.
 //
-// Login anonymously (creates session only).
+// Lets a workspace check its own status and expiry using the HAKONIWA_INSTANCE_ID injected into its
+// pod.
 //
-// POST /auth/anonymous
-func (c *Client) LoginAnonymous(ctx context.Context) (*AuthStatus, error) {
-	res, err := c.sendLoginAnonymous(ctx)
+// GET /instances/{instanceId}
+func (c *Client) GetInstance(ctx context.Context, params GetInstanceParams) (GetInstanceRes, error) {
+	res, err := c.sendGetInstance(ctx, params)
 	return res, err
 }
 
-func (c *Client) sendLoginAnonymous(ctx context.Context) (res *AuthStatus, err error) {
+func (c *Client) sendGetInstance(ctx context.Context, params GetInstanceParams) (res GetInstanceRes, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("loginAnonymous"),
-		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.URLTemplateKey.String("/auth/anonymous"),
+		otelogen.OperationID("getInstance"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/instances/{instanceId}"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -621,7 +712,7 @@ func (c *Client) sendLoginAnonymous(ctx context.Context) (res *AuthStatus, err e
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, LoginAnonymousOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, GetInstanceOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -638,12 +729,30 @@ func (c *Client) sendLoginAnonymous(ctx context.Context) (res *AuthStatus, err e
 
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
-	var pathParts [1]string
-	pathParts[0] = "/auth/anonymous"
+	var pathParts [2]string
+	pathParts[0] = "/instances/"
+	{
+		// Encode "instanceId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "instanceId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.InstanceId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		pathParts[1] = encoded
+	}
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "POST", u)
+	r, err := ht.NewRequest(ctx, "GET", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
@@ -656,7 +765,7 @@ func (c *Client) sendLoginAnonymous(ctx context.Context) (res *AuthStatus, err e
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeLoginAnonymousResponse(resp)
+	result, err := decodeGetInstanceResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -664,21 +773,21 @@ func (c *Client) sendLoginAnonymous(ctx context.Context) (res *AuthStatus, err e
 	return result, nil
 }
 
-// Logout invokes logout operation.
+// ListInstanceTypes invokes listInstanceTypes operation.
 //
-// Logout.
+// List available instance types.
 //
-// POST /auth/logout
-func (c *Client) Logout(ctx context.Context) error {
-	_, err := c.sendLogout(ctx)
-	return err
+// GET /instance-types
+func (c *Client) ListInstanceTypes(ctx context.Context) ([]InstanceType, error) {
+	res, err := c.sendListInstanceTypes(ctx)
+	return res, err
 }
 
-func (c *Client) sendLogout(ctx context.Context) (res *LogoutOK, err error) {
+func (c *Client) sendListInstanceTypes(ctx context.Context) (res []InstanceType, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("logout"),
-		semconv.HTTPRequestMethodKey.String("POST"),
-		semconv.URLTemplateKey.String("/auth/logout"),
+		otelogen.OperationID("listInstanceTypes"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/instance-types"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -694,7 +803,7 @@ func (c *Client) sendLogout(ctx context.Context) (res *LogoutOK, err error) {
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, LogoutOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, ListInstanceTypesOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -712,11 +821,11 @@ func (c *Client) sendLogout(ctx context.Context) (res *LogoutOK, err error) {
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/auth/logout"
+	pathParts[0] = "/instance-types"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
-	r, err := ht.NewRequest(ctx, "POST", u)
+	r, err := ht.NewRequest(ctx, "GET", u)
 	if err != nil {
 		return res, errors.Wrap(err, "create request")
 	}
@@ -729,7 +838,7 @@ func (c *Client) sendLogout(ctx context.Context) (res *LogoutOK, err error) {
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeLogoutResponse(resp)
+	result, err := decodeListInstanceTypesResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -737,21 +846,21 @@ func (c *Client) sendLogout(ctx context.Context) (res *LogoutOK, err error) {
 	return result, nil
 }
 
-// OidcAuthorize invokes oidcAuthorize operation.
+// ListInstances invokes listInstances operation.
 //
-// Redirect to OIDC provider.
+// List user instances.
 //
-// GET /auth/oidc/authorize
-func (c *Client) OidcAuthorize(ctx context.Context) (*OidcAuthorizeFound, error) {
-	res, err := c.sendOidcAuthorize(ctx)
+// GET /instances
+func (c *Client) ListInstances(ctx context.Context) ([]Instance, error) {
+	res, err := c.sendListInstances(ctx)
 	return res, err
 }
 
-func (c *Client) sendOidcAuthorize(ctx context.Context) (res *OidcAuthorizeFound, err error) {
+func (c *Client) sendListInstances(ctx context.Context) (res []Instance, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("oidcAuthorize"),
+		otelogen.OperationID("listInstances"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/auth/oidc/authorize"),
+		semconv.URLTemplateKey.String("/instances"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -767,7 +876,7 @@ func (c *Client) sendOidcAuthorize(ctx context.Context) (res *OidcAuthorizeFound
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, OidcAuthorizeOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, ListInstancesOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -785,7 +894,7 @@ func (c *Client) sendOidcAuthorize(ctx context.Context) (res *OidcAuthorizeFound
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/auth/oidc/authorize"
+	pathParts[0] = "/instances"
 	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
@@ -802,7 +911,7 @@ func (c *Client) sendOidcAuthorize(ctx context.Context) (res *OidcAuthorizeFound
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeOidcAuthorizeResponse(resp)
+	result, err := decodeListInstancesResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
@@ -810,21 +919,21 @@ func (c *Client) sendOidcAuthorize(ctx context.Context) (res *OidcAuthorizeFound
 	return result, nil
 }
 
-// OidcCallback invokes oidcCallback operation.
+// ListVolumes invokes listVolumes operation.
 //
-// Process OIDC callback from IdP.
+// List user volumes.
 //
-// GET /auth/oidc/callback
-func (c *Client) OidcCallback(ctx context.Context, params OidcCallbackParams) (*OidcCallbackFound, error) {
-	res, err := c.sendOidcCallback(ctx, params)
+// GET /volumes
+func (c *Client) ListVolumes(ctx context.Context) ([]Volume, error) {
+	res, err := c.sendListVolumes(ctx)
 	return res, err
 }
 
-func (c *Client) sendOidcCallback(ctx context.Context, params OidcCallbackParams) (res *OidcCallbackFound, err error) {
+func (c *Client) sendListVolumes(ctx context.Context) (res []Volume, err error) {
 	otelAttrs := []attribute.KeyValue{
-		otelogen.OperationID("oidcCallback"),
+		otelogen.OperationID("listVolumes"),
 		semconv.HTTPRequestMethodKey.String("GET"),
-		semconv.URLTemplateKey.String("/auth/oidc/callback"),
+		semconv.URLTemplateKey.String("/volumes"),
 	}
 	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
 
@@ -840,7 +949,7 @@ func (c *Client) sendOidcCallback(ctx context.Context, params OidcCallbackParams
 	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 
 	// Start a span for this request.
-	ctx, span := c.cfg.Tracer.Start(ctx, OidcCallbackOperation,
+	ctx, span := c.cfg.Tracer.Start(ctx, ListVolumesOperation,
 		trace.WithAttributes(otelAttrs...),
 		clientSpanKind,
 	)
@@ -858,74 +967,698 @@ func (c *Client) sendOidcCallback(ctx context.Context, params OidcCallbackParams
 	stage = "BuildURL"
 	u := uri.Clone(c.requestURL(ctx))
 	var pathParts [1]string
-	pathParts[0] = "/auth/oidc/callback"
+	pathParts[0] = "/volumes"
 	uri.AddPathParts(u, pathParts[:]...)
 
-	stage = "EncodeQueryParams"
-	q := uri.NewQueryEncoder()
-	{
-		// Encode "code" parameter.
-		cfg := uri.QueryParameterEncodingConfig{
-			Name:    "code",
-			Style:   uri.QueryStyleForm,
-			Explode: true,
-		}
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
 
-		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
-			return e.EncodeValue(conv.StringToString(params.Code))
-		}); err != nil {
-			return res, errors.Wrap(err, "encode query")
-		}
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
 	}
-	{
-		// Encode "state" parameter.
-		cfg := uri.QueryParameterEncodingConfig{
-			Name:    "state",
-			Style:   uri.QueryStyleForm,
-			Explode: true,
-		}
+	defer resp.Body.Close()
 
-		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+	stage = "DecodeResponse"
+	result, err := decodeListVolumesResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// LoginAnonymous invokes loginAnonymous operation.
+//
+// Login anonymously (creates session only).
+//
+// POST /auth/anonymous
+func (c *Client) LoginAnonymous(ctx context.Context) (*AuthStatus, error) {
+	res, err := c.sendLoginAnonymous(ctx)
+	return res, err
+}
+
+func (c *Client) sendLoginAnonymous(ctx context.Context) (res *AuthStatus, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("loginAnonymous"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/auth/anonymous"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, LoginAnonymousOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/auth/anonymous"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeLoginAnonymousResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// Logout invokes logout operation.
+//
+// Logout.
+//
+// POST /auth/logout
+func (c *Client) Logout(ctx context.Context) error {
+	_, err := c.sendLogout(ctx)
+	return err
+}
+
+func (c *Client) sendLogout(ctx context.Context) (res *LogoutOK, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("logout"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/auth/logout"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, LogoutOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/auth/logout"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeLogoutResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// OidcAuthorize invokes oidcAuthorize operation.
+//
+// Redirect to OIDC provider.
+//
+// GET /auth/oidc/authorize
+func (c *Client) OidcAuthorize(ctx context.Context) (*OidcAuthorizeFound, error) {
+	res, err := c.sendOidcAuthorize(ctx)
+	return res, err
+}
+
+func (c *Client) sendOidcAuthorize(ctx context.Context) (res *OidcAuthorizeFound, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("oidcAuthorize"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/auth/oidc/authorize"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, OidcAuthorizeOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/auth/oidc/authorize"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeOidcAuthorizeResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// OidcCallback invokes oidcCallback operation.
+//
+// Process OIDC callback from IdP.
+//
+// GET /auth/oidc/callback
+func (c *Client) OidcCallback(ctx context.Context, params OidcCallbackParams) (*OidcCallbackFound, error) {
+	res, err := c.sendOidcCallback(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendOidcCallback(ctx context.Context, params OidcCallbackParams) (res *OidcCallbackFound, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("oidcCallback"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/auth/oidc/callback"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, OidcCallbackOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/auth/oidc/callback"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeQueryParams"
+	q := uri.NewQueryEncoder()
+	{
+		// Encode "code" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "code",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			return e.EncodeValue(conv.StringToString(params.Code))
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "state" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "state",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
 			return e.EncodeValue(conv.StringToString(params.State))
 		}); err != nil {
 			return res, errors.Wrap(err, "encode query")
 		}
 	}
-	{
-		// Encode "error" parameter.
-		cfg := uri.QueryParameterEncodingConfig{
-			Name:    "error",
-			Style:   uri.QueryStyleForm,
-			Explode: true,
+	{
+		// Encode "error" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "error",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.Error.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	{
+		// Encode "error_description" parameter.
+		cfg := uri.QueryParameterEncodingConfig{
+			Name:    "error_description",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
+			if val, ok := params.ErrorDescription.Get(); ok {
+				return e.EncodeValue(conv.StringToString(val))
+			}
+			return nil
+		}); err != nil {
+			return res, errors.Wrap(err, "encode query")
+		}
+	}
+	u.RawQuery = q.Values().Encode()
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "GET", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeOidcCallbackResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// ReloadInstanceTypes invokes reloadInstanceTypes operation.
+//
+// Reads the pod template again and atomically replaces the instance types. Existing instances keep
+// the template they were created with.
+//
+// POST /admin/instance-types/reload
+func (c *Client) ReloadInstanceTypes(ctx context.Context) (ReloadInstanceTypesRes, error) {
+	res, err := c.sendReloadInstanceTypes(ctx)
+	return res, err
+}
+
+func (c *Client) sendReloadInstanceTypes(ctx context.Context) (res ReloadInstanceTypesRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("reloadInstanceTypes"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/admin/instance-types/reload"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, ReloadInstanceTypesOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/admin/instance-types/reload"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeReloadInstanceTypesResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// StartInstance invokes startInstance operation.
+//
+// Start a stopped instance.
+//
+// POST /instances/{instanceId}/start
+func (c *Client) StartInstance(ctx context.Context, params StartInstanceParams) (StartInstanceRes, error) {
+	res, err := c.sendStartInstance(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendStartInstance(ctx context.Context, params StartInstanceParams) (res StartInstanceRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("startInstance"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/instances/{instanceId}/start"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, StartInstanceOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 		}
+		span.End()
+	}()
 
-		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
-			if val, ok := params.Error.Get(); ok {
-				return e.EncodeValue(conv.StringToString(val))
-			}
-			return nil
-		}); err != nil {
-			return res, errors.Wrap(err, "encode query")
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [3]string
+	pathParts[0] = "/instances/"
+	{
+		// Encode "instanceId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "instanceId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.InstanceId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
 		}
+		pathParts[1] = encoded
 	}
-	{
-		// Encode "error_description" parameter.
-		cfg := uri.QueryParameterEncodingConfig{
-			Name:    "error_description",
-			Style:   uri.QueryStyleForm,
-			Explode: true,
+	pathParts[2] = "/start"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeStartInstanceResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// StopInstance invokes stopInstance operation.
+//
+// Deletes the instance pod but keeps the instance record and its volumes so it can be started again.
+//
+// POST /instances/{instanceId}/stop
+func (c *Client) StopInstance(ctx context.Context, params StopInstanceParams) (StopInstanceRes, error) {
+	res, err := c.sendStopInstance(ctx, params)
+	return res, err
+}
+
+func (c *Client) sendStopInstance(ctx context.Context, params StopInstanceParams) (res StopInstanceRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("stopInstance"),
+		semconv.HTTPRequestMethodKey.String("POST"),
+		semconv.URLTemplateKey.String("/instances/{instanceId}/stop"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, StopInstanceOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
 		}
+		span.End()
+	}()
 
-		if err := q.EncodeParam(cfg, func(e uri.Encoder) error {
-			if val, ok := params.ErrorDescription.Get(); ok {
-				return e.EncodeValue(conv.StringToString(val))
-			}
-			return nil
-		}); err != nil {
-			return res, errors.Wrap(err, "encode query")
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [3]string
+	pathParts[0] = "/instances/"
+	{
+		// Encode "instanceId" parameter.
+		e := uri.NewPathEncoder(uri.PathEncoderConfig{
+			Param:   "instanceId",
+			Style:   uri.PathStyleSimple,
+			Explode: false,
+		})
+		if err := func() error {
+			return e.EncodeValue(conv.StringToString(params.InstanceId))
+		}(); err != nil {
+			return res, errors.Wrap(err, "encode path")
+		}
+		encoded, err := e.Result()
+		if err != nil {
+			return res, errors.Wrap(err, "encode path")
 		}
+		pathParts[1] = encoded
 	}
-	u.RawQuery = q.Values().Encode()
+	pathParts[2] = "/stop"
+	uri.AddPathParts(u, pathParts[:]...)
+
+	stage = "EncodeRequest"
+	r, err := ht.NewRequest(ctx, "POST", u)
+	if err != nil {
+		return res, errors.Wrap(err, "create request")
+	}
+
+	stage = "SendRequest"
+	resp, err := c.cfg.Client.Do(r)
+	if err != nil {
+		return res, errors.Wrap(err, "do request")
+	}
+	defer resp.Body.Close()
+
+	stage = "DecodeResponse"
+	result, err := decodeStopInstanceResponse(resp)
+	if err != nil {
+		return res, errors.Wrap(err, "decode response")
+	}
+
+	return result, nil
+}
+
+// ValidateInstanceTypes invokes validateInstanceTypes operation.
+//
+// Validates the pod template of each instance type with a server-side dry-run create and
+// Hakoniwa-specific checks. Nothing is created.
+//
+// GET /admin/instance-types/validation
+func (c *Client) ValidateInstanceTypes(ctx context.Context) (ValidateInstanceTypesRes, error) {
+	res, err := c.sendValidateInstanceTypes(ctx)
+	return res, err
+}
+
+func (c *Client) sendValidateInstanceTypes(ctx context.Context) (res ValidateInstanceTypesRes, err error) {
+	otelAttrs := []attribute.KeyValue{
+		otelogen.OperationID("validateInstanceTypes"),
+		semconv.HTTPRequestMethodKey.String("GET"),
+		semconv.URLTemplateKey.String("/admin/instance-types/validation"),
+	}
+	otelAttrs = append(otelAttrs, c.cfg.Attributes...)
+
+	// Run stopwatch.
+	startTime := time.Now()
+	defer func() {
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedDuration := time.Since(startTime)
+		c.duration.Record(ctx, float64(elapsedDuration)/float64(time.Millisecond), metric.WithAttributes(otelAttrs...))
+	}()
+
+	// Increment request counter.
+	c.requests.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+
+	// Start a span for this request.
+	ctx, span := c.cfg.Tracer.Start(ctx, ValidateInstanceTypesOperation,
+		trace.WithAttributes(otelAttrs...),
+		clientSpanKind,
+	)
+	// Track stage for error reporting.
+	var stage string
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, stage)
+			c.errors.Add(ctx, 1, metric.WithAttributes(otelAttrs...))
+		}
+		span.End()
+	}()
+
+	stage = "BuildURL"
+	u := uri.Clone(c.requestURL(ctx))
+	var pathParts [1]string
+	pathParts[0] = "/admin/instance-types/validation"
+	uri.AddPathParts(u, pathParts[:]...)
 
 	stage = "EncodeRequest"
 	r, err := ht.NewRequest(ctx, "GET", u)
@@ -941,7 +1674,7 @@ func (c *Client) sendOidcCallback(ctx context.Context, params OidcCallbackParams
 	defer resp.Body.Close()
 
 	stage = "DecodeResponse"
-	result, err := decodeOidcCallbackResponse(resp)
+	result, err := decodeValidateInstanceTypesResponse(resp)
 	if err != nil {
 		return res, errors.Wrap(err, "decode response")
 	}
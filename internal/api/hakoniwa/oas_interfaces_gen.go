@@ -9,6 +9,34 @@ type DeleteInstanceRes interface {
 	deleteInstanceRes()
 }
 
+type DeleteVolumeRes interface {
+	deleteVolumeRes()
+}
+
+type ExtendInstanceRes interface {
+	extendInstanceRes()
+}
+
 type GetAuthMeRes interface {
 	getAuthMeRes()
 }
+
+type GetInstanceRes interface {
+	getInstanceRes()
+}
+
+type ReloadInstanceTypesRes interface {
+	reloadInstanceTypesRes()
+}
+
+type StartInstanceRes interface {
+	startInstanceRes()
+}
+
+type StopInstanceRes interface {
+	stopInstanceRes()
+}
+
+type ValidateInstanceTypesRes interface {
+	validateInstanceTypesRes()
+}
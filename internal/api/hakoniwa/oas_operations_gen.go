@@ -6,14 +6,22 @@ package hakoniwa
 type OperationName = string
 
 const (
-	CreateInstanceOperation    OperationName = "CreateInstance"
-	DeleteInstanceOperation    OperationName = "DeleteInstance"
-	GetAuthMeOperation         OperationName = "GetAuthMe"
-	GetConfigurationOperation  OperationName = "GetConfiguration"
-	ListInstanceTypesOperation OperationName = "ListInstanceTypes"
-	ListInstancesOperation     OperationName = "ListInstances"
-	LoginAnonymousOperation    OperationName = "LoginAnonymous"
-	LogoutOperation            OperationName = "Logout"
-	OidcAuthorizeOperation     OperationName = "OidcAuthorize"
-	OidcCallbackOperation      OperationName = "OidcCallback"
+	CreateInstanceOperation        OperationName = "CreateInstance"
+	DeleteInstanceOperation        OperationName = "DeleteInstance"
+	DeleteVolumeOperation          OperationName = "DeleteVolume"
+	ExtendInstanceOperation        OperationName = "ExtendInstance"
+	GetAuthMeOperation             OperationName = "GetAuthMe"
+	GetConfigurationOperation      OperationName = "GetConfiguration"
+	GetInstanceOperation           OperationName = "GetInstance"
+	ListInstanceTypesOperation     OperationName = "ListInstanceTypes"
+	ListInstancesOperation         OperationName = "ListInstances"
+	ListVolumesOperation           OperationName = "ListVolumes"
+	LoginAnonymousOperation        OperationName = "LoginAnonymous"
+	LogoutOperation                OperationName = "Logout"
+	OidcAuthorizeOperation         OperationName = "OidcAuthorize"
+	OidcCallbackOperation          OperationName = "OidcCallback"
+	ReloadInstanceTypesOperation   OperationName = "ReloadInstanceTypes"
+	StartInstanceOperation         OperationName = "StartInstance"
+	StopInstanceOperation          OperationName = "StopInstance"
+	ValidateInstanceTypesOperation OperationName = "ValidateInstanceTypes"
 )
@@ -61,9 +61,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				break
 			}
 			switch elem[0] {
-			case 'a': // Prefix: "auth/"
+			case 'a': // Prefix: "a"
 
-				if l := len("auth/"); len(elem) >= l && elem[0:l] == "auth/" {
+				if l := len("a"); len(elem) >= l && elem[0:l] == "a" {
 					elem = elem[l:]
 				} else {
 					break
@@ -73,69 +73,63 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 					break
 				}
 				switch elem[0] {
-				case 'a': // Prefix: "anonymous"
+				case 'd': // Prefix: "dmin/instance-types/"
 
-					if l := len("anonymous"); len(elem) >= l && elem[0:l] == "anonymous" {
+					if l := len("dmin/instance-types/"); len(elem) >= l && elem[0:l] == "dmin/instance-types/" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
 					if len(elem) == 0 {
-						// Leaf node.
-						switch r.Method {
-						case "POST":
-							s.handleLoginAnonymousRequest([0]string{}, elemIsEscaped, w, r)
-						default:
-							s.notAllowed(w, r, "POST")
-						}
-
-						return
+						break
 					}
+					switch elem[0] {
+					case 'r': // Prefix: "reload"
 
-				case 'l': // Prefix: "logout"
+						if l := len("reload"); len(elem) >= l && elem[0:l] == "reload" {
+							elem = elem[l:]
+						} else {
+							break
+						}
 
-					if l := len("logout"); len(elem) >= l && elem[0:l] == "logout" {
-						elem = elem[l:]
-					} else {
-						break
-					}
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handleReloadInstanceTypesRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
 
-					if len(elem) == 0 {
-						// Leaf node.
-						switch r.Method {
-						case "POST":
-							s.handleLogoutRequest([0]string{}, elemIsEscaped, w, r)
-						default:
-							s.notAllowed(w, r, "POST")
+							return
 						}
 
-						return
-					}
+					case 'v': // Prefix: "validation"
 
-				case 'm': // Prefix: "me"
+						if l := len("validation"); len(elem) >= l && elem[0:l] == "validation" {
+							elem = elem[l:]
+						} else {
+							break
+						}
 
-					if l := len("me"); len(elem) >= l && elem[0:l] == "me" {
-						elem = elem[l:]
-					} else {
-						break
-					}
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "GET":
+								s.handleValidateInstanceTypesRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "GET")
+							}
 
-					if len(elem) == 0 {
-						// Leaf node.
-						switch r.Method {
-						case "GET":
-							s.handleGetAuthMeRequest([0]string{}, elemIsEscaped, w, r)
-						default:
-							s.notAllowed(w, r, "GET")
+							return
 						}
 
-						return
 					}
 
-				case 'o': // Prefix: "oidc/"
+				case 'u': // Prefix: "uth/"
 
-					if l := len("oidc/"); len(elem) >= l && elem[0:l] == "oidc/" {
+					if l := len("uth/"); len(elem) >= l && elem[0:l] == "uth/" {
 						elem = elem[l:]
 					} else {
 						break
@@ -145,9 +139,9 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						break
 					}
 					switch elem[0] {
-					case 'a': // Prefix: "authorize"
+					case 'a': // Prefix: "anonymous"
 
-						if l := len("authorize"); len(elem) >= l && elem[0:l] == "authorize" {
+						if l := len("anonymous"); len(elem) >= l && elem[0:l] == "anonymous" {
 							elem = elem[l:]
 						} else {
 							break
@@ -156,18 +150,38 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						if len(elem) == 0 {
 							// Leaf node.
 							switch r.Method {
-							case "GET":
-								s.handleOidcAuthorizeRequest([0]string{}, elemIsEscaped, w, r)
+							case "POST":
+								s.handleLoginAnonymousRequest([0]string{}, elemIsEscaped, w, r)
 							default:
-								s.notAllowed(w, r, "GET")
+								s.notAllowed(w, r, "POST")
 							}
 
 							return
 						}
 
-					case 'c': // Prefix: "callback"
+					case 'l': // Prefix: "logout"
 
-						if l := len("callback"); len(elem) >= l && elem[0:l] == "callback" {
+						if l := len("logout"); len(elem) >= l && elem[0:l] == "logout" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch r.Method {
+							case "POST":
+								s.handleLogoutRequest([0]string{}, elemIsEscaped, w, r)
+							default:
+								s.notAllowed(w, r, "POST")
+							}
+
+							return
+						}
+
+					case 'm': // Prefix: "me"
+
+						if l := len("me"); len(elem) >= l && elem[0:l] == "me" {
 							elem = elem[l:]
 						} else {
 							break
@@ -177,7 +191,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 							// Leaf node.
 							switch r.Method {
 							case "GET":
-								s.handleOidcCallbackRequest([0]string{}, elemIsEscaped, w, r)
+								s.handleGetAuthMeRequest([0]string{}, elemIsEscaped, w, r)
 							default:
 								s.notAllowed(w, r, "GET")
 							}
@@ -185,6 +199,60 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 							return
 						}
 
+					case 'o': // Prefix: "oidc/"
+
+						if l := len("oidc/"); len(elem) >= l && elem[0:l] == "oidc/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							break
+						}
+						switch elem[0] {
+						case 'a': // Prefix: "authorize"
+
+							if l := len("authorize"); len(elem) >= l && elem[0:l] == "authorize" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch r.Method {
+								case "GET":
+									s.handleOidcAuthorizeRequest([0]string{}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "GET")
+								}
+
+								return
+							}
+
+						case 'c': // Prefix: "callback"
+
+							if l := len("callback"); len(elem) >= l && elem[0:l] == "callback" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch r.Method {
+								case "GET":
+									s.handleOidcCallbackRequest([0]string{}, elemIsEscaped, w, r)
+								default:
+									s.notAllowed(w, r, "GET")
+								}
+
+								return
+							}
+
+						}
+
 					}
 
 				}
@@ -271,28 +339,179 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 						}
 
 						// Param: "instanceId"
-						// Leaf parameter, slashes are prohibited
+						// Match until "/"
 						idx := strings.IndexByte(elem, '/')
-						if idx >= 0 {
-							break
+						if idx < 0 {
+							idx = len(elem)
 						}
-						args[0] = elem
-						elem = ""
+						args[0] = elem[:idx]
+						elem = elem[idx:]
 
 						if len(elem) == 0 {
-							// Leaf node.
 							switch r.Method {
 							case "DELETE":
 								s.handleDeleteInstanceRequest([1]string{
 									args[0],
 								}, elemIsEscaped, w, r)
+							case "GET":
+								s.handleGetInstanceRequest([1]string{
+									args[0],
+								}, elemIsEscaped, w, r)
 							default:
-								s.notAllowed(w, r, "DELETE")
+								s.notAllowed(w, r, "DELETE,GET")
 							}
 
 							return
 						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								break
+							}
+							switch elem[0] {
+							case 'e': // Prefix: "extend"
+
+								if l := len("extend"); len(elem) >= l && elem[0:l] == "extend" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch r.Method {
+									case "POST":
+										s.handleExtendInstanceRequest([1]string{
+											args[0],
+										}, elemIsEscaped, w, r)
+									default:
+										s.notAllowed(w, r, "POST")
+									}
+
+									return
+								}
+
+							case 's': // Prefix: "st"
+
+								if l := len("st"); len(elem) >= l && elem[0:l] == "st" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									break
+								}
+								switch elem[0] {
+								case 'a': // Prefix: "art"
+
+									if l := len("art"); len(elem) >= l && elem[0:l] == "art" {
+										elem = elem[l:]
+									} else {
+										break
+									}
+
+									if len(elem) == 0 {
+										// Leaf node.
+										switch r.Method {
+										case "POST":
+											s.handleStartInstanceRequest([1]string{
+												args[0],
+											}, elemIsEscaped, w, r)
+										default:
+											s.notAllowed(w, r, "POST")
+										}
+
+										return
+									}
+
+								case 'o': // Prefix: "op"
+
+									if l := len("op"); len(elem) >= l && elem[0:l] == "op" {
+										elem = elem[l:]
+									} else {
+										break
+									}
+
+									if len(elem) == 0 {
+										// Leaf node.
+										switch r.Method {
+										case "POST":
+											s.handleStopInstanceRequest([1]string{
+												args[0],
+											}, elemIsEscaped, w, r)
+										default:
+											s.notAllowed(w, r, "POST")
+										}
+
+										return
+									}
+
+								}
+
+							}
+
+						}
+
+					}
+
+				}
+
+			case 'v': // Prefix: "volumes"
+
+				if l := len("volumes"); len(elem) >= l && elem[0:l] == "volumes" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					switch r.Method {
+					case "GET":
+						s.handleListVolumesRequest([0]string{}, elemIsEscaped, w, r)
+					default:
+						s.notAllowed(w, r, "GET")
+					}
+
+					return
+				}
+				switch elem[0] {
+				case '/': // Prefix: "/"
+
+					if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "volumeId"
+					// Leaf parameter, slashes are prohibited
+					idx := strings.IndexByte(elem, '/')
+					if idx >= 0 {
+						break
+					}
+					args[0] = elem
+					elem = ""
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "DELETE":
+							s.handleDeleteVolumeRequest([1]string{
+								args[0],
+							}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "DELETE")
+						}
 
+						return
 					}
 
 				}
@@ -397,9 +616,9 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 				break
 			}
 			switch elem[0] {
-			case 'a': // Prefix: "auth/"
+			case 'a': // Prefix: "a"
 
-				if l := len("auth/"); len(elem) >= l && elem[0:l] == "auth/" {
+				if l := len("a"); len(elem) >= l && elem[0:l] == "a" {
 					elem = elem[l:]
 				} else {
 					break
@@ -409,84 +628,73 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 					break
 				}
 				switch elem[0] {
-				case 'a': // Prefix: "anonymous"
+				case 'd': // Prefix: "dmin/instance-types/"
 
-					if l := len("anonymous"); len(elem) >= l && elem[0:l] == "anonymous" {
+					if l := len("dmin/instance-types/"); len(elem) >= l && elem[0:l] == "dmin/instance-types/" {
 						elem = elem[l:]
 					} else {
 						break
 					}
 
 					if len(elem) == 0 {
-						// Leaf node.
-						switch method {
-						case "POST":
-							r.name = LoginAnonymousOperation
-							r.summary = "Login anonymously (creates session only)"
-							r.operationID = "loginAnonymous"
-							r.operationGroup = ""
-							r.pathPattern = "/auth/anonymous"
-							r.args = args
-							r.count = 0
-							return r, true
-						default:
-							return
-						}
-					}
-
-				case 'l': // Prefix: "logout"
-
-					if l := len("logout"); len(elem) >= l && elem[0:l] == "logout" {
-						elem = elem[l:]
-					} else {
 						break
 					}
+					switch elem[0] {
+					case 'r': // Prefix: "reload"
 
-					if len(elem) == 0 {
-						// Leaf node.
-						switch method {
-						case "POST":
-							r.name = LogoutOperation
-							r.summary = "Logout"
-							r.operationID = "logout"
-							r.operationGroup = ""
-							r.pathPattern = "/auth/logout"
-							r.args = args
-							r.count = 0
-							return r, true
-						default:
-							return
+						if l := len("reload"); len(elem) >= l && elem[0:l] == "reload" {
+							elem = elem[l:]
+						} else {
+							break
 						}
-					}
 
-				case 'm': // Prefix: "me"
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = ReloadInstanceTypesOperation
+								r.summary = "Reload instance types"
+								r.operationID = "reloadInstanceTypes"
+								r.operationGroup = ""
+								r.pathPattern = "/admin/instance-types/reload"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
 
-					if l := len("me"); len(elem) >= l && elem[0:l] == "me" {
-						elem = elem[l:]
-					} else {
-						break
-					}
+					case 'v': // Prefix: "validation"
 
-					if len(elem) == 0 {
-						// Leaf node.
-						switch method {
-						case "GET":
-							r.name = GetAuthMeOperation
-							r.summary = "Get current user status"
-							r.operationID = "getAuthMe"
-							r.operationGroup = ""
-							r.pathPattern = "/auth/me"
-							r.args = args
-							r.count = 0
-							return r, true
-						default:
-							return
+						if l := len("validation"); len(elem) >= l && elem[0:l] == "validation" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "GET":
+								r.name = ValidateInstanceTypesOperation
+								r.summary = "Validate instance types"
+								r.operationID = "validateInstanceTypes"
+								r.operationGroup = ""
+								r.pathPattern = "/admin/instance-types/validation"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
 						}
+
 					}
 
-				case 'o': // Prefix: "oidc/"
+				case 'u': // Prefix: "uth/"
 
-					if l := len("oidc/"); len(elem) >= l && elem[0:l] == "oidc/" {
+					if l := len("uth/"); len(elem) >= l && elem[0:l] == "uth/" {
 						elem = elem[l:]
 					} else {
 						break
@@ -496,9 +704,9 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						break
 					}
 					switch elem[0] {
-					case 'a': // Prefix: "authorize"
+					case 'a': // Prefix: "anonymous"
 
-						if l := len("authorize"); len(elem) >= l && elem[0:l] == "authorize" {
+						if l := len("anonymous"); len(elem) >= l && elem[0:l] == "anonymous" {
 							elem = elem[l:]
 						} else {
 							break
@@ -507,12 +715,37 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						if len(elem) == 0 {
 							// Leaf node.
 							switch method {
-							case "GET":
-								r.name = OidcAuthorizeOperation
-								r.summary = "Redirect to OIDC provider"
-								r.operationID = "oidcAuthorize"
+							case "POST":
+								r.name = LoginAnonymousOperation
+								r.summary = "Login anonymously (creates session only)"
+								r.operationID = "loginAnonymous"
+								r.operationGroup = ""
+								r.pathPattern = "/auth/anonymous"
+								r.args = args
+								r.count = 0
+								return r, true
+							default:
+								return
+							}
+						}
+
+					case 'l': // Prefix: "logout"
+
+						if l := len("logout"); len(elem) >= l && elem[0:l] == "logout" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							// Leaf node.
+							switch method {
+							case "POST":
+								r.name = LogoutOperation
+								r.summary = "Logout"
+								r.operationID = "logout"
 								r.operationGroup = ""
-								r.pathPattern = "/auth/oidc/authorize"
+								r.pathPattern = "/auth/logout"
 								r.args = args
 								r.count = 0
 								return r, true
@@ -521,9 +754,9 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 							}
 						}
 
-					case 'c': // Prefix: "callback"
+					case 'm': // Prefix: "me"
 
-						if l := len("callback"); len(elem) >= l && elem[0:l] == "callback" {
+						if l := len("me"); len(elem) >= l && elem[0:l] == "me" {
 							elem = elem[l:]
 						} else {
 							break
@@ -533,11 +766,11 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 							// Leaf node.
 							switch method {
 							case "GET":
-								r.name = OidcCallbackOperation
-								r.summary = "Process OIDC callback from IdP"
-								r.operationID = "oidcCallback"
+								r.name = GetAuthMeOperation
+								r.summary = "Get current user status"
+								r.operationID = "getAuthMe"
 								r.operationGroup = ""
-								r.pathPattern = "/auth/oidc/callback"
+								r.pathPattern = "/auth/me"
 								r.args = args
 								r.count = 0
 								return r, true
@@ -546,6 +779,70 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 							}
 						}
 
+					case 'o': // Prefix: "oidc/"
+
+						if l := len("oidc/"); len(elem) >= l && elem[0:l] == "oidc/" {
+							elem = elem[l:]
+						} else {
+							break
+						}
+
+						if len(elem) == 0 {
+							break
+						}
+						switch elem[0] {
+						case 'a': // Prefix: "authorize"
+
+							if l := len("authorize"); len(elem) >= l && elem[0:l] == "authorize" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch method {
+								case "GET":
+									r.name = OidcAuthorizeOperation
+									r.summary = "Redirect to OIDC provider"
+									r.operationID = "oidcAuthorize"
+									r.operationGroup = ""
+									r.pathPattern = "/auth/oidc/authorize"
+									r.args = args
+									r.count = 0
+									return r, true
+								default:
+									return
+								}
+							}
+
+						case 'c': // Prefix: "callback"
+
+							if l := len("callback"); len(elem) >= l && elem[0:l] == "callback" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								// Leaf node.
+								switch method {
+								case "GET":
+									r.name = OidcCallbackOperation
+									r.summary = "Process OIDC callback from IdP"
+									r.operationID = "oidcCallback"
+									r.operationGroup = ""
+									r.pathPattern = "/auth/oidc/callback"
+									r.args = args
+									r.count = 0
+									return r, true
+								default:
+									return
+								}
+							}
+
+						}
+
 					}
 
 				}
@@ -654,16 +951,15 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 						}
 
 						// Param: "instanceId"
-						// Leaf parameter, slashes are prohibited
+						// Match until "/"
 						idx := strings.IndexByte(elem, '/')
-						if idx >= 0 {
-							break
+						if idx < 0 {
+							idx = len(elem)
 						}
-						args[0] = elem
-						elem = ""
+						args[0] = elem[:idx]
+						elem = elem[idx:]
 
 						if len(elem) == 0 {
-							// Leaf node.
 							switch method {
 							case "DELETE":
 								r.name = DeleteInstanceOperation
@@ -674,11 +970,185 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 								r.args = args
 								r.count = 1
 								return r, true
+							case "GET":
+								r.name = GetInstanceOperation
+								r.summary = "Get an instance"
+								r.operationID = "getInstance"
+								r.operationGroup = ""
+								r.pathPattern = "/instances/{instanceId}"
+								r.args = args
+								r.count = 1
+								return r, true
 							default:
 								return
 							}
 						}
+						switch elem[0] {
+						case '/': // Prefix: "/"
+
+							if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+								elem = elem[l:]
+							} else {
+								break
+							}
+
+							if len(elem) == 0 {
+								break
+							}
+							switch elem[0] {
+							case 'e': // Prefix: "extend"
+
+								if l := len("extend"); len(elem) >= l && elem[0:l] == "extend" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									// Leaf node.
+									switch method {
+									case "POST":
+										r.name = ExtendInstanceOperation
+										r.summary = "Extend the lease of an instance"
+										r.operationID = "extendInstance"
+										r.operationGroup = ""
+										r.pathPattern = "/instances/{instanceId}/extend"
+										r.args = args
+										r.count = 1
+										return r, true
+									default:
+										return
+									}
+								}
+
+							case 's': // Prefix: "st"
+
+								if l := len("st"); len(elem) >= l && elem[0:l] == "st" {
+									elem = elem[l:]
+								} else {
+									break
+								}
+
+								if len(elem) == 0 {
+									break
+								}
+								switch elem[0] {
+								case 'a': // Prefix: "art"
+
+									if l := len("art"); len(elem) >= l && elem[0:l] == "art" {
+										elem = elem[l:]
+									} else {
+										break
+									}
+
+									if len(elem) == 0 {
+										// Leaf node.
+										switch method {
+										case "POST":
+											r.name = StartInstanceOperation
+											r.summary = "Start a stopped instance"
+											r.operationID = "startInstance"
+											r.operationGroup = ""
+											r.pathPattern = "/instances/{instanceId}/start"
+											r.args = args
+											r.count = 1
+											return r, true
+										default:
+											return
+										}
+									}
+
+								case 'o': // Prefix: "op"
+
+									if l := len("op"); len(elem) >= l && elem[0:l] == "op" {
+										elem = elem[l:]
+									} else {
+										break
+									}
+
+									if len(elem) == 0 {
+										// Leaf node.
+										switch method {
+										case "POST":
+											r.name = StopInstanceOperation
+											r.summary = "Stop an instance"
+											r.operationID = "stopInstance"
+											r.operationGroup = ""
+											r.pathPattern = "/instances/{instanceId}/stop"
+											r.args = args
+											r.count = 1
+											return r, true
+										default:
+											return
+										}
+									}
+
+								}
+
+							}
+
+						}
+
+					}
+
+				}
+
+			case 'v': // Prefix: "volumes"
+
+				if l := len("volumes"); len(elem) >= l && elem[0:l] == "volumes" {
+					elem = elem[l:]
+				} else {
+					break
+				}
 
+				if len(elem) == 0 {
+					switch method {
+					case "GET":
+						r.name = ListVolumesOperation
+						r.summary = "List user volumes"
+						r.operationID = "listVolumes"
+						r.operationGroup = ""
+						r.pathPattern = "/volumes"
+						r.args = args
+						r.count = 0
+						return r, true
+					default:
+						return
+					}
+				}
+				switch elem[0] {
+				case '/': // Prefix: "/"
+
+					if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "volumeId"
+					// Leaf parameter, slashes are prohibited
+					idx := strings.IndexByte(elem, '/')
+					if idx >= 0 {
+						break
+					}
+					args[0] = elem
+					elem = ""
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "DELETE":
+							r.name = DeleteVolumeOperation
+							r.summary = "Delete a volume"
+							r.operationID = "deleteVolume"
+							r.operationGroup = ""
+							r.pathPattern = "/volumes/{volumeId}"
+							r.args = args
+							r.count = 1
+							return r, true
+						default:
+							return
+						}
 					}
 
 				}
@@ -3,7 +3,10 @@
 package hakoniwa
 
 import (
+	"time"
+
 	"github.com/go-faster/errors"
+	"github.com/go-faster/jx"
 )
 
 // Ref: #/components/schemas/AuthStatus
@@ -41,6 +44,11 @@ type Configuration struct {
 	OidcName OptString `json:"oidc_name"`
 	// Whether to automatically log in if only one auth method is available.
 	AuthAutoLogin bool `json:"auth_auto_login"`
+	// How instances are opened. With `cookie`, the dashboard host serves the instance selected by the
+	// `hakoniwa_instance_id` cookie. With `subdomain`, each instance has its own host, opened through
+	// `/_hakoniwa/open/{instanceId}` on the dashboard host. With `path`, the dashboard host serves each
+	// instance under `/w/{instanceId}/`.
+	InstanceRouting ConfigurationInstanceRouting `json:"instance_routing"`
 }
 
 // GetTitle returns the value of Title.
@@ -83,6 +91,11 @@ func (s *Configuration) GetAuthAutoLogin() bool {
 	return s.AuthAutoLogin
 }
 
+// GetInstanceRouting returns the value of InstanceRouting.
+func (s *Configuration) GetInstanceRouting() ConfigurationInstanceRouting {
+	return s.InstanceRouting
+}
+
 // SetTitle sets the value of Title.
 func (s *Configuration) SetTitle(val string) {
 	s.Title = val
@@ -123,8 +136,64 @@ func (s *Configuration) SetAuthAutoLogin(val bool) {
 	s.AuthAutoLogin = val
 }
 
-// CreateInstanceBadRequest is response for CreateInstance operation.
-type CreateInstanceBadRequest struct{}
+// SetInstanceRouting sets the value of InstanceRouting.
+func (s *Configuration) SetInstanceRouting(val ConfigurationInstanceRouting) {
+	s.InstanceRouting = val
+}
+
+// How instances are opened. With `cookie`, the dashboard host serves the instance selected by the
+// `hakoniwa_instance_id` cookie. With `subdomain`, each instance has its own host, opened through
+// `/_hakoniwa/open/{instanceId}` on the dashboard host. With `path`, the dashboard host serves each
+// instance under `/w/{instanceId}/`.
+type ConfigurationInstanceRouting string
+
+const (
+	ConfigurationInstanceRoutingCookie    ConfigurationInstanceRouting = "cookie"
+	ConfigurationInstanceRoutingSubdomain ConfigurationInstanceRouting = "subdomain"
+	ConfigurationInstanceRoutingPath      ConfigurationInstanceRouting = "path"
+)
+
+// AllValues returns all ConfigurationInstanceRouting values.
+func (ConfigurationInstanceRouting) AllValues() []ConfigurationInstanceRouting {
+	return []ConfigurationInstanceRouting{
+		ConfigurationInstanceRoutingCookie,
+		ConfigurationInstanceRoutingSubdomain,
+		ConfigurationInstanceRoutingPath,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s ConfigurationInstanceRouting) MarshalText() ([]byte, error) {
+	switch s {
+	case ConfigurationInstanceRoutingCookie:
+		return []byte(s), nil
+	case ConfigurationInstanceRoutingSubdomain:
+		return []byte(s), nil
+	case ConfigurationInstanceRoutingPath:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *ConfigurationInstanceRouting) UnmarshalText(data []byte) error {
+	switch ConfigurationInstanceRouting(data) {
+	case ConfigurationInstanceRoutingCookie:
+		*s = ConfigurationInstanceRoutingCookie
+		return nil
+	case ConfigurationInstanceRoutingSubdomain:
+		*s = ConfigurationInstanceRoutingSubdomain
+		return nil
+	case ConfigurationInstanceRoutingPath:
+		*s = ConfigurationInstanceRoutingPath
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+type CreateInstanceBadRequest Error
 
 func (*CreateInstanceBadRequest) createInstanceRes() {}
 
@@ -132,6 +201,8 @@ func (*CreateInstanceBadRequest) createInstanceRes() {}
 type CreateInstanceRequest struct {
 	// Type of the instance to create.
 	Type string `json:"type"`
+	// Values for the parameters declared by the instance type. Omitted parameters use their default.
+	Parameters OptCreateInstanceRequestParameters `json:"parameters"`
 }
 
 // GetType returns the value of Type.
@@ -139,13 +210,34 @@ func (s *CreateInstanceRequest) GetType() string {
 	return s.Type
 }
 
+// GetParameters returns the value of Parameters.
+func (s *CreateInstanceRequest) GetParameters() OptCreateInstanceRequestParameters {
+	return s.Parameters
+}
+
 // SetType sets the value of Type.
 func (s *CreateInstanceRequest) SetType(val string) {
 	s.Type = val
 }
 
-// CreateInstanceServiceUnavailable is response for CreateInstance operation.
-type CreateInstanceServiceUnavailable struct{}
+// SetParameters sets the value of Parameters.
+func (s *CreateInstanceRequest) SetParameters(val OptCreateInstanceRequestParameters) {
+	s.Parameters = val
+}
+
+// Values for the parameters declared by the instance type. Omitted parameters use their default.
+type CreateInstanceRequestParameters map[string]jx.Raw
+
+func (s *CreateInstanceRequestParameters) init() CreateInstanceRequestParameters {
+	m := *s
+	if m == nil {
+		m = map[string]jx.Raw{}
+		*s = m
+	}
+	return m
+}
+
+type CreateInstanceServiceUnavailable Error
 
 func (*CreateInstanceServiceUnavailable) createInstanceRes() {}
 
@@ -159,11 +251,59 @@ type DeleteInstanceNotFound struct{}
 
 func (*DeleteInstanceNotFound) deleteInstanceRes() {}
 
+// DeleteVolumeConflict is response for DeleteVolume operation.
+type DeleteVolumeConflict struct{}
+
+func (*DeleteVolumeConflict) deleteVolumeRes() {}
+
+// DeleteVolumeNoContent is response for DeleteVolume operation.
+type DeleteVolumeNoContent struct{}
+
+func (*DeleteVolumeNoContent) deleteVolumeRes() {}
+
+// DeleteVolumeNotFound is response for DeleteVolume operation.
+type DeleteVolumeNotFound struct{}
+
+func (*DeleteVolumeNotFound) deleteVolumeRes() {}
+
+// Ref: #/components/schemas/Error
+type Error struct {
+	Message string `json:"message"`
+}
+
+// GetMessage returns the value of Message.
+func (s *Error) GetMessage() string {
+	return s.Message
+}
+
+// SetMessage sets the value of Message.
+func (s *Error) SetMessage(val string) {
+	s.Message = val
+}
+
+func (*Error) reloadInstanceTypesRes() {}
+func (*Error) startInstanceRes()       {}
+
+// ExtendInstanceConflict is response for ExtendInstance operation.
+type ExtendInstanceConflict struct{}
+
+func (*ExtendInstanceConflict) extendInstanceRes() {}
+
+// ExtendInstanceNotFound is response for ExtendInstance operation.
+type ExtendInstanceNotFound struct{}
+
+func (*ExtendInstanceNotFound) extendInstanceRes() {}
+
 // GetAuthMeUnauthorized is response for GetAuthMe operation.
 type GetAuthMeUnauthorized struct{}
 
 func (*GetAuthMeUnauthorized) getAuthMeRes() {}
 
+// GetInstanceNotFound is response for GetInstance operation.
+type GetInstanceNotFound struct{}
+
+func (*GetInstanceNotFound) getInstanceRes() {}
+
 // Ref: #/components/schemas/Instance
 type Instance struct {
 	// Unique instance ID.
@@ -171,9 +311,31 @@ type Instance struct {
 	// Display name of the instance.
 	Name string `json:"name"`
 	// Type of the instance.
-	Type   string         `json:"type"`
+	Type string `json:"type"`
+	// Pending: waiting for the pod to be scheduled. starting: scheduled and starting its containers.
+	// failed: the pod can't start, e.g. its image can't be pulled; it returns to starting or running if
+	// the problem is fixed.
 	Status InstanceStatus `json:"status"`
-	PodIP  OptString      `json:"pod_ip"`
+	// Machine-readable reason for a pending, starting or failed status, taken from Kubernetes (e.g.
+	// Unschedulable, ImagePullBackOff, CrashLoopBackOff, FailedMount).
+	StatusReason OptString `json:"status_reason"`
+	// Human-readable explanation of status_reason.
+	StatusMessage OptString       `json:"status_message"`
+	StartupPhase  OptStartupPhase `json:"startup_phase"`
+	// The startup phases the instance's pod has reached so far, in order, with when it entered each of
+	// them. Absent while the pod is not scheduled.
+	StartupPhases []StartupPhaseTime `json:"startup_phases"`
+	// 1-based position in the waiting queue. Only set while the instance is queued.
+	QueuePosition OptInt    `json:"queue_position"`
+	PodIP         OptString `json:"pod_ip"`
+	// Why the instance was stopped automatically.
+	ReapReason OptInstanceReapReason `json:"reap_reason"`
+	// When the instance will be reaped unless its lease is extended. Absent if no reaping is pending.
+	ExpiresAt OptDateTime `json:"expires_at"`
+	// When the lease extended by the user ends.
+	ExtendedUntil OptDateTime `json:"extended_until"`
+	// Template parameters the instance was created with.
+	Parameters OptInstanceParameters `json:"parameters"`
 }
 
 // GetID returns the value of ID.
@@ -196,11 +358,56 @@ func (s *Instance) GetStatus() InstanceStatus {
 	return s.Status
 }
 
+// GetStatusReason returns the value of StatusReason.
+func (s *Instance) GetStatusReason() OptString {
+	return s.StatusReason
+}
+
+// GetStatusMessage returns the value of StatusMessage.
+func (s *Instance) GetStatusMessage() OptString {
+	return s.StatusMessage
+}
+
+// GetStartupPhase returns the value of StartupPhase.
+func (s *Instance) GetStartupPhase() OptStartupPhase {
+	return s.StartupPhase
+}
+
+// GetStartupPhases returns the value of StartupPhases.
+func (s *Instance) GetStartupPhases() []StartupPhaseTime {
+	return s.StartupPhases
+}
+
+// GetQueuePosition returns the value of QueuePosition.
+func (s *Instance) GetQueuePosition() OptInt {
+	return s.QueuePosition
+}
+
 // GetPodIP returns the value of PodIP.
 func (s *Instance) GetPodIP() OptString {
 	return s.PodIP
 }
 
+// GetReapReason returns the value of ReapReason.
+func (s *Instance) GetReapReason() OptInstanceReapReason {
+	return s.ReapReason
+}
+
+// GetExpiresAt returns the value of ExpiresAt.
+func (s *Instance) GetExpiresAt() OptDateTime {
+	return s.ExpiresAt
+}
+
+// GetExtendedUntil returns the value of ExtendedUntil.
+func (s *Instance) GetExtendedUntil() OptDateTime {
+	return s.ExtendedUntil
+}
+
+// GetParameters returns the value of Parameters.
+func (s *Instance) GetParameters() OptInstanceParameters {
+	return s.Parameters
+}
+
 // SetID sets the value of ID.
 func (s *Instance) SetID(val string) {
 	s.ID = val
@@ -221,27 +428,316 @@ func (s *Instance) SetStatus(val InstanceStatus) {
 	s.Status = val
 }
 
+// SetStatusReason sets the value of StatusReason.
+func (s *Instance) SetStatusReason(val OptString) {
+	s.StatusReason = val
+}
+
+// SetStatusMessage sets the value of StatusMessage.
+func (s *Instance) SetStatusMessage(val OptString) {
+	s.StatusMessage = val
+}
+
+// SetStartupPhase sets the value of StartupPhase.
+func (s *Instance) SetStartupPhase(val OptStartupPhase) {
+	s.StartupPhase = val
+}
+
+// SetStartupPhases sets the value of StartupPhases.
+func (s *Instance) SetStartupPhases(val []StartupPhaseTime) {
+	s.StartupPhases = val
+}
+
+// SetQueuePosition sets the value of QueuePosition.
+func (s *Instance) SetQueuePosition(val OptInt) {
+	s.QueuePosition = val
+}
+
 // SetPodIP sets the value of PodIP.
 func (s *Instance) SetPodIP(val OptString) {
 	s.PodIP = val
 }
 
+// SetReapReason sets the value of ReapReason.
+func (s *Instance) SetReapReason(val OptInstanceReapReason) {
+	s.ReapReason = val
+}
+
+// SetExpiresAt sets the value of ExpiresAt.
+func (s *Instance) SetExpiresAt(val OptDateTime) {
+	s.ExpiresAt = val
+}
+
+// SetExtendedUntil sets the value of ExtendedUntil.
+func (s *Instance) SetExtendedUntil(val OptDateTime) {
+	s.ExtendedUntil = val
+}
+
+// SetParameters sets the value of Parameters.
+func (s *Instance) SetParameters(val OptInstanceParameters) {
+	s.Parameters = val
+}
+
 func (*Instance) createInstanceRes() {}
+func (*Instance) extendInstanceRes() {}
+func (*Instance) getInstanceRes()    {}
+func (*Instance) startInstanceRes()  {}
+func (*Instance) stopInstanceRes()   {}
+
+// A user-supplied option the instance template is rendered with.
+// Ref: #/components/schemas/InstanceParameter
+type InstanceParameter struct {
+	Name        string    `json:"name"`
+	DisplayName OptString `json:"display_name"`
+	Description OptString `json:"description"`
+	// Value type. quantity is a Kubernetes resource quantity (e.g. 4Gi); array is a list of strings.
+	Type InstanceParameterType `json:"type"`
+	// Default value. Absent if the parameter is required.
+	Default jx.Raw `json:"default"`
+	// Allowed values. For arrays, the allowed items.
+	Enum []string `json:"enum"`
+	// Lower bound for integer and quantity parameters.
+	Minimum OptString `json:"minimum"`
+	// Upper bound for integer and quantity parameters.
+	Maximum OptString `json:"maximum"`
+}
+
+// GetName returns the value of Name.
+func (s *InstanceParameter) GetName() string {
+	return s.Name
+}
+
+// GetDisplayName returns the value of DisplayName.
+func (s *InstanceParameter) GetDisplayName() OptString {
+	return s.DisplayName
+}
+
+// GetDescription returns the value of Description.
+func (s *InstanceParameter) GetDescription() OptString {
+	return s.Description
+}
+
+// GetType returns the value of Type.
+func (s *InstanceParameter) GetType() InstanceParameterType {
+	return s.Type
+}
+
+// GetDefault returns the value of Default.
+func (s *InstanceParameter) GetDefault() jx.Raw {
+	return s.Default
+}
+
+// GetEnum returns the value of Enum.
+func (s *InstanceParameter) GetEnum() []string {
+	return s.Enum
+}
+
+// GetMinimum returns the value of Minimum.
+func (s *InstanceParameter) GetMinimum() OptString {
+	return s.Minimum
+}
+
+// GetMaximum returns the value of Maximum.
+func (s *InstanceParameter) GetMaximum() OptString {
+	return s.Maximum
+}
+
+// SetName sets the value of Name.
+func (s *InstanceParameter) SetName(val string) {
+	s.Name = val
+}
 
+// SetDisplayName sets the value of DisplayName.
+func (s *InstanceParameter) SetDisplayName(val OptString) {
+	s.DisplayName = val
+}
+
+// SetDescription sets the value of Description.
+func (s *InstanceParameter) SetDescription(val OptString) {
+	s.Description = val
+}
+
+// SetType sets the value of Type.
+func (s *InstanceParameter) SetType(val InstanceParameterType) {
+	s.Type = val
+}
+
+// SetDefault sets the value of Default.
+func (s *InstanceParameter) SetDefault(val jx.Raw) {
+	s.Default = val
+}
+
+// SetEnum sets the value of Enum.
+func (s *InstanceParameter) SetEnum(val []string) {
+	s.Enum = val
+}
+
+// SetMinimum sets the value of Minimum.
+func (s *InstanceParameter) SetMinimum(val OptString) {
+	s.Minimum = val
+}
+
+// SetMaximum sets the value of Maximum.
+func (s *InstanceParameter) SetMaximum(val OptString) {
+	s.Maximum = val
+}
+
+// Value type. quantity is a Kubernetes resource quantity (e.g. 4Gi); array is a list of strings.
+type InstanceParameterType string
+
+const (
+	InstanceParameterTypeString   InstanceParameterType = "string"
+	InstanceParameterTypeInteger  InstanceParameterType = "integer"
+	InstanceParameterTypeBoolean  InstanceParameterType = "boolean"
+	InstanceParameterTypeQuantity InstanceParameterType = "quantity"
+	InstanceParameterTypeArray    InstanceParameterType = "array"
+)
+
+// AllValues returns all InstanceParameterType values.
+func (InstanceParameterType) AllValues() []InstanceParameterType {
+	return []InstanceParameterType{
+		InstanceParameterTypeString,
+		InstanceParameterTypeInteger,
+		InstanceParameterTypeBoolean,
+		InstanceParameterTypeQuantity,
+		InstanceParameterTypeArray,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s InstanceParameterType) MarshalText() ([]byte, error) {
+	switch s {
+	case InstanceParameterTypeString:
+		return []byte(s), nil
+	case InstanceParameterTypeInteger:
+		return []byte(s), nil
+	case InstanceParameterTypeBoolean:
+		return []byte(s), nil
+	case InstanceParameterTypeQuantity:
+		return []byte(s), nil
+	case InstanceParameterTypeArray:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *InstanceParameterType) UnmarshalText(data []byte) error {
+	switch InstanceParameterType(data) {
+	case InstanceParameterTypeString:
+		*s = InstanceParameterTypeString
+		return nil
+	case InstanceParameterTypeInteger:
+		*s = InstanceParameterTypeInteger
+		return nil
+	case InstanceParameterTypeBoolean:
+		*s = InstanceParameterTypeBoolean
+		return nil
+	case InstanceParameterTypeQuantity:
+		*s = InstanceParameterTypeQuantity
+		return nil
+	case InstanceParameterTypeArray:
+		*s = InstanceParameterTypeArray
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Template parameters the instance was created with.
+type InstanceParameters map[string]jx.Raw
+
+func (s *InstanceParameters) init() InstanceParameters {
+	m := *s
+	if m == nil {
+		m = map[string]jx.Raw{}
+		*s = m
+	}
+	return m
+}
+
+// Why the instance was stopped automatically.
+type InstanceReapReason string
+
+const (
+	InstanceReapReasonIdleTimeout InstanceReapReason = "idle_timeout"
+	InstanceReapReasonMaxLifetime InstanceReapReason = "max_lifetime"
+	InstanceReapReasonOffHours    InstanceReapReason = "off_hours"
+	InstanceReapReasonOverQuota   InstanceReapReason = "over_quota"
+)
+
+// AllValues returns all InstanceReapReason values.
+func (InstanceReapReason) AllValues() []InstanceReapReason {
+	return []InstanceReapReason{
+		InstanceReapReasonIdleTimeout,
+		InstanceReapReasonMaxLifetime,
+		InstanceReapReasonOffHours,
+		InstanceReapReasonOverQuota,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s InstanceReapReason) MarshalText() ([]byte, error) {
+	switch s {
+	case InstanceReapReasonIdleTimeout:
+		return []byte(s), nil
+	case InstanceReapReasonMaxLifetime:
+		return []byte(s), nil
+	case InstanceReapReasonOffHours:
+		return []byte(s), nil
+	case InstanceReapReasonOverQuota:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *InstanceReapReason) UnmarshalText(data []byte) error {
+	switch InstanceReapReason(data) {
+	case InstanceReapReasonIdleTimeout:
+		*s = InstanceReapReasonIdleTimeout
+		return nil
+	case InstanceReapReasonMaxLifetime:
+		*s = InstanceReapReasonMaxLifetime
+		return nil
+	case InstanceReapReasonOffHours:
+		*s = InstanceReapReasonOffHours
+		return nil
+	case InstanceReapReasonOverQuota:
+		*s = InstanceReapReasonOverQuota
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Pending: waiting for the pod to be scheduled. starting: scheduled and starting its containers.
+// failed: the pod can't start, e.g. its image can't be pulled; it returns to starting or running if
+// the problem is fixed.
 type InstanceStatus string
 
 const (
 	InstanceStatusPending     InstanceStatus = "pending"
+	InstanceStatusStarting    InstanceStatus = "starting"
 	InstanceStatusRunning     InstanceStatus = "running"
+	InstanceStatusFailed      InstanceStatus = "failed"
 	InstanceStatusTerminating InstanceStatus = "terminating"
+	InstanceStatusStopped     InstanceStatus = "stopped"
+	InstanceStatusQueued      InstanceStatus = "queued"
 )
 
 // AllValues returns all InstanceStatus values.
 func (InstanceStatus) AllValues() []InstanceStatus {
 	return []InstanceStatus{
 		InstanceStatusPending,
+		InstanceStatusStarting,
 		InstanceStatusRunning,
+		InstanceStatusFailed,
 		InstanceStatusTerminating,
+		InstanceStatusStopped,
+		InstanceStatusQueued,
 	}
 }
 
@@ -250,10 +746,18 @@ func (s InstanceStatus) MarshalText() ([]byte, error) {
 	switch s {
 	case InstanceStatusPending:
 		return []byte(s), nil
+	case InstanceStatusStarting:
+		return []byte(s), nil
 	case InstanceStatusRunning:
 		return []byte(s), nil
+	case InstanceStatusFailed:
+		return []byte(s), nil
 	case InstanceStatusTerminating:
 		return []byte(s), nil
+	case InstanceStatusStopped:
+		return []byte(s), nil
+	case InstanceStatusQueued:
+		return []byte(s), nil
 	default:
 		return nil, errors.Errorf("invalid value: %q", s)
 	}
@@ -265,12 +769,24 @@ func (s *InstanceStatus) UnmarshalText(data []byte) error {
 	case InstanceStatusPending:
 		*s = InstanceStatusPending
 		return nil
+	case InstanceStatusStarting:
+		*s = InstanceStatusStarting
+		return nil
 	case InstanceStatusRunning:
 		*s = InstanceStatusRunning
 		return nil
+	case InstanceStatusFailed:
+		*s = InstanceStatusFailed
+		return nil
 	case InstanceStatusTerminating:
 		*s = InstanceStatusTerminating
 		return nil
+	case InstanceStatusStopped:
+		*s = InstanceStatusStopped
+		return nil
+	case InstanceStatusQueued:
+		*s = InstanceStatusQueued
+		return nil
 	default:
 		return errors.Errorf("invalid value: %q", data)
 	}
@@ -285,7 +801,8 @@ type InstanceType struct {
 	// Description of the instance type.
 	Description OptString `json:"description"`
 	// URL to the logo of the instance type.
-	LogoURL OptString `json:"logo_url"`
+	LogoURL    OptString           `json:"logo_url"`
+	Parameters []InstanceParameter `json:"parameters"`
 }
 
 // GetID returns the value of ID.
@@ -308,6 +825,11 @@ func (s *InstanceType) GetLogoURL() OptString {
 	return s.LogoURL
 }
 
+// GetParameters returns the value of Parameters.
+func (s *InstanceType) GetParameters() []InstanceParameter {
+	return s.Parameters
+}
+
 // SetID sets the value of ID.
 func (s *InstanceType) SetID(val string) {
 	s.ID = val
@@ -328,6 +850,77 @@ func (s *InstanceType) SetLogoURL(val OptString) {
 	s.LogoURL = val
 }
 
+// SetParameters sets the value of Parameters.
+func (s *InstanceType) SetParameters(val []InstanceParameter) {
+	s.Parameters = val
+}
+
+// Ref: #/components/schemas/InstanceTypeReloadResult
+type InstanceTypeReloadResult struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"`
+}
+
+// GetAdded returns the value of Added.
+func (s *InstanceTypeReloadResult) GetAdded() []string {
+	return s.Added
+}
+
+// GetRemoved returns the value of Removed.
+func (s *InstanceTypeReloadResult) GetRemoved() []string {
+	return s.Removed
+}
+
+// GetChanged returns the value of Changed.
+func (s *InstanceTypeReloadResult) GetChanged() []string {
+	return s.Changed
+}
+
+// SetAdded sets the value of Added.
+func (s *InstanceTypeReloadResult) SetAdded(val []string) {
+	s.Added = val
+}
+
+// SetRemoved sets the value of Removed.
+func (s *InstanceTypeReloadResult) SetRemoved(val []string) {
+	s.Removed = val
+}
+
+// SetChanged sets the value of Changed.
+func (s *InstanceTypeReloadResult) SetChanged(val []string) {
+	s.Changed = val
+}
+
+func (*InstanceTypeReloadResult) reloadInstanceTypesRes() {}
+
+// Ref: #/components/schemas/InstanceTypeValidation
+type InstanceTypeValidation struct {
+	// Instance type ID.
+	Type     string            `json:"type"`
+	Problems []TemplateProblem `json:"problems"`
+}
+
+// GetType returns the value of Type.
+func (s *InstanceTypeValidation) GetType() string {
+	return s.Type
+}
+
+// GetProblems returns the value of Problems.
+func (s *InstanceTypeValidation) GetProblems() []TemplateProblem {
+	return s.Problems
+}
+
+// SetType sets the value of Type.
+func (s *InstanceTypeValidation) SetType(val string) {
+	s.Type = val
+}
+
+// SetProblems sets the value of Problems.
+func (s *InstanceTypeValidation) SetProblems(val []TemplateProblem) {
+	s.Problems = val
+}
+
 // LogoutOK is response for Logout operation.
 type LogoutOK struct{}
 
@@ -361,21 +954,297 @@ func (s *OidcCallbackFound) SetLocation(val string) {
 	s.Location = val
 }
 
-// NewOptString returns new OptString with value set to v.
-func NewOptString(v string) OptString {
-	return OptString{
+// NewOptCreateInstanceRequestParameters returns new OptCreateInstanceRequestParameters with value set to v.
+func NewOptCreateInstanceRequestParameters(v CreateInstanceRequestParameters) OptCreateInstanceRequestParameters {
+	return OptCreateInstanceRequestParameters{
 		Value: v,
 		Set:   true,
 	}
 }
 
-// OptString is optional string.
-type OptString struct {
-	Value string
+// OptCreateInstanceRequestParameters is optional CreateInstanceRequestParameters.
+type OptCreateInstanceRequestParameters struct {
+	Value CreateInstanceRequestParameters
 	Set   bool
 }
 
-// IsSet returns true if OptString was set.
+// IsSet returns true if OptCreateInstanceRequestParameters was set.
+func (o OptCreateInstanceRequestParameters) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptCreateInstanceRequestParameters) Reset() {
+	var v CreateInstanceRequestParameters
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptCreateInstanceRequestParameters) SetTo(v CreateInstanceRequestParameters) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptCreateInstanceRequestParameters) Get() (v CreateInstanceRequestParameters, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptCreateInstanceRequestParameters) Or(d CreateInstanceRequestParameters) CreateInstanceRequestParameters {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptDateTime returns new OptDateTime with value set to v.
+func NewOptDateTime(v time.Time) OptDateTime {
+	return OptDateTime{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptDateTime is optional time.Time.
+type OptDateTime struct {
+	Value time.Time
+	Set   bool
+}
+
+// IsSet returns true if OptDateTime was set.
+func (o OptDateTime) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptDateTime) Reset() {
+	var v time.Time
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptDateTime) SetTo(v time.Time) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptDateTime) Get() (v time.Time, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptDateTime) Or(d time.Time) time.Time {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInstanceParameters returns new OptInstanceParameters with value set to v.
+func NewOptInstanceParameters(v InstanceParameters) OptInstanceParameters {
+	return OptInstanceParameters{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInstanceParameters is optional InstanceParameters.
+type OptInstanceParameters struct {
+	Value InstanceParameters
+	Set   bool
+}
+
+// IsSet returns true if OptInstanceParameters was set.
+func (o OptInstanceParameters) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInstanceParameters) Reset() {
+	var v InstanceParameters
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInstanceParameters) SetTo(v InstanceParameters) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInstanceParameters) Get() (v InstanceParameters, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInstanceParameters) Or(d InstanceParameters) InstanceParameters {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInstanceReapReason returns new OptInstanceReapReason with value set to v.
+func NewOptInstanceReapReason(v InstanceReapReason) OptInstanceReapReason {
+	return OptInstanceReapReason{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInstanceReapReason is optional InstanceReapReason.
+type OptInstanceReapReason struct {
+	Value InstanceReapReason
+	Set   bool
+}
+
+// IsSet returns true if OptInstanceReapReason was set.
+func (o OptInstanceReapReason) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInstanceReapReason) Reset() {
+	var v InstanceReapReason
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInstanceReapReason) SetTo(v InstanceReapReason) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInstanceReapReason) Get() (v InstanceReapReason, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInstanceReapReason) Or(d InstanceReapReason) InstanceReapReason {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptInt returns new OptInt with value set to v.
+func NewOptInt(v int) OptInt {
+	return OptInt{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptInt is optional int.
+type OptInt struct {
+	Value int
+	Set   bool
+}
+
+// IsSet returns true if OptInt was set.
+func (o OptInt) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptInt) Reset() {
+	var v int
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptInt) SetTo(v int) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptInt) Get() (v int, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptInt) Or(d int) int {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptStartupPhase returns new OptStartupPhase with value set to v.
+func NewOptStartupPhase(v StartupPhase) OptStartupPhase {
+	return OptStartupPhase{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptStartupPhase is optional StartupPhase.
+type OptStartupPhase struct {
+	Value StartupPhase
+	Set   bool
+}
+
+// IsSet returns true if OptStartupPhase was set.
+func (o OptStartupPhase) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptStartupPhase) Reset() {
+	var v StartupPhase
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptStartupPhase) SetTo(v StartupPhase) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptStartupPhase) Get() (v StartupPhase, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptStartupPhase) Or(d StartupPhase) StartupPhase {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
 func (o OptString) IsSet() bool { return o.Set }
 
 // Reset unsets value.
@@ -407,6 +1276,212 @@ func (o OptString) Or(d string) string {
 	return d
 }
 
+// ReloadInstanceTypesForbidden is response for ReloadInstanceTypes operation.
+type ReloadInstanceTypesForbidden struct{}
+
+func (*ReloadInstanceTypesForbidden) reloadInstanceTypesRes() {}
+
+// StartInstanceConflict is response for StartInstance operation.
+type StartInstanceConflict struct{}
+
+func (*StartInstanceConflict) startInstanceRes() {}
+
+// StartInstanceNotFound is response for StartInstance operation.
+type StartInstanceNotFound struct{}
+
+func (*StartInstanceNotFound) startInstanceRes() {}
+
+// How far the instance's pod has come in starting up. pulling_image lasts until the first container
+// starts,
+// initializing until all containers have started (init containers first), and waiting_for_readiness
+// until the pod is ready.
+// Ref: #/components/schemas/StartupPhase
+type StartupPhase string
+
+const (
+	StartupPhaseScheduled           StartupPhase = "scheduled"
+	StartupPhasePullingImage        StartupPhase = "pulling_image"
+	StartupPhaseInitializing        StartupPhase = "initializing"
+	StartupPhaseWaitingForReadiness StartupPhase = "waiting_for_readiness"
+	StartupPhaseReady               StartupPhase = "ready"
+)
+
+// AllValues returns all StartupPhase values.
+func (StartupPhase) AllValues() []StartupPhase {
+	return []StartupPhase{
+		StartupPhaseScheduled,
+		StartupPhasePullingImage,
+		StartupPhaseInitializing,
+		StartupPhaseWaitingForReadiness,
+		StartupPhaseReady,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s StartupPhase) MarshalText() ([]byte, error) {
+	switch s {
+	case StartupPhaseScheduled:
+		return []byte(s), nil
+	case StartupPhasePullingImage:
+		return []byte(s), nil
+	case StartupPhaseInitializing:
+		return []byte(s), nil
+	case StartupPhaseWaitingForReadiness:
+		return []byte(s), nil
+	case StartupPhaseReady:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *StartupPhase) UnmarshalText(data []byte) error {
+	switch StartupPhase(data) {
+	case StartupPhaseScheduled:
+		*s = StartupPhaseScheduled
+		return nil
+	case StartupPhasePullingImage:
+		*s = StartupPhasePullingImage
+		return nil
+	case StartupPhaseInitializing:
+		*s = StartupPhaseInitializing
+		return nil
+	case StartupPhaseWaitingForReadiness:
+		*s = StartupPhaseWaitingForReadiness
+		return nil
+	case StartupPhaseReady:
+		*s = StartupPhaseReady
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
+// Ref: #/components/schemas/StartupPhaseTime
+type StartupPhaseTime struct {
+	Phase StartupPhase `json:"phase"`
+	At    time.Time    `json:"at"`
+}
+
+// GetPhase returns the value of Phase.
+func (s *StartupPhaseTime) GetPhase() StartupPhase {
+	return s.Phase
+}
+
+// GetAt returns the value of At.
+func (s *StartupPhaseTime) GetAt() time.Time {
+	return s.At
+}
+
+// SetPhase sets the value of Phase.
+func (s *StartupPhaseTime) SetPhase(val StartupPhase) {
+	s.Phase = val
+}
+
+// SetAt sets the value of At.
+func (s *StartupPhaseTime) SetAt(val time.Time) {
+	s.At = val
+}
+
+// StopInstanceConflict is response for StopInstance operation.
+type StopInstanceConflict struct{}
+
+func (*StopInstanceConflict) stopInstanceRes() {}
+
+// StopInstanceNotFound is response for StopInstance operation.
+type StopInstanceNotFound struct{}
+
+func (*StopInstanceNotFound) stopInstanceRes() {}
+
+// Ref: #/components/schemas/TemplateProblem
+type TemplateProblem struct {
+	Check   TemplateProblemCheck `json:"check"`
+	Message string               `json:"message"`
+}
+
+// GetCheck returns the value of Check.
+func (s *TemplateProblem) GetCheck() TemplateProblemCheck {
+	return s.Check
+}
+
+// GetMessage returns the value of Message.
+func (s *TemplateProblem) GetMessage() string {
+	return s.Message
+}
+
+// SetCheck sets the value of Check.
+func (s *TemplateProblem) SetCheck(val TemplateProblemCheck) {
+	s.Check = val
+}
+
+// SetMessage sets the value of Message.
+func (s *TemplateProblem) SetMessage(val string) {
+	s.Message = val
+}
+
+type TemplateProblemCheck string
+
+const (
+	TemplateProblemCheckRender         TemplateProblemCheck = "render"
+	TemplateProblemCheckDecode         TemplateProblemCheck = "decode"
+	TemplateProblemCheckPort           TemplateProblemCheck = "port"
+	TemplateProblemCheckReadinessProbe TemplateProblemCheck = "readiness-probe"
+	TemplateProblemCheckDryRun         TemplateProblemCheck = "dry-run"
+)
+
+// AllValues returns all TemplateProblemCheck values.
+func (TemplateProblemCheck) AllValues() []TemplateProblemCheck {
+	return []TemplateProblemCheck{
+		TemplateProblemCheckRender,
+		TemplateProblemCheckDecode,
+		TemplateProblemCheckPort,
+		TemplateProblemCheckReadinessProbe,
+		TemplateProblemCheckDryRun,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s TemplateProblemCheck) MarshalText() ([]byte, error) {
+	switch s {
+	case TemplateProblemCheckRender:
+		return []byte(s), nil
+	case TemplateProblemCheckDecode:
+		return []byte(s), nil
+	case TemplateProblemCheckPort:
+		return []byte(s), nil
+	case TemplateProblemCheckReadinessProbe:
+		return []byte(s), nil
+	case TemplateProblemCheckDryRun:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *TemplateProblemCheck) UnmarshalText(data []byte) error {
+	switch TemplateProblemCheck(data) {
+	case TemplateProblemCheckRender:
+		*s = TemplateProblemCheckRender
+		return nil
+	case TemplateProblemCheckDecode:
+		*s = TemplateProblemCheckDecode
+		return nil
+	case TemplateProblemCheckPort:
+		*s = TemplateProblemCheckPort
+		return nil
+	case TemplateProblemCheckReadinessProbe:
+		*s = TemplateProblemCheckReadinessProbe
+		return nil
+	case TemplateProblemCheckDryRun:
+		*s = TemplateProblemCheckDryRun
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
 // Ref: #/components/schemas/User
 type User struct {
 	// User ID (OpenID Connect sub or UUID).
@@ -474,3 +1549,75 @@ func (s *UserType) UnmarshalText(data []byte) error {
 		return errors.Errorf("invalid value: %q", data)
 	}
 }
+
+// ValidateInstanceTypesForbidden is response for ValidateInstanceTypes operation.
+type ValidateInstanceTypesForbidden struct{}
+
+func (*ValidateInstanceTypesForbidden) validateInstanceTypesRes() {}
+
+type ValidateInstanceTypesOKApplicationJSON []InstanceTypeValidation
+
+func (*ValidateInstanceTypesOKApplicationJSON) validateInstanceTypesRes() {}
+
+// Ref: #/components/schemas/Volume
+type Volume struct {
+	// Unique volume ID.
+	ID string `json:"id"`
+	// Instance type the volume is mounted into.
+	InstanceType string `json:"instance_type"`
+	// Requested storage size (e.g. 10Gi).
+	Size string `json:"size"`
+	// Kubernetes PersistentVolumeClaim phase (e.g. Pending, Bound).
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GetID returns the value of ID.
+func (s *Volume) GetID() string {
+	return s.ID
+}
+
+// GetInstanceType returns the value of InstanceType.
+func (s *Volume) GetInstanceType() string {
+	return s.InstanceType
+}
+
+// GetSize returns the value of Size.
+func (s *Volume) GetSize() string {
+	return s.Size
+}
+
+// GetStatus returns the value of Status.
+func (s *Volume) GetStatus() string {
+	return s.Status
+}
+
+// GetCreatedAt returns the value of CreatedAt.
+func (s *Volume) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// SetID sets the value of ID.
+func (s *Volume) SetID(val string) {
+	s.ID = val
+}
+
+// SetInstanceType sets the value of InstanceType.
+func (s *Volume) SetInstanceType(val string) {
+	s.InstanceType = val
+}
+
+// SetSize sets the value of Size.
+func (s *Volume) SetSize(val string) {
+	s.Size = val
+}
+
+// SetStatus sets the value of Status.
+func (s *Volume) SetStatus(val string) {
+	s.Status = val
+}
+
+// SetCreatedAt sets the value of CreatedAt.
+func (s *Volume) SetCreatedAt(val time.Time) {
+	s.CreatedAt = val
+}
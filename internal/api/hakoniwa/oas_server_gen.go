@@ -20,6 +20,19 @@ type Handler interface {
 	//
 	// DELETE /instances/{instanceId}
 	DeleteInstance(ctx context.Context, params DeleteInstanceParams) (DeleteInstanceRes, error)
+	// DeleteVolume implements deleteVolume operation.
+	//
+	// Delete a volume.
+	//
+	// DELETE /volumes/{volumeId}
+	DeleteVolume(ctx context.Context, params DeleteVolumeParams) (DeleteVolumeRes, error)
+	// ExtendInstance implements extendInstance operation.
+	//
+	// Marks the instance as active and keeps the reaping policies away from it for a while, cancelling a
+	// pending expiry.
+	//
+	// POST /instances/{instanceId}/extend
+	ExtendInstance(ctx context.Context, params ExtendInstanceParams) (ExtendInstanceRes, error)
 	// GetAuthMe implements getAuthMe operation.
 	//
 	// Get current user status.
@@ -32,6 +45,13 @@ type Handler interface {
 	//
 	// GET /configuration
 	GetConfiguration(ctx context.Context) (*Configuration, error)
+	// GetInstance implements getInstance operation.
+	//
+	// Lets a workspace check its own status and expiry using the HAKONIWA_INSTANCE_ID injected into its
+	// pod.
+	//
+	// GET /instances/{instanceId}
+	GetInstance(ctx context.Context, params GetInstanceParams) (GetInstanceRes, error)
 	// ListInstanceTypes implements listInstanceTypes operation.
 	//
 	// List available instance types.
@@ -44,6 +64,12 @@ type Handler interface {
 	//
 	// GET /instances
 	ListInstances(ctx context.Context) ([]Instance, error)
+	// ListVolumes implements listVolumes operation.
+	//
+	// List user volumes.
+	//
+	// GET /volumes
+	ListVolumes(ctx context.Context) ([]Volume, error)
 	// LoginAnonymous implements loginAnonymous operation.
 	//
 	// Login anonymously (creates session only).
@@ -68,6 +94,32 @@ type Handler interface {
 	//
 	// GET /auth/oidc/callback
 	OidcCallback(ctx context.Context, params OidcCallbackParams) (*OidcCallbackFound, error)
+	// ReloadInstanceTypes implements reloadInstanceTypes operation.
+	//
+	// Reads the pod template again and atomically replaces the instance types. Existing instances keep
+	// the template they were created with.
+	//
+	// POST /admin/instance-types/reload
+	ReloadInstanceTypes(ctx context.Context) (ReloadInstanceTypesRes, error)
+	// StartInstance implements startInstance operation.
+	//
+	// Start a stopped instance.
+	//
+	// POST /instances/{instanceId}/start
+	StartInstance(ctx context.Context, params StartInstanceParams) (StartInstanceRes, error)
+	// StopInstance implements stopInstance operation.
+	//
+	// Deletes the instance pod but keeps the instance record and its volumes so it can be started again.
+	//
+	// POST /instances/{instanceId}/stop
+	StopInstance(ctx context.Context, params StopInstanceParams) (StopInstanceRes, error)
+	// ValidateInstanceTypes implements validateInstanceTypes operation.
+	//
+	// Validates the pod template of each instance type with a server-side dry-run create and
+	// Hakoniwa-specific checks. Nothing is created.
+	//
+	// GET /admin/instance-types/validation
+	ValidateInstanceTypes(ctx context.Context) (ValidateInstanceTypesRes, error)
 }
 
 // Server implements http server based on OpenAPI v3 specification and
@@ -31,6 +31,25 @@ func (UnimplementedHandler) DeleteInstance(ctx context.Context, params DeleteIns
 	return r, ht.ErrNotImplemented
 }
 
+// DeleteVolume implements deleteVolume operation.
+//
+// Delete a volume.
+//
+// DELETE /volumes/{volumeId}
+func (UnimplementedHandler) DeleteVolume(ctx context.Context, params DeleteVolumeParams) (r DeleteVolumeRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ExtendInstance implements extendInstance operation.
+//
+// Marks the instance as active and keeps the reaping policies away from it for a while, cancelling a
+// pending expiry.
+//
+// POST /instances/{instanceId}/extend
+func (UnimplementedHandler) ExtendInstance(ctx context.Context, params ExtendInstanceParams) (r ExtendInstanceRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // GetAuthMe implements getAuthMe operation.
 //
 // Get current user status.
@@ -49,6 +68,16 @@ func (UnimplementedHandler) GetConfiguration(ctx context.Context) (r *Configurat
 	return r, ht.ErrNotImplemented
 }
 
+// GetInstance implements getInstance operation.
+//
+// Lets a workspace check its own status and expiry using the HAKONIWA_INSTANCE_ID injected into its
+// pod.
+//
+// GET /instances/{instanceId}
+func (UnimplementedHandler) GetInstance(ctx context.Context, params GetInstanceParams) (r GetInstanceRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // ListInstanceTypes implements listInstanceTypes operation.
 //
 // List available instance types.
@@ -67,6 +96,15 @@ func (UnimplementedHandler) ListInstances(ctx context.Context) (r []Instance, _
 	return r, ht.ErrNotImplemented
 }
 
+// ListVolumes implements listVolumes operation.
+//
+// List user volumes.
+//
+// GET /volumes
+func (UnimplementedHandler) ListVolumes(ctx context.Context) (r []Volume, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // LoginAnonymous implements loginAnonymous operation.
 //
 // Login anonymously (creates session only).
@@ -102,3 +140,41 @@ func (UnimplementedHandler) OidcAuthorize(ctx context.Context) (r *OidcAuthorize
 func (UnimplementedHandler) OidcCallback(ctx context.Context, params OidcCallbackParams) (r *OidcCallbackFound, _ error) {
 	return r, ht.ErrNotImplemented
 }
+
+// ReloadInstanceTypes implements reloadInstanceTypes operation.
+//
+// Reads the pod template again and atomically replaces the instance types. Existing instances keep
+// the template they were created with.
+//
+// POST /admin/instance-types/reload
+func (UnimplementedHandler) ReloadInstanceTypes(ctx context.Context) (r ReloadInstanceTypesRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// StartInstance implements startInstance operation.
+//
+// Start a stopped instance.
+//
+// POST /instances/{instanceId}/start
+func (UnimplementedHandler) StartInstance(ctx context.Context, params StartInstanceParams) (r StartInstanceRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// StopInstance implements stopInstance operation.
+//
+// Deletes the instance pod but keeps the instance record and its volumes so it can be started again.
+//
+// POST /instances/{instanceId}/stop
+func (UnimplementedHandler) StopInstance(ctx context.Context, params StopInstanceParams) (r StopInstanceRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// ValidateInstanceTypes implements validateInstanceTypes operation.
+//
+// Validates the pod template of each instance type with a server-side dry-run create and
+// Hakoniwa-specific checks. Nothing is created.
+//
+// GET /admin/instance-types/validation
+func (UnimplementedHandler) ValidateInstanceTypes(ctx context.Context) (r ValidateInstanceTypesRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
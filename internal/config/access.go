@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// InstanceTypeAccess restricts who may list and create an instance type.
+// Every restriction that is set must match the user; an empty InstanceTypeAccess allows everyone.
+type InstanceTypeAccess struct {
+	// AuthMethods are the allowed authentication methods.
+	AuthMethods []model.UserType
+	// Groups are the allowed OIDC groups; the user must belong to at least one.
+	Groups []string
+	// UserPatterns are the allowed user IDs, where "*" matches any characters (e.g. "oidc:*").
+	UserPatterns []string
+}
+
+// parseInstanceTypeAccess reads the access annotations of a pod template.
+func parseInstanceTypeAccess(annotations map[string]interface{}) (InstanceTypeAccess, error) {
+	var access InstanceTypeAccess
+
+	if val, ok := annotations["hakoniwa.aplulu.me/allowed-auth-methods"].(string); ok {
+		for _, method := range splitList(val) {
+			switch strings.ToLower(method) {
+			case string(model.UserTypeAnonymous):
+				access.AuthMethods = append(access.AuthMethods, model.UserTypeAnonymous)
+			case string(model.UserTypeOIDC), "oidc":
+				access.AuthMethods = append(access.AuthMethods, model.UserTypeOIDC)
+			default:
+				return InstanceTypeAccess{}, fmt.Errorf("invalid hakoniwa.aplulu.me/allowed-auth-methods: unknown auth method %q", method)
+			}
+		}
+	}
+
+	if val, ok := annotations["hakoniwa.aplulu.me/allowed-groups"].(string); ok {
+		access.Groups = splitList(val)
+	}
+
+	if val, ok := annotations["hakoniwa.aplulu.me/allowed-users"].(string); ok {
+		access.UserPatterns = splitList(val)
+	}
+
+	return access, nil
+}
+
+// Allows returns true if the user may list and create instances of the type.
+func (it InstanceType) Allows(user *model.User) bool {
+	access := it.Access
+	if user == nil {
+		return len(access.AuthMethods) == 0 && len(access.Groups) == 0 && len(access.UserPatterns) == 0
+	}
+	if len(access.AuthMethods) > 0 && !slices.Contains(access.AuthMethods, user.Type) {
+		return false
+	}
+	if len(access.Groups) > 0 && !slices.ContainsFunc(access.Groups, func(g string) bool { return slices.Contains(user.Groups, g) }) {
+		return false
+	}
+	if len(access.UserPatterns) > 0 && !slices.ContainsFunc(access.UserPatterns, func(p string) bool { return matchUserPattern(p, user.ID) }) {
+		return false
+	}
+	return true
+}
+
+// matchUserPattern matches a user ID against a pattern where "*" matches any characters.
+func matchUserPattern(pattern, userID string) bool {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(userID)
+}
+
+// splitList splits a comma-separated annotation value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
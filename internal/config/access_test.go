@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+func TestInstanceTypeAllows(t *testing.T) {
+	access, err := parseInstanceTypeAccess(map[string]interface{}{
+		"hakoniwa.aplulu.me/allowed-auth-methods": "oidc",
+		"hakoniwa.aplulu.me/allowed-groups":       "staff, admins",
+		"hakoniwa.aplulu.me/allowed-users":        "oidc:*@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	staffOnly := InstanceType{ID: "large", Access: access}
+	open := InstanceType{ID: "demo"}
+
+	tests := []struct {
+		name string
+		user *model.User
+		it   InstanceType
+		want bool
+	}{
+		{"unrestricted type allows anonymous users", &model.User{ID: "anon", Type: model.UserTypeAnonymous}, open, true},
+		{"unrestricted type allows no user", nil, open, true},
+		{"staff member", &model.User{ID: "oidc:alice@example.com", Type: model.UserTypeOIDC, Groups: []string{"staff"}}, staffOnly, true},
+		{"anonymous user", &model.User{ID: "anon", Type: model.UserTypeAnonymous, Groups: []string{"staff"}}, staffOnly, false},
+		{"not in group", &model.User{ID: "oidc:bob@example.com", Type: model.UserTypeOIDC, Groups: []string{"students"}}, staffOnly, false},
+		{"user pattern mismatch", &model.User{ID: "oidc:eve@example.org", Type: model.UserTypeOIDC, Groups: []string{"admins"}}, staffOnly, false},
+		{"restricted type denies no user", nil, staffOnly, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.it.Allows(tt.user); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseInstanceTypeAccess(map[string]interface{}{"hakoniwa.aplulu.me/allowed-auth-methods": "saml"}); err == nil {
+		t.Error("expected an error for an unknown auth method")
+	}
+}
@@ -6,12 +6,20 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+	_ "time/tzdata" // OFF_HOURS_TIMEZONE must resolve in minimal images
 
 	"github.com/kelseyhightower/envconfig"
+	"k8s.io/apimachinery/pkg/api/resource"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/yaml"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
 )
 
 type config struct {
@@ -26,12 +34,81 @@ type config struct {
 	// KubernetesNamespace is the Kubernetes namespace to use.
 	KubernetesNamespace string `envconfig:"KUBERNETES_NAMESPACE" default:"default"`
 
+	// DatabaseDriver is the instance repository backend ("memory", "sqlite" or "postgres").
+	DatabaseDriver string `envconfig:"DATABASE_DRIVER" default:"memory"`
+
+	// DatabaseDSN is the data source name for the database driver (e.g. the SQLite file path or a PostgreSQL URL).
+	DatabaseDSN string `envconfig:"DATABASE_DSN" default:"hakoniwa.db"`
+
+	// LeaderElectionEnabled is a flag to run background workers only on the replica holding the leader Lease.
+	LeaderElectionEnabled bool `envconfig:"LEADER_ELECTION_ENABLED" default:"false"`
+
+	// LeaderElectionLeaseName is the name of the Lease used for leader election.
+	LeaderElectionLeaseName string `envconfig:"LEADER_ELECTION_LEASE_NAME" default:"hakoniwa-leader"`
+
+	// LeaderElectionLeaseDuration is the duration non-leaders wait before trying to acquire the Lease.
+	LeaderElectionLeaseDuration time.Duration `envconfig:"LEADER_ELECTION_LEASE_DURATION" default:"15s"`
+
+	// LeaderElectionRenewDeadline is the duration the leader retries refreshing the Lease before giving up.
+	LeaderElectionRenewDeadline time.Duration `envconfig:"LEADER_ELECTION_RENEW_DEADLINE" default:"10s"`
+
+	// LeaderElectionRetryPeriod is the interval between Lease acquisition and renewal attempts.
+	LeaderElectionRetryPeriod time.Duration `envconfig:"LEADER_ELECTION_RETRY_PERIOD" default:"2s"`
+
 	// SwaggerUIEnabled is a flag to enable Swagger UI.
 	SwaggerUIEnabled bool `envconfig:"SWAGGER_UI_ENABLED" default:"true"`
 
 	// InstanceInactivityTimeout is the time duration after which an instance is considered inactive.
 	InstanceInactivityTimeout time.Duration `envconfig:"INSTANCE_INACTIVITY_TIMEOUT" default:"1m"`
 
+	// InstanceInactivityAction is what the cleaner does with inactive instances ("delete" or "stop").
+	InstanceInactivityAction string `envconfig:"INSTANCE_INACTIVITY_ACTION" default:"delete"`
+
+	// InstanceWorkload is how instances run by default ("pod", "statefulset" or "deployment").
+	InstanceWorkload string `envconfig:"INSTANCE_WORKLOAD" default:"pod"`
+
+	// ReapPolicies is the ordered list of reaping policies ("idle-timeout", "max-lifetime", "off-hours", "over-quota").
+	ReapPolicies []string `envconfig:"REAP_POLICIES" default:"idle-timeout"`
+
+	// ReapDryRun is a flag to only log the instances the reaping policies would reap.
+	ReapDryRun bool `envconfig:"REAP_DRY_RUN" default:"false"`
+
+	// ReapWarningPeriod is how long an instance is marked as expiring before it is reaped (0 reaps immediately).
+	ReapWarningPeriod time.Duration `envconfig:"REAP_WARNING_PERIOD" default:"5m"`
+
+	// ReapLeaseExtension is how long extending the lease of an instance keeps the reaping policies away.
+	ReapLeaseExtension time.Duration `envconfig:"REAP_LEASE_EXTENSION" default:"1h"`
+
+	// InstanceMaxLifetime is the maximum time an instance may run since it was started (0 disables).
+	InstanceMaxLifetime time.Duration `envconfig:"INSTANCE_MAX_LIFETIME" default:"0"`
+
+	// OffHours is the daily window in which instances are shut down (e.g. "20:00-07:00").
+	OffHours string `envconfig:"OFF_HOURS" default:""`
+
+	// OffHoursTimezone is the IANA time zone OFF_HOURS is interpreted in.
+	OffHoursTimezone string `envconfig:"OFF_HOURS_TIMEZONE" default:"UTC"`
+
+	// InstanceRouting is how requests are routed to instances ("cookie", "subdomain" or "path").
+	InstanceRouting string `envconfig:"INSTANCE_ROUTING" default:"cookie"`
+
+	// InstanceDomain is the wildcard domain instances are served on with subdomain routing ({instance-id}.INSTANCE_DOMAIN).
+	InstanceDomain string `envconfig:"INSTANCE_DOMAIN" default:""`
+
+	// DashboardURL is the URL of the dashboard, where instance hosts send users to sign in.
+	DashboardURL string `envconfig:"DASHBOARD_URL" default:""`
+
+	// ProxyDialTimeout is the timeout for connecting to an instance pod.
+	ProxyDialTimeout time.Duration `envconfig:"PROXY_DIAL_TIMEOUT" default:"5s"`
+
+	// ProxyMaxIdleConnsPerInstance is the number of idle connections kept open to each instance pod.
+	ProxyMaxIdleConnsPerInstance int `envconfig:"PROXY_MAX_IDLE_CONNS_PER_INSTANCE" default:"64"`
+
+	// ProxyIdleConnTimeout is how long an idle connection to an instance pod is kept open.
+	ProxyIdleConnTimeout time.Duration `envconfig:"PROXY_IDLE_CONN_TIMEOUT" default:"90s"`
+
+	// ProxyResponseHeaderTimeout is how long to wait for the response headers of an instance pod (0 waits forever, for long polling).
+	ProxyResponseHeaderTimeout time.Duration `envconfig:"PROXY_RESPONSE_HEADER_TIMEOUT" default:"0"`
+
 	// MaxPodCount is the maximum number of pods allowed (Global limit).
 	MaxPodCount int `envconfig:"MAX_POD_COUNT" default:"100"`
 
@@ -41,9 +118,38 @@ type config struct {
 	// MaxInstancesPerUserPerType is the maximum number of instances allowed per user per type.
 	MaxInstancesPerUserPerType int `envconfig:"MAX_INSTANCES_PER_USER_PER_TYPE" default:"1"`
 
+	// QuotaProfiles override MaxInstancesPerUser for auth methods or OIDC groups (e.g. "anonymous=1,group:staff=5").
+	QuotaProfiles []string `envconfig:"QUOTA_PROFILES" default:""`
+
+	// MaxTotalCPU and MaxTotalMemory budget the resource requests of all running instances (e.g. "32", "64Gi"); empty is unlimited.
+	MaxTotalCPU    string `envconfig:"MAX_TOTAL_CPU" default:""`
+	MaxTotalMemory string `envconfig:"MAX_TOTAL_MEMORY" default:""`
+
+	// InstanceQueueEnabled queues instances that don't fit in MAX_POD_COUNT or the total resource budget instead of refusing them.
+	InstanceQueueEnabled bool `envconfig:"INSTANCE_QUEUE_ENABLED" default:"false"`
+
+	// InstanceQueueMaxLength is the maximum number of queued instances (0 is unlimited).
+	InstanceQueueMaxLength int `envconfig:"INSTANCE_QUEUE_MAX_LENGTH" default:"0"`
+
+	// MaxCPUPerUser and MaxMemoryPerUser budget the resource requests of each user's running instances; empty is unlimited.
+	MaxCPUPerUser    string `envconfig:"MAX_CPU_PER_USER" default:""`
+	MaxMemoryPerUser string `envconfig:"MAX_MEMORY_PER_USER" default:""`
+
 	// PodTemplatePath is the path to the pod template file.
 	PodTemplatePath string `envconfig:"POD_TEMPLATE_PATH" default:""`
 
+	// PodTemplateReloadInterval is how often POD_TEMPLATE_PATH is checked for changes (0 disables).
+	PodTemplateReloadInterval time.Duration `envconfig:"POD_TEMPLATE_RELOAD_INTERVAL" default:"10s"`
+
+	// PodTemplateConfigMap is the name of a ConfigMap to read and watch the pod template from instead of POD_TEMPLATE_PATH.
+	PodTemplateConfigMap string `envconfig:"POD_TEMPLATE_CONFIGMAP" default:""`
+
+	// PodTemplateConfigMapKey is the key of the pod template in POD_TEMPLATE_CONFIGMAP.
+	PodTemplateConfigMapKey string `envconfig:"POD_TEMPLATE_CONFIGMAP_KEY" default:"pod_template.yaml"`
+
+	// AdminUsers is the list of user IDs allowed to use the admin API.
+	AdminUsers []string `envconfig:"ADMIN_USERS" default:""`
+
 	// Title is the application title.
 	Title string `envconfig:"TITLE" default:"Hakoniwa"`
 
@@ -83,6 +189,8 @@ type config struct {
 	OIDCName string `envconfig:"OIDC_NAME" default:"OpenID Connect"`
 	// OIDCScopes is the list of OpenID Connect scopes.
 	OIDCScopes []string `envconfig:"OIDC_SCOPES" default:"openid,profile"`
+	// OIDCGroupsClaim is the ID token claim holding the user's groups.
+	OIDCGroupsClaim string `envconfig:"OIDC_GROUPS_CLAIM" default:"groups"`
 }
 
 type InstanceType struct {
@@ -91,12 +199,47 @@ type InstanceType struct {
 	Description string
 	LogoURL     string
 	TargetPort  string // string to support named ports, though usually int
-	Content     []byte
+	// PreservePathPrefix passes the /w/{instance-id} prefix of path routing on to the pod, whose HAKONIWA_BASE_URL is
+	// set to it, instead of stripping it.
+	PreservePathPrefix bool
+	// H2C proxies requests to the pods over HTTP/2 without TLS, for apps that serve it on the target port.
+	H2C bool
+	// ForwardedPorts are the other ports of the pods users may access, e.g. the port of a dev server.
+	ForwardedPorts []PortRange
+	// VolumeSize and VolumeMountPath declare a persistent home volume kept per user and type.
+	VolumeSize      string
+	VolumeMountPath string
+	// IdleTimeout, MaxLifetime and OffHours are the reaping settings after applying the type's annotations
+	// to the global defaults. Zero or nil disables the rule.
+	IdleTimeout time.Duration
+	MaxLifetime time.Duration
+	OffHours    *OffHours
+	// Workload is how instances of the type run on Kubernetes.
+	Workload model.WorkloadKind
+	// Access restricts who may list and create instances of the type.
+	Access InstanceTypeAccess
+	// MaxInstances caps the running instances of the type across all users (0 is unlimited).
+	MaxInstances int
+	// MaxInstancesPerUser is the per-user limit for the type, MAX_INSTANCES_PER_USER_PER_TYPE unless overridden.
+	MaxInstancesPerUser int
+	// QueuePriority orders queued instances of the type; higher priorities are provisioned first.
+	QueuePriority int
+	// WarmPoolSize is the number of unclaimed pods kept ready for new instances of the type (0 disables the pool).
+	WarmPoolSize int
+	// Parameters are the user-supplied options the template is rendered with.
+	Parameters []InstanceParameter
+	// Content is the pod template, or a List of the pod template followed by its bundled resources.
+	Content []byte
 }
 
 var (
-	conf          config
-	instanceTypes map[string]InstanceType
+	conf             config
+	offHours         *OffHours
+	offHoursLocation *time.Location
+	instanceTypes    atomic.Pointer[map[string]InstanceType]
+	quotaProfiles    []QuotaProfile
+	totalBudget      model.Resources
+	userBudget       model.Resources
 )
 
 //go:embed pod_template.yaml
@@ -108,17 +251,114 @@ func LoadConf() error {
 		return fmt.Errorf("config.LoadConf: failed to load config: %w", err)
 	}
 
-	instanceTypes = make(map[string]InstanceType)
+	switch conf.InstanceInactivityAction {
+	case "delete", "stop":
+	default:
+		return fmt.Errorf("config.LoadConf: invalid INSTANCE_INACTIVITY_ACTION: %s", conf.InstanceInactivityAction)
+	}
 
-	var content []byte
-	if conf.PodTemplatePath != "" {
-		var err error
-		content, err = os.ReadFile(conf.PodTemplatePath)
+	if _, err := parseWorkloadKind(conf.InstanceWorkload); err != nil {
+		return fmt.Errorf("config.LoadConf: invalid INSTANCE_WORKLOAD: %w", err)
+	}
+
+	if err := validateInstanceRouting(); err != nil {
+		return fmt.Errorf("config.LoadConf: %w", err)
+	}
+
+	quotaProfiles = nil
+	for _, value := range conf.QuotaProfiles {
+		p, err := ParseQuotaProfile(value)
+		if err != nil {
+			return fmt.Errorf("config.LoadConf: invalid QUOTA_PROFILES: %w", err)
+		}
+		quotaProfiles = append(quotaProfiles, p)
+	}
+
+	var err error
+	totalBudget, err = parseResourceBudget(conf.MaxTotalCPU, conf.MaxTotalMemory)
+	if err != nil {
+		return fmt.Errorf("config.LoadConf: invalid MAX_TOTAL_CPU or MAX_TOTAL_MEMORY: %w", err)
+	}
+	userBudget, err = parseResourceBudget(conf.MaxCPUPerUser, conf.MaxMemoryPerUser)
+	if err != nil {
+		return fmt.Errorf("config.LoadConf: invalid MAX_CPU_PER_USER or MAX_MEMORY_PER_USER: %w", err)
+	}
+
+	for _, p := range conf.ReapPolicies {
+		switch p {
+		case "idle-timeout", "max-lifetime", "off-hours", "over-quota":
+		default:
+			return fmt.Errorf("config.LoadConf: invalid REAP_POLICIES entry: %s", p)
+		}
+	}
+
+	loc, err := time.LoadLocation(conf.OffHoursTimezone)
+	if err != nil {
+		return fmt.Errorf("config.LoadConf: invalid OFF_HOURS_TIMEZONE: %w", err)
+	}
+	offHoursLocation = loc
+	offHours = nil
+	if conf.OffHours != "" {
+		offHours, err = ParseOffHours(conf.OffHours, loc)
+		if err != nil {
+			return fmt.Errorf("config.LoadConf: invalid OFF_HOURS: %w", err)
+		}
+	}
+
+	content, err := ReadPodTemplate()
+	if err != nil {
+		return fmt.Errorf("config.LoadConf: %w", err)
+	}
+	types, err := ParseInstanceTypes(content)
+	if err != nil {
+		return fmt.Errorf("config.LoadConf: %w", err)
+	}
+	SetInstanceTypes(types)
+
+	return nil
+}
+
+// DefaultPodTemplate returns the embedded default pod template.
+func DefaultPodTemplate() []byte {
+	return defaultPodTemplate
+}
+
+// ReadPodTemplate reads the pod template from POD_TEMPLATE_PATH, or returns the embedded default.
+func ReadPodTemplate() ([]byte, error) {
+	if conf.PodTemplatePath == "" {
+		return defaultPodTemplate, nil
+	}
+	content, err := os.ReadFile(conf.PodTemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pod template path %s: %w", conf.PodTemplatePath, err)
+	}
+	return content, nil
+}
+
+// ParseInstanceTypes parses a pod template (multiple documents or a List) into instance types.
+// Documents other than pods are bundled with the instance type named by their
+// hakoniwa.aplulu.me/instance-type annotation and created together with its pods.
+func ParseInstanceTypes(content []byte) (map[string]InstanceType, error) {
+	types := make(map[string]InstanceType)
+	pods := make(map[string]map[string]interface{})
+	resources := make(map[string][]interface{})
+
+	add := func(raw map[string]interface{}) error {
+		if kind, _ := raw["kind"].(string); kind != "" && kind != "Pod" {
+			typeID, err := bundleInstanceType(raw)
+			if err != nil {
+				return err
+			}
+			resources[typeID] = append(resources[typeID], raw)
+			return nil
+		}
+		it, err := parseInstanceTypeMap(raw)
 		if err != nil {
-			return fmt.Errorf("failed to read pod template path %s: %w", conf.PodTemplatePath, err)
+			return err
 		}
-	} else {
-		content = defaultPodTemplate
+		types[it.ID] = it
+		pods[it.ID] = raw
+		return nil
 	}
 
 	// Decode multiple documents or List
@@ -131,44 +371,119 @@ func LoadConf() error {
 			if err == io.EOF {
 				break
 			}
-			return fmt.Errorf("failed to decode pod template: %w", err)
+			return nil, fmt.Errorf("failed to decode pod template: %w", err)
+		}
+		if raw == nil {
+			// Empty document
+			continue
 		}
 
 		// Check if it's a List
 		if kind, ok := raw["kind"].(string); ok && kind == "List" {
 			if items, ok := raw["items"].([]interface{}); ok {
 				for _, item := range items {
-					// Re-marshal item to bytes to store in InstanceType
-					// This is a bit inefficient but works
-					// Or we can just parse manually.
-					// Let's try to extract metadata from map.
 					if itemMap, ok := item.(map[string]interface{}); ok {
-						it, err := parseInstanceTypeMap(itemMap)
-						if err != nil {
-							return err
+						if err := add(itemMap); err != nil {
+							return nil, err
 						}
-						instanceTypes[it.ID] = it
 					}
 				}
 			}
 			continue
 		}
 
-		// Single Item (Pod)
-		it, err := parseInstanceTypeMap(raw)
+		// Single Item
+		if err := add(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	for typeID, items := range resources {
+		it, ok := types[typeID]
+		if !ok {
+			return nil, fmt.Errorf("pod template: resources reference unknown instance type %s", typeID)
+		}
+
+		// The pod comes first so it can be told apart from the bundled resources.
+		bundle, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      append([]interface{}{pods[typeID]}, items...),
+		})
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("failed to marshal bundle content: %w", err)
+		}
+		if it.WarmPoolSize > 0 {
+			return nil, fmt.Errorf("pod template %s: hakoniwa.aplulu.me/warm-pool-size is not supported with bundled resources", typeID)
+		}
+		it.Content = bundle
+		if _, err := it.Render(it.SampleParameters()); err != nil {
+			return nil, fmt.Errorf("pod template %s: %w", typeID, err)
 		}
-		instanceTypes[it.ID] = it
+		types[typeID] = it
 	}
 
-	// Fallback if empty?
-	if len(instanceTypes) == 0 {
-		// Should we error? Or just leave empty?
-		// Original default was webtop.
+	return types, nil
+}
+
+// bundleInstanceType returns the instance type a non-pod document of the pod template belongs to.
+func bundleInstanceType(raw map[string]interface{}) (string, error) {
+	kind, _ := raw["kind"].(string)
+	metadata, _ := raw["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		return "", fmt.Errorf("missing metadata.name in %s template", kind)
 	}
+	if _, ok := metadata["namespace"]; ok {
+		return "", fmt.Errorf("%s template %s: metadata.namespace is not supported; resources are created in the instance namespace", kind, name)
+	}
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	typeID, _ := annotations["hakoniwa.aplulu.me/instance-type"].(string)
+	if typeID == "" {
+		return "", fmt.Errorf("%s template %s: missing hakoniwa.aplulu.me/instance-type annotation", kind, name)
+	}
+	return typeID, nil
+}
 
-	return nil
+// InstanceTypeChanges lists the instance type IDs changed by SetInstanceTypes.
+type InstanceTypeChanges struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// Empty returns true if nothing changed.
+func (c InstanceTypeChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// SetInstanceTypes atomically replaces the instance types and returns what changed.
+// Instances keep the template they were created with.
+func SetInstanceTypes(types map[string]InstanceType) InstanceTypeChanges {
+	var changes InstanceTypeChanges
+	old := instanceTypes.Swap(&types)
+
+	var previous map[string]InstanceType
+	if old != nil {
+		previous = *old
+	}
+	for id, it := range types {
+		prev, ok := previous[id]
+		if !ok {
+			changes.Added = append(changes.Added, id)
+		} else if !reflect.DeepEqual(prev, it) {
+			changes.Changed = append(changes.Changed, id)
+		}
+	}
+	for id := range previous {
+		if _, ok := types[id]; !ok {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+	return changes
 }
 
 func parseInstanceTypeMap(raw map[string]interface{}) (InstanceType, error) {
@@ -207,6 +522,143 @@ func parseInstanceTypeMap(raw map[string]interface{}) (InstanceType, error) {
 		targetPort = val
 	}
 
+	preservePathPrefix := false
+	if val, ok := annotations["hakoniwa.aplulu.me/path-prefix"].(string); ok {
+		switch val {
+		case "strip":
+		case "preserve":
+			preservePathPrefix = true
+		default:
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/path-prefix %q", name, val)
+		}
+	}
+
+	var forwardedPorts []PortRange
+	if val, ok := annotations["hakoniwa.aplulu.me/forwarded-ports"].(string); ok {
+		var err error
+		forwardedPorts, err = ParsePortRanges(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/forwarded-ports: %w", name, err)
+		}
+	}
+
+	h2c := false
+	if val, ok := annotations["hakoniwa.aplulu.me/h2c"].(string); ok {
+		var err error
+		h2c, err = strconv.ParseBool(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/h2c %q", name, val)
+		}
+	}
+
+	// Persistent home volume
+	volumeSize, _ := annotations["hakoniwa.aplulu.me/volume-size"].(string)
+	volumeMountPath, _ := annotations["hakoniwa.aplulu.me/volume-mount-path"].(string)
+	if (volumeSize == "") != (volumeMountPath == "") {
+		return InstanceType{}, fmt.Errorf("pod template %s: hakoniwa.aplulu.me/volume-size and hakoniwa.aplulu.me/volume-mount-path must be set together", name)
+	}
+	if volumeSize != "" {
+		if _, err := resource.ParseQuantity(volumeSize); err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/volume-size %q: %w", name, volumeSize, err)
+		}
+	}
+
+	// Reaping overrides
+	idleTimeout := conf.InstanceInactivityTimeout
+	if val, ok := annotations["hakoniwa.aplulu.me/idle-timeout"].(string); ok {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/idle-timeout %q: %w", name, val, err)
+		}
+		idleTimeout = d
+	}
+
+	maxLifetime := conf.InstanceMaxLifetime
+	if val, ok := annotations["hakoniwa.aplulu.me/max-lifetime"].(string); ok {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/max-lifetime %q: %w", name, val, err)
+		}
+		maxLifetime = d
+	}
+
+	typeOffHours := offHours
+	if val, ok := annotations["hakoniwa.aplulu.me/off-hours"].(string); ok {
+		if strings.EqualFold(val, "none") {
+			typeOffHours = nil
+		} else {
+			o, err := ParseOffHours(val, offHoursLocation)
+			if err != nil {
+				return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/off-hours: %w", name, err)
+			}
+			typeOffHours = o
+		}
+	}
+
+	workload, _ := parseWorkloadKind(conf.InstanceWorkload)
+	if val, ok := annotations["hakoniwa.aplulu.me/workload"].(string); ok {
+		var err error
+		workload, err = parseWorkloadKind(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/workload: %w", name, err)
+		}
+	}
+
+	access, err := parseInstanceTypeAccess(annotations)
+	if err != nil {
+		return InstanceType{}, fmt.Errorf("pod template %s: %w", name, err)
+	}
+
+	// Quota overrides
+	maxInstances := 0
+	if val, ok := annotations["hakoniwa.aplulu.me/max-instances"].(string); ok {
+		maxInstances, err = strconv.Atoi(val)
+		if err != nil || maxInstances < 0 {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/max-instances %q", name, val)
+		}
+	}
+	maxInstancesPerUser := conf.MaxInstancesPerUserPerType
+	if val, ok := annotations["hakoniwa.aplulu.me/max-instances-per-user"].(string); ok {
+		maxInstancesPerUser, err = strconv.Atoi(val)
+		if err != nil || maxInstancesPerUser < 0 {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/max-instances-per-user %q", name, val)
+		}
+	}
+	queuePriority := 0
+	if val, ok := annotations["hakoniwa.aplulu.me/queue-priority"].(string); ok {
+		queuePriority, err = strconv.Atoi(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/queue-priority %q", name, val)
+		}
+	}
+
+	warmPoolSize := 0
+	if val, ok := annotations["hakoniwa.aplulu.me/warm-pool-size"].(string); ok {
+		warmPoolSize, err = strconv.Atoi(val)
+		if err != nil || warmPoolSize < 0 {
+			return InstanceType{}, fmt.Errorf("pod template %s: invalid hakoniwa.aplulu.me/warm-pool-size %q", name, val)
+		}
+	}
+	// Pool pods are started before anyone claims them, so they can't carry per-user state
+	if warmPoolSize > 0 && volumeSize != "" {
+		return InstanceType{}, fmt.Errorf("pod template %s: hakoniwa.aplulu.me/warm-pool-size is not supported with a home volume", name)
+	}
+	if warmPoolSize > 0 && preservePathPrefix {
+		return InstanceType{}, fmt.Errorf("pod template %s: hakoniwa.aplulu.me/warm-pool-size is not supported with hakoniwa.aplulu.me/path-prefix: preserve", name)
+	}
+	if warmPoolSize > 0 && (workload == model.WorkloadKindStatefulSet || workload == model.WorkloadKindDeployment) {
+		return InstanceType{}, fmt.Errorf("pod template %s: hakoniwa.aplulu.me/warm-pool-size is only supported for pod workloads", name)
+	}
+
+	var params []InstanceParameter
+	if val, ok := annotations["hakoniwa.aplulu.me/parameters"].(string); ok {
+		var err error
+		params, err = parseInstanceParameters(val)
+		if err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: %w", name, err)
+		}
+	}
+
 	// Marshal back to bytes for Content
 	// Note: This drops comments and re-formats, but that's acceptable for internal use.
 	// We need a serializer. k8s yaml serializer?
@@ -228,26 +680,61 @@ func parseInstanceTypeMap(raw map[string]interface{}) (InstanceType, error) {
 		return InstanceType{}, fmt.Errorf("failed to marshal item content: %w", err)
 	}
 
-	return InstanceType{
-		ID:          name,
-		DisplayName: displayName,
-		Description: description,
-		LogoURL:     logoURL,
-		TargetPort:  targetPort,
-		Content:     content,
-	}, nil
+	it := InstanceType{
+		ID:                  name,
+		DisplayName:         displayName,
+		Description:         description,
+		LogoURL:             logoURL,
+		TargetPort:          targetPort,
+		PreservePathPrefix:  preservePathPrefix,
+		H2C:                 h2c,
+		ForwardedPorts:      forwardedPorts,
+		VolumeSize:          volumeSize,
+		VolumeMountPath:     volumeMountPath,
+		IdleTimeout:         idleTimeout,
+		MaxLifetime:         maxLifetime,
+		OffHours:            typeOffHours,
+		Workload:            workload,
+		Access:              access,
+		MaxInstances:        maxInstances,
+		MaxInstancesPerUser: maxInstancesPerUser,
+		QueuePriority:       queuePriority,
+		WarmPoolSize:        warmPoolSize,
+		Parameters:          params,
+		Content:             content,
+	}
+
+	// Make sure the template renders before anyone tries to create an instance from it.
+	if _, err := it.Render(it.SampleParameters()); err != nil {
+		return InstanceType{}, fmt.Errorf("pod template %s: %w", name, err)
+	}
+	if it.WarmPoolSize > 0 {
+		if _, err := it.WarmPoolTemplate(); err != nil {
+			return InstanceType{}, fmt.Errorf("pod template %s: warm pool: %w", name, err)
+		}
+	}
+
+	return it, nil
 }
 
 // GetInstanceType returns the instance type by ID.
 func GetInstanceType(id string) (InstanceType, bool) {
-	it, ok := instanceTypes[id]
+	types := instanceTypes.Load()
+	if types == nil {
+		return InstanceType{}, false
+	}
+	it, ok := (*types)[id]
 	return it, ok
 }
 
 // GetInstanceTypes returns all available instance types.
 func GetInstanceTypes() []InstanceType {
-	types := make([]InstanceType, 0, len(instanceTypes))
-	for _, it := range instanceTypes {
+	current := instanceTypes.Load()
+	if current == nil {
+		return nil
+	}
+	types := make([]InstanceType, 0, len(*current))
+	for _, it := range *current {
 		types = append(types, it)
 	}
 	sort.Slice(types, func(i, j int) bool {
@@ -261,6 +748,11 @@ func MaxInstancesPerUser() int {
 	return conf.MaxInstancesPerUser
 }
 
+// QuotaProfiles returns the per-user limit overrides for auth methods and OIDC groups.
+func QuotaProfiles() []QuotaProfile {
+	return quotaProfiles
+}
+
 // MaxInstancesPerUserPerType returns the max instances per user per type.
 func MaxInstancesPerUserPerType() int {
 	return conf.MaxInstancesPerUserPerType
@@ -286,6 +778,41 @@ func KubernetesNamespace() string {
 	return conf.KubernetesNamespace
 }
 
+// DatabaseDriver returns the instance repository backend.
+func DatabaseDriver() string {
+	return conf.DatabaseDriver
+}
+
+// DatabaseDSN returns the data source name for the database driver.
+func DatabaseDSN() string {
+	return conf.DatabaseDSN
+}
+
+// LeaderElectionEnabled returns true if background workers run only on the leader replica.
+func LeaderElectionEnabled() bool {
+	return conf.LeaderElectionEnabled
+}
+
+// LeaderElectionLeaseName returns the name of the Lease used for leader election.
+func LeaderElectionLeaseName() string {
+	return conf.LeaderElectionLeaseName
+}
+
+// LeaderElectionLeaseDuration returns the duration non-leaders wait before trying to acquire the Lease.
+func LeaderElectionLeaseDuration() time.Duration {
+	return conf.LeaderElectionLeaseDuration
+}
+
+// LeaderElectionRenewDeadline returns the duration the leader retries refreshing the Lease before giving up.
+func LeaderElectionRenewDeadline() time.Duration {
+	return conf.LeaderElectionRenewDeadline
+}
+
+// LeaderElectionRetryPeriod returns the interval between Lease acquisition and renewal attempts.
+func LeaderElectionRetryPeriod() time.Duration {
+	return conf.LeaderElectionRetryPeriod
+}
+
 // SwaggerUIEnabled returns true if Swagger UI is enabled.
 func SwaggerUIEnabled() bool {
 	return conf.SwaggerUIEnabled
@@ -296,11 +823,115 @@ func InstanceInactivityTimeout() time.Duration {
 	return conf.InstanceInactivityTimeout
 }
 
+// InstanceInactivityAction returns what the cleaner does with inactive instances ("delete" or "stop").
+func InstanceInactivityAction() string {
+	return conf.InstanceInactivityAction
+}
+
+// parseWorkloadKind parses "pod", "statefulset" or "deployment".
+func parseWorkloadKind(value string) (model.WorkloadKind, error) {
+	switch kind := model.WorkloadKind(strings.ToLower(value)); kind {
+	case model.WorkloadKindPod, model.WorkloadKindStatefulSet, model.WorkloadKindDeployment:
+		return kind, nil
+	}
+	return "", fmt.Errorf("unknown workload %q", value)
+}
+
+// ReapPolicies returns the ordered list of reaping policies.
+func ReapPolicies() []string {
+	return conf.ReapPolicies
+}
+
+// ReapDryRun returns true if the reaping policies only log what they would reap.
+func ReapDryRun() bool {
+	return conf.ReapDryRun
+}
+
+// ReapWarningPeriod returns how long an instance is marked as expiring before it is reaped.
+func ReapWarningPeriod() time.Duration {
+	return conf.ReapWarningPeriod
+}
+
+// ReapLeaseExtension returns how long extending the lease of an instance keeps the reaping policies away.
+func ReapLeaseExtension() time.Duration {
+	return conf.ReapLeaseExtension
+}
+
+// InstanceMaxLifetime returns the maximum time an instance may run since it was started.
+func InstanceMaxLifetime() time.Duration {
+	return conf.InstanceMaxLifetime
+}
+
+// GetOffHours returns the global off-hours window, or nil if it is not configured.
+func GetOffHours() *OffHours {
+	return offHours
+}
+
+// InstanceQueueEnabled returns true if instances wait in a queue when the cluster capacity is exhausted.
+func InstanceQueueEnabled() bool {
+	return conf.InstanceQueueEnabled
+}
+
+// InstanceQueueMaxLength returns the maximum number of queued instances (0 is unlimited).
+func InstanceQueueMaxLength() int {
+	return conf.InstanceQueueMaxLength
+}
+
+// ProxyDialTimeout returns the timeout for connecting to an instance pod.
+func ProxyDialTimeout() time.Duration {
+	return conf.ProxyDialTimeout
+}
+
+// ProxyMaxIdleConnsPerInstance returns the number of idle connections kept open to each instance pod.
+func ProxyMaxIdleConnsPerInstance() int {
+	return conf.ProxyMaxIdleConnsPerInstance
+}
+
+// ProxyIdleConnTimeout returns how long an idle connection to an instance pod is kept open.
+func ProxyIdleConnTimeout() time.Duration {
+	return conf.ProxyIdleConnTimeout
+}
+
+// ProxyResponseHeaderTimeout returns how long to wait for the response headers of an instance pod.
+func ProxyResponseHeaderTimeout() time.Duration {
+	return conf.ProxyResponseHeaderTimeout
+}
+
 // MaxPodCount returns the maximum number of pods allowed.
 func MaxPodCount() int {
 	return conf.MaxPodCount
 }
 
+// PodTemplatePath returns the path to the pod template file.
+func PodTemplatePath() string {
+	return conf.PodTemplatePath
+}
+
+// PodTemplateReloadInterval returns how often the pod template file is checked for changes.
+func PodTemplateReloadInterval() time.Duration {
+	return conf.PodTemplateReloadInterval
+}
+
+// PodTemplateConfigMap returns the name of the ConfigMap holding the pod template.
+func PodTemplateConfigMap() string {
+	return conf.PodTemplateConfigMap
+}
+
+// PodTemplateConfigMapKey returns the key of the pod template in the ConfigMap.
+func PodTemplateConfigMapKey() string {
+	return conf.PodTemplateConfigMapKey
+}
+
+// IsAdmin returns true if the user is allowed to use the admin API.
+func IsAdmin(userID string) bool {
+	for _, id := range conf.AdminUsers {
+		if id != "" && id == userID {
+			return true
+		}
+	}
+	return false
+}
+
 // Title returns the application title.
 func Title() string {
 	return conf.Title
@@ -391,6 +1022,11 @@ func OIDCScopes() []string {
 	return conf.OIDCScopes
 }
 
+// OIDCGroupsClaim returns the ID token claim holding the user's groups.
+func OIDCGroupsClaim() string {
+	return conf.OIDCGroupsClaim
+}
+
 // OIDCName returns the display name for OIDC login button.
 func OIDCName() string {
 	return conf.OIDCName
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// OffHours is a daily time window in which instances are shut down.
+// The window may wrap around midnight (e.g. "20:00-07:00").
+type OffHours struct {
+	Start    int // minutes since midnight
+	End      int // minutes since midnight
+	Location *time.Location
+}
+
+// ParseOffHours parses a window in the "HH:MM-HH:MM" format.
+func ParseOffHours(value string, loc *time.Location) (*OffHours, error) {
+	var startHour, startMin, endHour, endMin int
+	if _, err := fmt.Sscanf(value, "%d:%d-%d:%d", &startHour, &startMin, &endHour, &endMin); err != nil {
+		return nil, fmt.Errorf("config.ParseOffHours: invalid off-hours %q: %w", value, err)
+	}
+	for _, v := range []struct{ hour, min int }{{startHour, startMin}, {endHour, endMin}} {
+		if v.hour < 0 || v.hour > 23 || v.min < 0 || v.min > 59 {
+			return nil, fmt.Errorf("config.ParseOffHours: invalid off-hours %q: time out of range", value)
+		}
+	}
+
+	start := startHour*60 + startMin
+	end := endHour*60 + endMin
+	if start == end {
+		return nil, fmt.Errorf("config.ParseOffHours: invalid off-hours %q: empty window", value)
+	}
+
+	return &OffHours{Start: start, End: end, Location: loc}, nil
+}
+
+// Contains returns true if t falls within the window.
+func (o *OffHours) Contains(t time.Time) bool {
+	local := t.In(o.Location)
+	m := local.Hour()*60 + local.Minute()
+	if o.Start < o.End {
+		return o.Start <= m && m < o.End
+	}
+	return m >= o.Start || m < o.End
+}
+
+// WindowStart returns the time the window containing t began.
+// ok is false if t is outside the window.
+func (o *OffHours) WindowStart(t time.Time) (time.Time, bool) {
+	if !o.Contains(t) {
+		return time.Time{}, false
+	}
+	local := t.In(o.Location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), o.Start/60, o.Start%60, 0, 0, o.Location)
+	if start.After(local) {
+		// The window started yesterday and wraps around midnight.
+		start = start.AddDate(0, 0, -1)
+	}
+	return start, true
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOffHours(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      string
+		at          string
+		contains    bool
+		windowStart string
+	}{
+		{name: "inside", window: "12:00-13:00", at: "2025-01-02T12:30:00Z", contains: true, windowStart: "2025-01-02T12:00:00Z"},
+		{name: "end is exclusive", window: "12:00-13:00", at: "2025-01-02T13:00:00Z", contains: false},
+		{name: "wrapped before midnight", window: "20:00-07:00", at: "2025-01-02T22:00:00Z", contains: true, windowStart: "2025-01-02T20:00:00Z"},
+		{name: "wrapped after midnight", window: "20:00-07:00", at: "2025-01-02T03:00:00Z", contains: true, windowStart: "2025-01-01T20:00:00Z"},
+		{name: "wrapped outside", window: "20:00-07:00", at: "2025-01-02T12:00:00Z", contains: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := ParseOffHours(tt.window, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseOffHours: %v", err)
+			}
+			at, _ := time.Parse(time.RFC3339, tt.at)
+
+			start, ok := o.WindowStart(at)
+			if ok != tt.contains || o.Contains(at) != tt.contains {
+				t.Fatalf("contains = %v, want %v", ok, tt.contains)
+			}
+			if ok && start.Format(time.RFC3339) != tt.windowStart {
+				t.Errorf("window start = %s, want %s", start.Format(time.RFC3339), tt.windowStart)
+			}
+		})
+	}
+}
+
+func TestParseOffHoursInvalid(t *testing.T) {
+	for _, v := range []string{"", "20:00", "25:00-07:00", "08:00-08:00", "night"} {
+		if _, err := ParseOffHours(v, time.UTC); err == nil {
+			t.Errorf("ParseOffHours(%q) returned no error", v)
+		}
+	}
+}
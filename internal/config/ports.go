@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	From int
+	To   int
+}
+
+// ParsePortRanges parses a comma-separated list of ports and port ranges, e.g. "5173,8000-8099".
+func ParsePortRanges(value string) ([]PortRange, error) {
+	var ranges []PortRange
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(item, "-")
+		if !isRange {
+			to = from
+		}
+		r := PortRange{From: ParsePort(from), To: ParsePort(to)}
+		if r.From == 0 || r.To == 0 || r.From > r.To {
+			return nil, fmt.Errorf("invalid port range %q", item)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// ParsePort returns the port, or 0 if it is not a valid port number.
+func ParsePort(value string) int {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > 65535 {
+		return 0
+	}
+	return port
+}
+
+// ForwardsPort returns true if users may access the port of the pods of the instance type.
+func (it InstanceType) ForwardsPort(port int) bool {
+	for _, r := range it.ForwardedPorts {
+		if port >= r.From && port <= r.To {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePortRanges(t *testing.T) {
+	got, err := ParsePortRanges("5173, 8000-8099,")
+	if err != nil {
+		t.Fatalf("ParsePortRanges: %v", err)
+	}
+	if want := []PortRange{{From: 5173, To: 5173}, {From: 8000, To: 8099}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	it := InstanceType{ForwardedPorts: got}
+	for port, want := range map[int]bool{5173: true, 8000: true, 8099: true, 8100: false, 3000: false} {
+		if it.ForwardsPort(port) != want {
+			t.Errorf("ForwardsPort(%d) = %v, want %v", port, !want, want)
+		}
+	}
+
+	for _, value := range []string{"http", "0", "70000", "9000-8000", "8000-", "-8000"} {
+		if _, err := ParsePortRanges(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// QuotaProfile overrides MAX_INSTANCES_PER_USER for users of an auth method or members of an OIDC group.
+type QuotaProfile struct {
+	// Exactly one of AuthMethod and Group is set.
+	AuthMethod          model.UserType
+	Group               string
+	MaxInstancesPerUser int
+}
+
+// ParseQuotaProfile parses "<auth method>=<limit>" or "group:<name>=<limit>", e.g. "anonymous=1" or "group:staff=5".
+func ParseQuotaProfile(value string) (QuotaProfile, error) {
+	subject, limit, ok := strings.Cut(value, "=")
+	if !ok {
+		return QuotaProfile{}, fmt.Errorf("%q: missing limit", value)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n < 0 {
+		return QuotaProfile{}, fmt.Errorf("%q: invalid limit", value)
+	}
+
+	subject = strings.TrimSpace(subject)
+	if group, ok := strings.CutPrefix(subject, "group:"); ok {
+		if group == "" {
+			return QuotaProfile{}, fmt.Errorf("%q: missing group", value)
+		}
+		return QuotaProfile{Group: group, MaxInstancesPerUser: n}, nil
+	}
+	switch strings.ToLower(subject) {
+	case string(model.UserTypeAnonymous):
+		return QuotaProfile{AuthMethod: model.UserTypeAnonymous, MaxInstancesPerUser: n}, nil
+	case string(model.UserTypeOIDC), "oidc":
+		return QuotaProfile{AuthMethod: model.UserTypeOIDC, MaxInstancesPerUser: n}, nil
+	}
+	return QuotaProfile{}, fmt.Errorf("%q: unknown auth method %s", value, subject)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+func TestParseQuotaProfile(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    QuotaProfile
+		wantErr bool
+	}{
+		{value: "anonymous=1", want: QuotaProfile{AuthMethod: model.UserTypeAnonymous, MaxInstancesPerUser: 1}},
+		{value: "oidc=3", want: QuotaProfile{AuthMethod: model.UserTypeOIDC, MaxInstancesPerUser: 3}},
+		{value: "group:staff=5", want: QuotaProfile{Group: "staff", MaxInstancesPerUser: 5}},
+		{value: "staff=5", wantErr: true},
+		{value: "group:=5", wantErr: true},
+		{value: "anonymous", wantErr: true},
+		{value: "anonymous=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuotaProfile(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseQuotaProfile(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseQuotaProfile(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// parseResourceBudget parses a CPU and memory budget. An empty value leaves that resource unlimited (0).
+func parseResourceBudget(cpu, memory string) (model.Resources, error) {
+	var budget model.Resources
+	if cpu != "" {
+		q, err := resource.ParseQuantity(cpu)
+		if err != nil || q.Sign() < 0 {
+			return model.Resources{}, fmt.Errorf("invalid cpu: %s", cpu)
+		}
+		budget.CPUMillis = q.MilliValue()
+	}
+	if memory != "" {
+		q, err := resource.ParseQuantity(memory)
+		if err != nil || q.Sign() < 0 {
+			return model.Resources{}, fmt.Errorf("invalid memory: %s", memory)
+		}
+		budget.MemoryBytes = q.Value()
+	}
+	return budget, nil
+}
+
+// PodResourceRequests computes the CPU and memory a rendered template requests the way the scheduler does:
+// the sum of the containers (and sidecars), at least the largest init container, plus the pod overhead.
+// A container without a request is accounted with its limit, which Kubernetes defaults the request to.
+func PodResourceRequests(content []byte) (model.Resources, error) {
+	var doc struct {
+		Kind  string            `json:"kind"`
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return model.Resources{}, fmt.Errorf("config.PodResourceRequests: failed to decode template: %w", err)
+	}
+	if doc.Kind == "List" {
+		if len(doc.Items) == 0 {
+			return model.Resources{}, fmt.Errorf("config.PodResourceRequests: empty template")
+		}
+		content = doc.Items[0]
+	}
+	var pod corev1.Pod
+	if err := yaml.Unmarshal(content, &pod); err != nil {
+		return model.Resources{}, fmt.Errorf("config.PodResourceRequests: failed to decode pod: %w", err)
+	}
+	return PodSpecResourceRequests(&pod.Spec), nil
+}
+
+// PodSpecResourceRequests computes the CPU and memory a pod spec requests, see PodResourceRequests.
+func PodSpecResourceRequests(spec *corev1.PodSpec) model.Resources {
+	var containers, sidecars, initMax model.Resources
+	for _, c := range spec.Containers {
+		containers = containers.Add(containerRequests(c))
+	}
+	for _, c := range spec.InitContainers {
+		r := containerRequests(c)
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			// Sidecars keep running next to the containers
+			sidecars = sidecars.Add(r)
+			continue
+		}
+		// Init containers run one at a time, next to the sidecars started before them
+		r = r.Add(sidecars)
+		initMax.CPUMillis = max(initMax.CPUMillis, r.CPUMillis)
+		initMax.MemoryBytes = max(initMax.MemoryBytes, r.MemoryBytes)
+	}
+
+	total := containers.Add(sidecars)
+	total.CPUMillis = max(total.CPUMillis, initMax.CPUMillis)
+	total.MemoryBytes = max(total.MemoryBytes, initMax.MemoryBytes)
+	return total.Add(resourceList(spec.Overhead, nil))
+}
+
+// containerRequests returns the requests of a container, falling back to its limits.
+func containerRequests(c corev1.Container) model.Resources {
+	return resourceList(c.Resources.Requests, c.Resources.Limits)
+}
+
+func resourceList(requests, limits corev1.ResourceList) model.Resources {
+	var r model.Resources
+	if q, ok := requests[corev1.ResourceCPU]; ok {
+		r.CPUMillis = q.MilliValue()
+	} else if q, ok := limits[corev1.ResourceCPU]; ok {
+		r.CPUMillis = q.MilliValue()
+	}
+	if q, ok := requests[corev1.ResourceMemory]; ok {
+		r.MemoryBytes = q.Value()
+	} else if q, ok := limits[corev1.ResourceMemory]; ok {
+		r.MemoryBytes = q.Value()
+	}
+	return r
+}
+
+// TotalResourceBudget returns the CPU and memory budget of all running instances. A zero field is unlimited.
+func TotalResourceBudget() model.Resources {
+	return totalBudget
+}
+
+// UserResourceBudget returns the CPU and memory budget of each user's running instances. A zero field is unlimited.
+func UserResourceBudget() model.Resources {
+	return userBudget
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+func TestPodResourceRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    model.Resources
+	}{
+		{
+			name: "containers are summed and limits stand in for missing requests",
+			content: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+spec:
+  containers:
+    - name: a
+      resources:
+        requests:
+          cpu: 250m
+          memory: 512Mi
+    - name: b
+      resources:
+        limits:
+          cpu: "1"
+          memory: 1Gi
+    - name: c
+`,
+			want: model.Resources{CPUMillis: 1250, MemoryBytes: 1536 << 20},
+		},
+		{
+			name: "largest init container and overhead",
+			content: `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+spec:
+  overhead:
+    cpu: 100m
+  initContainers:
+    - name: init
+      resources:
+        requests:
+          cpu: "2"
+          memory: 64Mi
+  containers:
+    - name: a
+      resources:
+        requests:
+          cpu: 500m
+          memory: 256Mi
+`,
+			want: model.Resources{CPUMillis: 2100, MemoryBytes: 256 << 20},
+		},
+		{
+			name: "bundled template",
+			content: `{"apiVersion":"v1","kind":"List","items":[
+{"apiVersion":"v1","kind":"Pod","metadata":{"name":"test"},"spec":{"containers":[{"name":"a","resources":{"requests":{"cpu":"1","memory":"1Gi"}}}]}},
+{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"}}]}`,
+			want: model.Resources{CPUMillis: 1000, MemoryBytes: 1 << 30},
+		},
+	}
+	for _, tt := range tests {
+		got, err := PodResourceRequests([]byte(tt.content))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseResourceBudget(t *testing.T) {
+	got, err := parseResourceBudget("4", "8Gi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != (model.Resources{CPUMillis: 4000, MemoryBytes: 8 << 30}) {
+		t.Errorf("unexpected budget: %+v", got)
+	}
+
+	if got, err := parseResourceBudget("", ""); err != nil || got != (model.Resources{}) {
+		t.Errorf("expected an unlimited budget, got %+v, %v", got, err)
+	}
+	if _, err := parseResourceBudget("lots", ""); err == nil {
+		t.Errorf("expected an error for an invalid cpu")
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// InstanceRoutingCookie routes requests on the dashboard host to the instance named by the hakoniwa_instance_id cookie.
+	InstanceRoutingCookie = "cookie"
+	// InstanceRoutingSubdomain routes requests on {instance-id}.INSTANCE_DOMAIN to the instance.
+	InstanceRoutingSubdomain = "subdomain"
+	// InstanceRoutingPath routes requests under /w/{instance-id}/ on the dashboard host to the instance.
+	InstanceRoutingPath = "path"
+
+	instancePathPrefix = "/w/"
+)
+
+func validateInstanceRouting() error {
+	switch conf.InstanceRouting {
+	case InstanceRoutingCookie, InstanceRoutingPath:
+		return nil
+	case InstanceRoutingSubdomain:
+	default:
+		return fmt.Errorf("invalid INSTANCE_ROUTING: %s", conf.InstanceRouting)
+	}
+
+	conf.InstanceDomain = strings.ToLower(strings.Trim(conf.InstanceDomain, "."))
+	if conf.InstanceDomain == "" {
+		return fmt.Errorf("INSTANCE_DOMAIN is required with subdomain routing")
+	}
+	u, err := url.Parse(conf.DashboardURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid DASHBOARD_URL: %q is not an absolute http(s) URL", conf.DashboardURL)
+	}
+	if id, _ := InstanceIDFromHost(u.Host); id != "" {
+		return fmt.Errorf("invalid DASHBOARD_URL: %s is an instance host", u.Host)
+	}
+	conf.DashboardURL = strings.TrimSuffix(conf.DashboardURL, "/")
+	return nil
+}
+
+// InstanceRouting returns how requests are routed to instances.
+func InstanceRouting() string {
+	return conf.InstanceRouting
+}
+
+// DashboardURL returns the URL of the dashboard without a trailing slash.
+func DashboardURL() string {
+	return conf.DashboardURL
+}
+
+// InstanceURL returns the URL of the path on the host of the instance with subdomain routing,
+// or on the host of a forwarded port of the instance if port is not 0. The scheme is the one of DASHBOARD_URL.
+func InstanceURL(instanceID string, port int, path string) string {
+	scheme := "https"
+	if strings.HasPrefix(conf.DashboardURL, "http://") {
+		scheme = "http"
+	}
+	label := instanceID
+	if port != 0 {
+		label = strconv.Itoa(port) + "-" + instanceID
+	}
+	return scheme + "://" + label + "." + conf.InstanceDomain + path
+}
+
+// InstancePathPrefix returns the path prefix the instance is served under with path routing, without a trailing slash.
+func InstancePathPrefix(instanceID string) string {
+	return instancePathPrefix + instanceID
+}
+
+// InstanceIDFromPath returns the instance the request path selects with path routing, or "" for other paths.
+func InstanceIDFromPath(path string) string {
+	if conf.InstanceRouting != InstanceRoutingPath {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(path, instancePathPrefix)
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	return id
+}
+
+// InstanceBaseURL returns the HAKONIWA_BASE_URL of a new pod of the instance, the path the instance sees requests under.
+func InstanceBaseURL(instanceID string, it InstanceType) string {
+	if conf.InstanceRouting == InstanceRoutingPath && it.PreservePathPrefix {
+		return InstancePathPrefix(instanceID) + "/"
+	}
+	return "/"
+}
+
+// InstanceIDFromHost returns the instance the request host selects with subdomain routing, or "" for other hosts,
+// and the forwarded port for {port}-{instance-id} hosts (0 for the instance's own port).
+// INSTANCE_DOMAIN includes the port if instances are served on a non-default one.
+func InstanceIDFromHost(host string) (string, int) {
+	if conf.InstanceRouting != InstanceRoutingSubdomain {
+		return "", 0
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+conf.InstanceDomain)
+	if !ok || strings.Contains(label, ".") {
+		return "", 0
+	}
+	// Instance IDs are UUIDs, which may start with digits and a hyphen themselves
+	if uuid.Validate(label) == nil {
+		return label, 0
+	}
+	if value, id, ok := strings.Cut(label, "-"); ok && uuid.Validate(id) == nil {
+		if port, err := strconv.Atoi(value); err == nil && port > 0 && port <= 65535 {
+			return id, port
+		}
+	}
+	return label, 0
+}
@@ -0,0 +1,80 @@
+package config
+
+import "testing"
+
+func TestInstanceRouting(t *testing.T) {
+	saved := conf
+	defer func() { conf = saved }()
+
+	conf.InstanceRouting = InstanceRoutingSubdomain
+	conf.InstanceDomain = ".WS.example.com"
+	conf.DashboardURL = "https://example.com/"
+	if err := validateInstanceRouting(); err != nil {
+		t.Fatalf("validateInstanceRouting: %v", err)
+	}
+
+	const id = "12345678-9abc-def0-1234-56789abcdef0"
+	for host, want := range map[string]struct {
+		id   string
+		port int
+	}{
+		"abc.ws.example.com":              {id: "abc"},
+		"ABC.WS.example.com":              {id: "abc"},
+		id + ".ws.example.com":            {id: id},
+		"5173-" + id + ".ws.example.com":  {id: id, port: 5173},
+		"70000-" + id + ".ws.example.com": {id: "70000-" + id},
+		"a.b.ws.example.com":              {},
+		"ws.example.com":                  {},
+		"abc.ws.example.com:443":          {},
+		"example.com":                     {},
+		"abc.example.com":                 {},
+	} {
+		if gotID, gotPort := InstanceIDFromHost(host); gotID != want.id || gotPort != want.port {
+			t.Errorf("InstanceIDFromHost(%q) = %q, %d, want %q, %d", host, gotID, gotPort, want.id, want.port)
+		}
+	}
+	if got, want := InstanceURL("abc", 0, "/_hakoniwa/session"), "https://abc.ws.example.com/_hakoniwa/session"; got != want {
+		t.Errorf("InstanceURL = %q, want %q", got, want)
+	}
+	if got, want := InstanceURL("abc", 5173, "/"), "https://5173-abc.ws.example.com/"; got != want {
+		t.Errorf("InstanceURL = %q, want %q", got, want)
+	}
+
+	conf = config{InstanceRouting: InstanceRoutingPath}
+	for path, want := range map[string]string{
+		"/w/abc":        "abc",
+		"/w/abc/lab":    "abc",
+		"/w/":           "",
+		"/_hakoniwa/":   "",
+		"/work/abc/lab": "",
+	} {
+		if got := InstanceIDFromPath(path); got != want {
+			t.Errorf("InstanceIDFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+	if got := InstanceBaseURL("abc", InstanceType{PreservePathPrefix: true}); got != "/w/abc/" {
+		t.Errorf("got base URL %q for a type preserving the prefix, want /w/abc/", got)
+	}
+	if got := InstanceBaseURL("abc", InstanceType{}); got != "/" {
+		t.Errorf("got base URL %q for a type stripping the prefix, want /", got)
+	}
+	conf.InstanceRouting = InstanceRoutingCookie
+	if got := InstanceBaseURL("abc", InstanceType{PreservePathPrefix: true}); got != "/" {
+		t.Errorf("got base URL %q without path routing, want /", got)
+	}
+	if got := InstanceIDFromPath("/w/abc/lab"); got != "" {
+		t.Errorf("got instance %q without path routing", got)
+	}
+
+	for name, c := range map[string]config{
+		"no domain":          {InstanceRouting: InstanceRoutingSubdomain, DashboardURL: "https://example.com"},
+		"relative dashboard": {InstanceRouting: InstanceRoutingSubdomain, InstanceDomain: "ws.example.com", DashboardURL: "/"},
+		"dashboard instance": {InstanceRouting: InstanceRoutingSubdomain, InstanceDomain: "example.com", DashboardURL: "https://www.example.com"},
+		"unknown routing":    {InstanceRouting: "header"},
+	} {
+		conf = c
+		if err := validateInstanceRouting(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// Parameter types
+const (
+	ParameterTypeString   = "string"
+	ParameterTypeInteger  = "integer"
+	ParameterTypeBoolean  = "boolean"
+	ParameterTypeQuantity = "quantity" // Kubernetes resource quantity (e.g. "4Gi", "500m")
+	ParameterTypeArray    = "array"    // List of strings, e.g. enabled extensions
+)
+
+// InstanceParameter is a user-supplied option declared by a pod template
+// in the hakoniwa.aplulu.me/parameters annotation.
+type InstanceParameter struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Default     any    `json:"default,omitempty"`
+	// Enum lists the allowed values. For arrays it applies to each item.
+	Enum []string `json:"enum,omitempty"`
+	// Minimum and Maximum bound integer and quantity parameters.
+	Minimum *resource.Quantity `json:"minimum,omitempty"`
+	Maximum *resource.Quantity `json:"maximum,omitempty"`
+}
+
+// parseInstanceParameters parses and checks the parameter declarations of a pod template.
+func parseInstanceParameters(value string) ([]InstanceParameter, error) {
+	var params []InstanceParameter
+	if err := yaml.Unmarshal([]byte(value), &params); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	seen := make(map[string]bool, len(params))
+	for i, p := range params {
+		if p.Name == "" {
+			return nil, fmt.Errorf("parameter %d: missing name", i)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("parameter %s: duplicated", p.Name)
+		}
+		seen[p.Name] = true
+
+		switch p.Type {
+		case ParameterTypeString, ParameterTypeBoolean, ParameterTypeArray:
+			if p.Minimum != nil || p.Maximum != nil {
+				return nil, fmt.Errorf("parameter %s: minimum and maximum are only supported for integer and quantity", p.Name)
+			}
+		case ParameterTypeInteger, ParameterTypeQuantity:
+		default:
+			return nil, fmt.Errorf("parameter %s: unknown type %q", p.Name, p.Type)
+		}
+
+		if p.Default != nil {
+			if _, err := p.normalize(p.Default); err != nil {
+				return nil, fmt.Errorf("parameter %s: invalid default: %w", p.Name, err)
+			}
+		}
+	}
+	return params, nil
+}
+
+// ResolveParameters validates user-supplied values against the declared parameters and fills in defaults.
+// Values are normalized to string, int64, bool or []string so they can be rendered and stored as JSON.
+func (it InstanceType) ResolveParameters(values map[string]any) (map[string]any, error) {
+	for name := range values {
+		if !slices.ContainsFunc(it.Parameters, func(p InstanceParameter) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("%w: unknown parameter %s", model.ErrInvalidParameter, name)
+		}
+	}
+
+	resolved := make(map[string]any, len(it.Parameters))
+	for _, p := range it.Parameters {
+		v, ok := values[p.Name]
+		if !ok || v == nil {
+			if p.Default == nil {
+				return nil, fmt.Errorf("%w: parameter %s is required", model.ErrInvalidParameter, p.Name)
+			}
+			v = p.Default
+		}
+		n, err := p.normalize(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: parameter %s: %v", model.ErrInvalidParameter, p.Name, err)
+		}
+		resolved[p.Name] = n
+	}
+	return resolved, nil
+}
+
+func (p InstanceParameter) normalize(v any) (any, error) {
+	switch p.Type {
+	case ParameterTypeString:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, s) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(p.Enum, ", "))
+		}
+		return s, nil
+
+	case ParameterTypeInteger:
+		var n int64
+		switch t := v.(type) {
+		case int64:
+			n = t
+		case int:
+			n = int64(t)
+		case float64:
+			if t != math.Trunc(t) {
+				return nil, fmt.Errorf("must be an integer")
+			}
+			n = int64(t)
+		default:
+			return nil, fmt.Errorf("must be an integer")
+		}
+		q := resource.NewQuantity(n, resource.DecimalSI)
+		if err := p.checkRange(*q); err != nil {
+			return nil, err
+		}
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, q.String()) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(p.Enum, ", "))
+		}
+		return n, nil
+
+	case ParameterTypeBoolean:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return b, nil
+
+	case ParameterTypeQuantity:
+		var s string
+		switch t := v.(type) {
+		case string:
+			s = t
+		case float64, int64, int:
+			s = fmt.Sprint(t)
+		default:
+			return nil, fmt.Errorf("must be a quantity")
+		}
+		q, err := resource.ParseQuantity(s)
+		if err != nil {
+			return nil, fmt.Errorf("must be a quantity: %w", err)
+		}
+		if err := p.checkRange(q); err != nil {
+			return nil, err
+		}
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, s) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(p.Enum, ", "))
+		}
+		return s, nil
+
+	case ParameterTypeArray:
+		var items []string
+		switch t := v.(type) {
+		case []string:
+			items = t
+		case []any:
+			for _, item := range t {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("must be a list of strings")
+				}
+				items = append(items, s)
+			}
+		default:
+			return nil, fmt.Errorf("must be a list of strings")
+		}
+		for _, s := range items {
+			if len(p.Enum) > 0 && !slices.Contains(p.Enum, s) {
+				return nil, fmt.Errorf("items must be one of %s", strings.Join(p.Enum, ", "))
+			}
+		}
+		if items == nil {
+			items = []string{}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", p.Type)
+}
+
+func (p InstanceParameter) checkRange(q resource.Quantity) error {
+	if p.Minimum != nil && q.Cmp(*p.Minimum) < 0 {
+		return fmt.Errorf("must be at least %s", p.Minimum.String())
+	}
+	if p.Maximum != nil && q.Cmp(*p.Maximum) > 0 {
+		return fmt.Errorf("must be at most %s", p.Maximum.String())
+	}
+	return nil
+}
+
+// Render renders the string values of the template with the resolved parameters.
+// Each string is a Go text/template with the parameters available as .Params,
+// e.g. memory: "{{ .Params.memory }}" or value: '{{ join .Params.extensions "," }}'.
+func (it InstanceType) Render(params map[string]any) ([]byte, error) {
+	if len(it.Parameters) == 0 {
+		return it.Content, nil
+	}
+
+	var obj any
+	if err := yaml.Unmarshal(it.Content, &obj); err != nil {
+		return nil, fmt.Errorf("config.Render: failed to decode template: %w", err)
+	}
+
+	data := map[string]any{"Params": params}
+	rendered, err := renderValue(obj, data)
+	if err != nil {
+		return nil, fmt.Errorf("config.Render: template %s: %w", it.ID, err)
+	}
+
+	content, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("config.Render: failed to encode template: %w", err)
+	}
+	return content, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join": func(items []string, sep string) string { return strings.Join(items, sep) },
+}
+
+func renderValue(v any, data map[string]any) (any, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			r, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = r
+		}
+		return t, nil
+	case []any:
+		for i, item := range t {
+			r, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = r
+		}
+		return t, nil
+	case string:
+		if !strings.Contains(t, "{{") {
+			return t, nil
+		}
+		tmpl, err := template.New("").Option("missingkey=error").Funcs(templateFuncs).Parse(t)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	}
+	return v, nil
+}
+
+// SampleParameters returns the defaults, or a zero value for required parameters, to check that the template renders.
+func (it InstanceType) SampleParameters() map[string]any {
+	values := make(map[string]any, len(it.Parameters))
+	for _, p := range it.Parameters {
+		if p.Default != nil {
+			if n, err := p.normalize(p.Default); err == nil {
+				values[p.Name] = n
+				continue
+			}
+		}
+		switch p.Type {
+		case ParameterTypeInteger:
+			values[p.Name] = int64(0)
+		case ParameterTypeBoolean:
+			values[p.Name] = false
+		case ParameterTypeArray:
+			values[p.Name] = []string{}
+		default:
+			values[p.Name] = ""
+		}
+	}
+	return values
+}
+
+// WarmPoolTemplate renders the template with the parameter defaults, which is what warm pool pods are started with.
+// Instances created with other values don't match the pool and get a pod of their own.
+func (it InstanceType) WarmPoolTemplate() ([]byte, error) {
+	params, err := it.ResolveParameters(nil)
+	if err != nil {
+		return nil, err
+	}
+	return it.Render(params)
+}
+
+// TemplateHash identifies a rendered template, so a warm pool pod is only claimed by instances rendered the same way.
+func TemplateHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const parameterizedTemplate = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: jupyter
+  annotations:
+    hakoniwa.aplulu.me/parameters: |
+      - name: memory
+        type: quantity
+        default: 2Gi
+        minimum: 1Gi
+        maximum: 16Gi
+      - name: tag
+        type: string
+        enum: [latest, "2024.1"]
+        default: latest
+      - name: extensions
+        type: array
+        enum: [git, lsp]
+        default: []
+spec:
+  containers:
+  - name: jupyter
+    image: "jupyter/base-notebook:{{ .Params.tag }}"
+    env:
+    - name: EXTENSIONS
+      value: '{{ join .Params.extensions "," }}'
+    resources:
+      limits:
+        memory: "{{ .Params.memory }}"
+`
+
+func TestInstanceTypeParameters(t *testing.T) {
+	var raw map[string]any
+	if err := yaml.Unmarshal([]byte(parameterizedTemplate), &raw); err != nil {
+		t.Fatal(err)
+	}
+	it, err := parseInstanceTypeMap(raw)
+	if err != nil {
+		t.Fatalf("parseInstanceTypeMap: %v", err)
+	}
+
+	resolved, err := it.ResolveParameters(map[string]any{
+		"memory":     "8Gi",
+		"extensions": []any{"git", "lsp"},
+	})
+	if err != nil {
+		t.Fatalf("ResolveParameters: %v", err)
+	}
+	content, err := it.Render(resolved)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{`"jupyter/base-notebook:latest"`, `"git,lsp"`, `"memory":"8Gi"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("rendered template does not contain %s: %s", want, content)
+		}
+	}
+
+	for name, values := range map[string]map[string]any{
+		"above maximum":    {"memory": "32Gi"},
+		"not in enum":      {"tag": "nightly"},
+		"item not in enum": {"extensions": []any{"vim"}},
+		"unknown":          {"cpu": "2"},
+	} {
+		if _, err := it.ResolveParameters(values); !errors.Is(err, model.ErrInvalidParameter) {
+			t.Errorf("%s: expected ErrInvalidParameter, got %v", name, err)
+		}
+	}
+}
+
+const bundledTemplate = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: code-server
+spec:
+  containers:
+  - name: code-server
+    image: codercom/code-server
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  annotations:
+    hakoniwa.aplulu.me/instance-type: code-server
+data:
+  settings.json: "{}"
+`
+
+func TestParseInstanceTypesBundle(t *testing.T) {
+	types, err := ParseInstanceTypes([]byte(bundledTemplate))
+	if err != nil {
+		t.Fatalf("ParseInstanceTypes: %v", err)
+	}
+	if len(types) != 1 {
+		t.Fatalf("got %d instance types, want 1", len(types))
+	}
+
+	var bundle struct {
+		Kind  string `json:"kind"`
+		Items []struct {
+			Kind string `json:"kind"`
+		} `json:"items"`
+	}
+	if err := yaml.Unmarshal(types["code-server"].Content, &bundle); err != nil {
+		t.Fatal(err)
+	}
+	if bundle.Kind != "List" || len(bundle.Items) != 2 || bundle.Items[0].Kind != "Pod" || bundle.Items[1].Kind != "ConfigMap" {
+		t.Errorf("unexpected bundle: %+v", bundle)
+	}
+
+	orphan := strings.Replace(bundledTemplate, "instance-type: code-server", "instance-type: jupyter", 1)
+	if _, err := ParseInstanceTypes([]byte(orphan)); err == nil {
+		t.Error("expected an error for resources of an unknown instance type")
+	}
+}
+
+func TestParseInstanceTypesWarmPool(t *testing.T) {
+	pooled := strings.Replace(parameterizedTemplate, "  annotations:\n", "  annotations:\n    hakoniwa.aplulu.me/warm-pool-size: \"2\"\n", 1)
+	types, err := ParseInstanceTypes([]byte(pooled))
+	if err != nil {
+		t.Fatalf("ParseInstanceTypes: %v", err)
+	}
+	it := types["jupyter"]
+	if it.WarmPoolSize != 2 {
+		t.Errorf("got warm pool size %d, want 2", it.WarmPoolSize)
+	}
+	content, err := it.WarmPoolTemplate()
+	if err != nil {
+		t.Fatalf("WarmPoolTemplate: %v", err)
+	}
+	// An instance created with the defaults matches the pool
+	defaults, err := it.ResolveParameters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered, _ := it.Render(defaults); TemplateHash(rendered) != TemplateHash(content) {
+		t.Error("expected the defaults to render the warm pool template")
+	}
+	custom, err := it.ResolveParameters(map[string]any{"memory": "8Gi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered, _ := it.Render(custom); TemplateHash(rendered) == TemplateHash(content) {
+		t.Error("expected other values not to match the warm pool template")
+	}
+
+	for name, template := range map[string]string{
+		"home volume":      strings.Replace(pooled, "  annotations:\n", "  annotations:\n    hakoniwa.aplulu.me/volume-size: 1Gi\n    hakoniwa.aplulu.me/volume-mount-path: /home/jovyan\n", 1),
+		"workload":         strings.Replace(pooled, "  annotations:\n", "  annotations:\n    hakoniwa.aplulu.me/workload: statefulset\n", 1),
+		"path prefix":      strings.Replace(pooled, "  annotations:\n", "  annotations:\n    hakoniwa.aplulu.me/path-prefix: preserve\n", 1),
+		"required param":   strings.Replace(pooled, "        default: latest\n", "", 1),
+		"bundled resource": strings.Replace(bundledTemplate, "  name: code-server\nspec:", "  name: code-server\n  annotations:\n    hakoniwa.aplulu.me/warm-pool-size: \"1\"\nspec:", 1),
+	} {
+		if _, err := ParseInstanceTypes([]byte(template)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -3,7 +3,12 @@ package model
 import "errors"
 
 var (
-	ErrUnauthorized        = errors.New("unauthorized")
-	ErrNotFound            = errors.New("not found")
-	ErrMaxInstancesReached = errors.New("max instances reached")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrNotFound             = errors.New("not found")
+	ErrMaxInstancesReached  = errors.New("max instances reached")
+	ErrVolumeInUse          = errors.New("volume in use")
+	ErrInvalidParameter     = errors.New("invalid parameter")
+	ErrInvalidInstanceState = errors.New("invalid instance state")
+	ErrBudgetExceeded       = errors.New("resource budget exceeded")
+	ErrInvalidPodTemplate   = errors.New("invalid pod template")
 )
@@ -5,9 +5,74 @@ import "time"
 type InstanceStatus string
 
 const (
-	InstanceStatusPending     InstanceStatus = "pending"
+	InstanceStatusPending     InstanceStatus = "pending"  // Waiting for the pod to be scheduled
+	InstanceStatusStarting    InstanceStatus = "starting" // Scheduled; pulling images or starting containers
 	InstanceStatusRunning     InstanceStatus = "running"
+	InstanceStatusFailed      InstanceStatus = "failed" // The pod can't start, e.g. ImagePullBackOff; it may still recover
 	InstanceStatusTerminating InstanceStatus = "terminating"
+	InstanceStatusStopped     InstanceStatus = "stopped" // Pod deleted; record and volumes are kept
+	InstanceStatusQueued      InstanceStatus = "queued"  // Waiting for cluster capacity; the pod is not created yet
+)
+
+// StatusReasonProvisioningFailed is the status reason of a failed queued instance whose pod could not be created.
+// It has no pod to recover, so it is kept as failed until it is stopped or deleted.
+const StatusReasonProvisioningFailed = "ProvisioningFailed"
+
+// HasPod returns false for stopped and queued instances, which don't take up cluster capacity.
+func (s InstanceStatus) HasPod() bool {
+	return s != InstanceStatusStopped && s != InstanceStatusQueued
+}
+
+// StartupPhase is how far the pod of an instance has come in starting up.
+type StartupPhase string
+
+const (
+	StartupPhaseScheduled           StartupPhase = "scheduled"
+	StartupPhasePullingImage        StartupPhase = "pulling_image"
+	StartupPhaseInitializing        StartupPhase = "initializing" // The first containers are starting, init containers first
+	StartupPhaseWaitingForReadiness StartupPhase = "waiting_for_readiness"
+	StartupPhaseReady               StartupPhase = "ready"
+)
+
+// StartupPhaseTime is when the pod of an instance entered a startup phase.
+type StartupPhaseTime struct {
+	Phase StartupPhase
+	At    time.Time
+}
+
+// EqualStartupPhases returns true if both list the same phases entered at the same times.
+func EqualStartupPhases(a, b []StartupPhaseTime) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Phase != b[i].Phase || !a[i].At.Equal(b[i].At) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReapReason explains why the cleaner reaped an instance.
+type ReapReason string
+
+const (
+	ReapReasonIdleTimeout ReapReason = "idle_timeout"
+	ReapReasonMaxLifetime ReapReason = "max_lifetime"
+	ReapReasonOffHours    ReapReason = "off_hours"
+	ReapReasonOverQuota   ReapReason = "over_quota"
+)
+
+// WorkloadKind is how an instance runs on Kubernetes.
+type WorkloadKind string
+
+const (
+	// WorkloadKindPod runs the instance as a bare pod; the instance ends with its pod.
+	WorkloadKindPod WorkloadKind = "pod"
+	// WorkloadKindStatefulSet and WorkloadKindDeployment run the instance as a single-replica workload,
+	// so Kubernetes recreates the pod when it exits or its node is drained.
+	WorkloadKindStatefulSet WorkloadKind = "statefulset"
+	WorkloadKindDeployment  WorkloadKind = "deployment"
 )
 
 type Instance struct {
@@ -19,4 +84,57 @@ type Instance struct {
 	PodIP        string
 	Status       InstanceStatus
 	LastActiveAt time.Time
-}
\ No newline at end of file
+	CreatedAt    time.Time
+	StartedAt    time.Time
+	ReapReason   ReapReason // Set when the cleaner stopped the instance
+	// ExpiresAt is when the cleaner will reap the instance. Zero unless a reaping policy selected it.
+	ExpiresAt time.Time
+	// ExtendedUntil is when the lease extended by the user ends. Reaping policies leave the instance alone until then.
+	ExtendedUntil time.Time
+	// Parameters are the resolved template parameters the instance was created with.
+	Parameters map[string]any
+	// TargetPort and Template are taken from the instance type at creation,
+	// so the instance keeps working the same way when the instance types are reloaded.
+	TargetPort string
+	Template   []byte
+	// Resources are the CPU and memory requested by the pod, counted against the resource budgets.
+	Resources Resources
+	// Workload is how the instance runs. PodName is the current pod of the workload and changes when it is recreated.
+	Workload WorkloadKind
+	// QueuePriority orders the waiting queue; higher priorities are provisioned first, then the oldest.
+	QueuePriority int
+	// QueuePosition is the 1-based position of a queued instance in the waiting queue. It is not stored.
+	QueuePosition int
+	// StatusReason and StatusMessage explain a pending, starting or failed status, e.g. ImagePullBackOff
+	// and the image pull error. They are empty while the instance is running.
+	StatusReason  string
+	StatusMessage string
+	// StartupPhases are the startup phases the current pod has reached so far, in order. The last one is the current phase.
+	StartupPhases []StartupPhaseTime
+	// BaseURL is the HAKONIWA_BASE_URL the pod was started with, the path the instance sees requests under.
+	// It is "/" unless path routing preserves the prefix for the instance type, and empty for instances created before it was recorded.
+	BaseURL string
+}
+
+// HasPod returns false if the instance takes up no cluster capacity: it is stopped or queued, or its pod could not
+// be created.
+func (i *Instance) HasPod() bool {
+	return i.Status.HasPod() && i.StatusReason != StatusReasonProvisioningFailed
+}
+
+// StartupPhase returns the current startup phase, or an empty phase if the pod has not been scheduled.
+func (i *Instance) StartupPhase() StartupPhase {
+	if len(i.StartupPhases) == 0 {
+		return ""
+	}
+	return i.StartupPhases[len(i.StartupPhases)-1].Phase
+}
+
+// PodStatus is the state of an instance's pod as reported by Kubernetes.
+type PodStatus struct {
+	Status  InstanceStatus
+	PodIP   string
+	Reason  string
+	Message string
+	Phases  []StartupPhaseTime
+}
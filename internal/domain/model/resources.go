@@ -0,0 +1,15 @@
+package model
+
+// Resources are the CPU and memory requested by instances.
+type Resources struct {
+	CPUMillis   int64
+	MemoryBytes int64
+}
+
+// Add returns the sum of both resources.
+func (r Resources) Add(o Resources) Resources {
+	return Resources{
+		CPUMillis:   r.CPUMillis + o.CPUMillis,
+		MemoryBytes: r.MemoryBytes + o.MemoryBytes,
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+// Template checks
+const (
+	TemplateCheckRender         = "render"
+	TemplateCheckDecode         = "decode"
+	TemplateCheckPort           = "port"
+	TemplateCheckReadinessProbe = "readiness-probe"
+	TemplateCheckDryRun         = "dry-run" // Kubernetes server-side dry-run create
+)
+
+// TemplateProblem is an issue found while validating an instance type template.
+type TemplateProblem struct {
+	Check   string
+	Message string
+}
+
+// InstanceTypeValidation is the validation result of an instance type.
+type InstanceTypeValidation struct {
+	InstanceType string
+	Problems     []TemplateProblem
+}
@@ -8,6 +8,7 @@ const (
 )
 
 type User struct {
-	ID   string
-	Type UserType
+	ID     string
+	Type   UserType
+	Groups []string // OIDC groups, used to restrict instance types
 }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Volume is a persistent home volume owned by a user for one instance type.
+// It outlives the instances that mount it.
+type Volume struct {
+	Name         string
+	UserID       string
+	InstanceType string
+	Size         string
+	Status       string
+	CreatedAt    time.Time
+}
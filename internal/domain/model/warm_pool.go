@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// WarmPoolPod is an unclaimed pod started ahead of time for an instance type.
+// Creating an instance of the type claims it instead of starting a pod from scratch.
+type WarmPoolPod struct {
+	PodName      string
+	InstanceType string
+	// TemplateHash identifies the rendered template the pod was started from.
+	TemplateHash string
+	Ready        bool
+	// Failed is true once the pod has finished, so it can never be claimed.
+	Failed    bool
+	CreatedAt time.Time
+}
@@ -8,12 +8,48 @@ import (
 )
 
 type InstanceRepository interface {
+	// Save writes the whole instance. It is for new instances; updates of existing ones go through the Update methods,
+	// so they don't roll back columns other replicas update in the meantime, such as LastActiveAt.
 	Save(ctx context.Context, instance *model.Instance) error
 	FindByID(ctx context.Context, instanceID string) (*model.Instance, error)
 	FindByUser(ctx context.Context, userID string) ([]*model.Instance, error)
+	List(ctx context.Context) ([]*model.Instance, error)
 	Delete(ctx context.Context, instanceID string) error
-	ListInactive(ctx context.Context, threshold time.Duration) ([]*model.Instance, error)
+	UpdateLastActiveAt(ctx context.Context, instanceID string, lastActiveAt time.Time) error
+	// UpdateExpiresAt sets when the cleaner will reap the instance. A zero time clears the warning.
+	UpdateExpiresAt(ctx context.Context, instanceID string, expiresAt time.Time) error
+	// UpdateLease sets when the cleaner will reap the instance and when the lease extended by the user ends,
+	// together so the cleaner never sees one without the other.
+	UpdateLease(ctx context.Context, instanceID string, expiresAt, extendedUntil time.Time) error
+	// UpdateStatus sets the status of the instance and its pod: Status, PodName, PodIP, StatusReason, StatusMessage,
+	// StartupPhases and ReapReason. It only updates an instance that is still in the expected status, so a stale
+	// snapshot never overwrites a newer status; otherwise it returns model.ErrInvalidInstanceState.
+	UpdateStatus(ctx context.Context, instance *model.Instance, expected model.InstanceStatus) error
+	// Requeue moves an instance whose reservation left the queue back to it, at the time it was queued
+	// (StartedAt). It returns model.ErrInvalidInstanceState if the instance is no longer pending.
+	Requeue(ctx context.Context, instance *model.Instance) error
+	// ListQueued returns the queued instances in queue order: by priority, then by when they were queued.
+	ListQueued(ctx context.Context) ([]*model.Instance, error)
+	// Reserve saves the instance if check passes. Reservations are serialized, so concurrent checks never
+	// see the same counts; the saved instance holds its share of the quotas until it is deleted or stopped.
+	Reserve(ctx context.Context, instance *model.Instance, check func(ctx context.Context, counter InstanceCounter) error) error
+	InstanceCounter
+}
+
+// InstanceCounter counts instances towards the quotas.
+type InstanceCounter interface {
+	// Count returns the number of instances with a pod, excluding stopped and queued instances.
 	Count(ctx context.Context) (int, error)
 	CountByUser(ctx context.Context, userID string) (int, error)
 	CountByUserAndType(ctx context.Context, userID, instanceType string) (int, error)
-}
\ No newline at end of file
+	// CountByUserAndTypeWithPod counts the user's instances of a type with a pod.
+	CountByUserAndTypeWithPod(ctx context.Context, userID, instanceType string) (int, error)
+	// CountByType counts the instances of a type with a pod.
+	CountByType(ctx context.Context, instanceType string) (int, error)
+	// CountQueued returns the length of the waiting queue.
+	CountQueued(ctx context.Context) (int, error)
+	// SumResources sums the resources requested by instances with a pod.
+	SumResources(ctx context.Context) (model.Resources, error)
+	// SumResourcesByUser sums the resources requested by the user's instances that are not stopped, including queued ones.
+	SumResourcesByUser(ctx context.Context, userID string) (model.Resources, error)
+}
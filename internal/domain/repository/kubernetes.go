@@ -12,10 +12,47 @@ type KubernetesClient interface {
 
 	GetPodIP(ctx context.Context, podName string) (string, error)
 
-	GetPodStatus(ctx context.Context, podName string) (model.InstanceStatus, string, error)
+	// GetPodStatus returns the status of the pod with the reason it is not running yet, or nil if the pod doesn't exist.
+	GetPodStatus(ctx context.Context, podName string) (*model.PodStatus, error)
 
-	DeletePod(ctx context.Context, podName string) error
+	// DeleteInstanceWorkload deletes the pod or workload of the instance.
+	DeleteInstanceWorkload(ctx context.Context, instance *model.Instance) error
+
+	// InstanceWorkloadExists returns true if the workload of the instance exists, even while it has no pod.
+	InstanceWorkloadExists(ctx context.Context, instance *model.Instance) (bool, error)
+
+	// DeleteInstanceResources deletes the resources created from the instance's template besides its pod.
+	DeleteInstanceResources(ctx context.Context, instance *model.Instance) error
 
 	ListInstancePods(ctx context.Context) ([]*model.Instance, error)
 
+	WatchInstancePods(ctx context.Context, handler InstancePodEventHandler) error
+
+	ListUserVolumes(ctx context.Context, userID string) ([]*model.Volume, error)
+
+	DeleteVolume(ctx context.Context, volumeName string) error
+
+	// ValidateInstancePod checks a rendered pod template without creating anything.
+	ValidateInstancePod(ctx context.Context, instanceType string, template []byte, targetPort string) ([]model.TemplateProblem, error)
+
+	// ListWarmPoolPods returns the unclaimed warm pool pods, excluding terminating ones.
+	ListWarmPoolPods(ctx context.Context) ([]*model.WarmPoolPod, error)
+
+	// CreateWarmPoolPod starts an unclaimed pod of the instance type from the rendered template.
+	CreateWarmPoolPod(ctx context.Context, instanceType string, template []byte) error
+
+	// ClaimWarmPoolPod hands an unclaimed pod of the instance's type started from the same template over to the instance,
+	// setting its PodName, and its status and PodIP if the pod is ready. It returns false if there is no pod to claim.
+	ClaimWarmPoolPod(ctx context.Context, instance *model.Instance, template []byte) (bool, error)
+
+	DeleteWarmPoolPod(ctx context.Context, podName string) error
+
+}
+
+// InstancePodEventHandler receives instance pod changes observed by the KubernetesClient.
+type InstancePodEventHandler interface {
+	// OnInstancePodChanged is called when an instance pod is added or its status changes.
+	OnInstancePodChanged(ctx context.Context, instance *model.Instance)
+	// OnInstancePodRemoved is called when an instance pod is deleted, terminating or finished.
+	OnInstancePodRemoved(ctx context.Context, instanceID string)
 }
@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// TemplateSource provides the pod template the instance types are parsed from.
+type TemplateSource interface {
+	Read(ctx context.Context) ([]byte, error)
+	// Watch calls onChange whenever the template may have changed, until ctx is done.
+	Watch(ctx context.Context, onChange func(ctx context.Context))
+}
@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// migrationLockID is the PostgreSQL advisory lock key used to serialize migrations across replicas.
+const migrationLockID = 0x68616b6f6e697761 // "hakoniwa"
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open opens the database for the given driver and applies pending migrations.
+// Queries use $N placeholders, which both SQLite and PostgreSQL accept.
+func Open(ctx context.Context, driver, dsn string) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+	switch driver {
+	case "sqlite":
+		db, err = sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("database.Open: failed to open sqlite: %w", err)
+		}
+		// SQLite only allows a single writer; serialize access to avoid SQLITE_BUSY.
+		db.SetMaxOpenConns(1)
+		if _, err := db.ExecContext(ctx, "PRAGMA journal_mode = WAL"); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("database.Open: failed to enable WAL: %w", err)
+		}
+		if _, err := db.ExecContext(ctx, "PRAGMA busy_timeout = 5000"); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("database.Open: failed to set busy timeout: %w", err)
+		}
+	case "postgres":
+		db, err = sql.Open("pgx", dsn)
+		if err != nil {
+			return nil, fmt.Errorf("database.Open: failed to open postgres: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("database.Open: unsupported driver: %s", driver)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("database.Open: failed to ping database: %w", err)
+	}
+
+	if err := migrate(ctx, db, driver); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migrate applies embedded migrations that have not been recorded in schema_migrations yet.
+// Migration files are named "<version>_<description>.sql" and applied in version order.
+func migrate(ctx context.Context, db *sql.DB, driver string) error {
+	// Use a single connection so a session-level lock covers every migration.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("database.migrate: failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if driver == "postgres" {
+		// Replicas starting at the same time must not apply migrations concurrently.
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+			return fmt.Errorf("database.migrate: failed to acquire migration lock: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+	}
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    applied_at BIGINT NOT NULL
+)`); err != nil {
+		return fmt.Errorf("database.migrate: failed to create schema_migrations: %w", err)
+	}
+
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("database.migrate: failed to read migrations: %w", err)
+	}
+
+	type migration struct {
+		version int64
+		name    string
+	}
+	var pending []migration
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok {
+			return fmt.Errorf("database.migrate: invalid migration file name: %s", e.Name())
+		}
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return fmt.Errorf("database.migrate: invalid migration version in %s: %w", e.Name(), err)
+		}
+		pending = append(pending, migration{version: version, name: e.Name()})
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].version < pending[j].version
+	})
+
+	for _, m := range pending {
+		var applied int
+		if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations WHERE version = $1", m.version).Scan(&applied); err != nil {
+			return fmt.Errorf("database.migrate: failed to check migration %s: %w", m.name, err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		content, err := migrations.ReadFile("migrations/" + m.name)
+		if err != nil {
+			return fmt.Errorf("database.migrate: failed to read migration %s: %w", m.name, err)
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("database.migrate: failed to begin transaction: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("database.migrate: failed to apply migration %s: %w", m.name, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)", m.version, time.Now().UnixMilli()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("database.migrate: failed to record migration %s: %w", m.name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("database.migrate: failed to commit migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
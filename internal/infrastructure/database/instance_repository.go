@@ -0,0 +1,440 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+)
+
+const instanceColumns = "instance_id, user_id, type, display_name, pod_name, pod_ip, status, last_active_at, created_at, started_at, reap_reason, expires_at, extended_until, parameters, target_port, template, workload, cpu_millis, memory_bytes, queue_priority, status_reason, status_message, startup_phases, base_url"
+
+// reservationLockID is the PostgreSQL advisory lock that serializes reservations across replicas.
+const reservationLockID = 7427150617
+
+// querier runs queries on the database or in a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type InstanceRepository struct {
+	instanceCounter
+	db     *sql.DB
+	driver string
+}
+
+func NewInstanceRepository(db *sql.DB, driver string) *InstanceRepository {
+	return &InstanceRepository{
+		instanceCounter: instanceCounter{q: db},
+		db:              db,
+		driver:          driver,
+	}
+}
+
+// Save upserts every column of the instance; it is for new instances, which no other replica updates yet.
+func (r *InstanceRepository) Save(ctx context.Context, instance *model.Instance) error {
+	if err := saveInstance(ctx, r.db, instance); err != nil {
+		return fmt.Errorf("database.Save: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) ListQueued(ctx context.Context) ([]*model.Instance, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+instanceColumns+" FROM instances WHERE status = $1 ORDER BY queue_priority DESC, started_at", string(model.InstanceStatusQueued))
+	if err != nil {
+		return nil, fmt.Errorf("database.ListQueued: failed to query instances: %w", err)
+	}
+	return scanInstances(rows)
+}
+
+func (r *InstanceRepository) Reserve(ctx context.Context, instance *model.Instance, check func(ctx context.Context, counter repository.InstanceCounter) error) error {
+	// SQLite has a single connection, so the transaction alone serializes reservations.
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("database.Reserve: failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if r.driver == "postgres" {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", reservationLockID); err != nil {
+			return fmt.Errorf("database.Reserve: failed to acquire reservation lock: %w", err)
+		}
+	}
+	if err := check(ctx, instanceCounter{q: tx}); err != nil {
+		return err
+	}
+	if err := saveInstance(ctx, tx, instance); err != nil {
+		return fmt.Errorf("database.Reserve: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database.Reserve: failed to commit reservation: %w", err)
+	}
+	return nil
+}
+
+func saveInstance(ctx context.Context, q querier, instance *model.Instance) error {
+	var parameters string
+	if len(instance.Parameters) > 0 {
+		b, err := json.Marshal(instance.Parameters)
+		if err != nil {
+			return fmt.Errorf("failed to encode parameters: %w", err)
+		}
+		parameters = string(b)
+	}
+	startupPhases, err := encodeStartupPhases(instance.StartupPhases)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx, `INSERT INTO instances (`+instanceColumns+`)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
+ON CONFLICT (instance_id) DO UPDATE SET
+    user_id = excluded.user_id,
+    type = excluded.type,
+    display_name = excluded.display_name,
+    pod_name = excluded.pod_name,
+    pod_ip = excluded.pod_ip,
+    status = excluded.status,
+    last_active_at = excluded.last_active_at,
+    created_at = excluded.created_at,
+    started_at = excluded.started_at,
+    reap_reason = excluded.reap_reason,
+    expires_at = excluded.expires_at,
+    extended_until = excluded.extended_until,
+    parameters = excluded.parameters,
+    target_port = excluded.target_port,
+    template = excluded.template,
+    workload = excluded.workload,
+    cpu_millis = excluded.cpu_millis,
+    memory_bytes = excluded.memory_bytes,
+    queue_priority = excluded.queue_priority,
+    status_reason = excluded.status_reason,
+    status_message = excluded.status_message,
+    startup_phases = excluded.startup_phases,
+    base_url = excluded.base_url`,
+		instance.InstanceID,
+		instance.UserID,
+		instance.Type,
+		instance.DisplayName,
+		instance.PodName,
+		instance.PodIP,
+		string(instance.Status),
+		toMillis(instance.LastActiveAt),
+		toMillis(instance.CreatedAt),
+		toMillis(instance.StartedAt),
+		string(instance.ReapReason),
+		toMillis(instance.ExpiresAt),
+		toMillis(instance.ExtendedUntil),
+		parameters,
+		instance.TargetPort,
+		string(instance.Template),
+		string(instance.Workload),
+		instance.Resources.CPUMillis,
+		instance.Resources.MemoryBytes,
+		instance.QueuePriority,
+		instance.StatusReason,
+		instance.StatusMessage,
+		startupPhases,
+		instance.BaseURL,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save instance: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) FindByID(ctx context.Context, instanceID string) (*model.Instance, error) {
+	row := r.db.QueryRowContext(ctx, "SELECT "+instanceColumns+" FROM instances WHERE instance_id = $1", instanceID)
+	instance, err := scanInstance(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("instance not found: %s", instanceID)
+		}
+		return nil, fmt.Errorf("database.FindByID: failed to find instance: %w", err)
+	}
+	return instance, nil
+}
+
+func (r *InstanceRepository) FindByUser(ctx context.Context, userID string) ([]*model.Instance, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+instanceColumns+" FROM instances WHERE user_id = $1 ORDER BY created_at", userID)
+	if err != nil {
+		return nil, fmt.Errorf("database.FindByUser: failed to query instances: %w", err)
+	}
+	return scanInstances(rows)
+}
+
+func (r *InstanceRepository) List(ctx context.Context) ([]*model.Instance, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+instanceColumns+" FROM instances ORDER BY created_at")
+	if err != nil {
+		return nil, fmt.Errorf("database.List: failed to query instances: %w", err)
+	}
+	return scanInstances(rows)
+}
+
+func (r *InstanceRepository) Delete(ctx context.Context, instanceID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM instances WHERE instance_id = $1", instanceID); err != nil {
+		return fmt.Errorf("database.Delete: failed to delete instance: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) UpdateLastActiveAt(ctx context.Context, instanceID string, lastActiveAt time.Time) error {
+	// Only touch the timestamp so concurrent status updates from other replicas are not overwritten.
+	if _, err := r.db.ExecContext(ctx, "UPDATE instances SET last_active_at = $1 WHERE instance_id = $2", toMillis(lastActiveAt), instanceID); err != nil {
+		return fmt.Errorf("database.UpdateLastActiveAt: failed to update instance: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) UpdateExpiresAt(ctx context.Context, instanceID string, expiresAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE instances SET expires_at = $1 WHERE instance_id = $2", toMillis(expiresAt), instanceID); err != nil {
+		return fmt.Errorf("database.UpdateExpiresAt: failed to update instance: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) UpdateLease(ctx context.Context, instanceID string, expiresAt, extendedUntil time.Time) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE instances SET expires_at = $1, extended_until = $2 WHERE instance_id = $3", toMillis(expiresAt), toMillis(extendedUntil), instanceID); err != nil {
+		return fmt.Errorf("database.UpdateLease: failed to update instance: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) UpdateStatus(ctx context.Context, instance *model.Instance, expected model.InstanceStatus) error {
+	startupPhases, err := encodeStartupPhases(instance.StartupPhases)
+	if err != nil {
+		return fmt.Errorf("database.UpdateStatus: %w", err)
+	}
+	// Only touch the status so last_active_at and expires_at written by other replicas are not rolled back.
+	result, err := r.db.ExecContext(ctx, `UPDATE instances SET status = $1, pod_name = $2, pod_ip = $3, status_reason = $4,
+    status_message = $5, startup_phases = $6, reap_reason = $7 WHERE instance_id = $8 AND status = $9`,
+		string(instance.Status),
+		instance.PodName,
+		instance.PodIP,
+		instance.StatusReason,
+		instance.StatusMessage,
+		startupPhases,
+		string(instance.ReapReason),
+		instance.InstanceID,
+		string(expected),
+	)
+	if err != nil {
+		return fmt.Errorf("database.UpdateStatus: failed to update instance: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database.UpdateStatus: failed to update instance: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("database.UpdateStatus: instance %s is not %s: %w", instance.InstanceID, expected, model.ErrInvalidInstanceState)
+	}
+	return nil
+}
+
+func (r *InstanceRepository) Requeue(ctx context.Context, instance *model.Instance) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE instances SET status = $1, pod_name = '', pod_ip = '', started_at = $2 WHERE instance_id = $3 AND status = $4",
+		string(model.InstanceStatusQueued), toMillis(instance.StartedAt), instance.InstanceID, string(model.InstanceStatusPending))
+	if err != nil {
+		return fmt.Errorf("database.Requeue: failed to update instance: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("database.Requeue: failed to update instance: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("database.Requeue: instance %s is not %s: %w", instance.InstanceID, model.InstanceStatusPending, model.ErrInvalidInstanceState)
+	}
+	return nil
+}
+
+// instanceCounter counts instances on the database, or in the transaction of a reservation.
+type instanceCounter struct {
+	q querier
+}
+
+func (c instanceCounter) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := c.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM instances WHERE status NOT IN ($1, $2) AND status_reason <> $3", string(model.InstanceStatusStopped), string(model.InstanceStatusQueued), model.StatusReasonProvisioningFailed).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database.Count: failed to count instances: %w", err)
+	}
+	return count, nil
+}
+
+func (c instanceCounter) CountByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+	if err := c.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM instances WHERE user_id = $1", userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database.CountByUser: failed to count instances: %w", err)
+	}
+	return count, nil
+}
+
+func (c instanceCounter) CountByUserAndType(ctx context.Context, userID, instanceType string) (int, error) {
+	var count int
+	if err := c.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM instances WHERE user_id = $1 AND type = $2", userID, instanceType).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database.CountByUserAndType: failed to count instances: %w", err)
+	}
+	return count, nil
+}
+
+func (c instanceCounter) CountByUserAndTypeWithPod(ctx context.Context, userID, instanceType string) (int, error) {
+	var count int
+	if err := c.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM instances WHERE user_id = $1 AND type = $2 AND status NOT IN ($3, $4) AND status_reason <> $5", userID, instanceType, string(model.InstanceStatusStopped), string(model.InstanceStatusQueued), model.StatusReasonProvisioningFailed).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database.CountByUserAndTypeWithPod: failed to count instances: %w", err)
+	}
+	return count, nil
+}
+
+func (c instanceCounter) CountByType(ctx context.Context, instanceType string) (int, error) {
+	var count int
+	if err := c.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM instances WHERE type = $1 AND status NOT IN ($2, $3) AND status_reason <> $4", instanceType, string(model.InstanceStatusStopped), string(model.InstanceStatusQueued), model.StatusReasonProvisioningFailed).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database.CountByType: failed to count instances: %w", err)
+	}
+	return count, nil
+}
+
+func (c instanceCounter) CountQueued(ctx context.Context) (int, error) {
+	var count int
+	if err := c.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM instances WHERE status = $1", string(model.InstanceStatusQueued)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("database.CountQueued: failed to count instances: %w", err)
+	}
+	return count, nil
+}
+
+func (c instanceCounter) SumResources(ctx context.Context) (model.Resources, error) {
+	var sum model.Resources
+	if err := c.q.QueryRowContext(ctx, "SELECT CAST(COALESCE(SUM(cpu_millis), 0) AS BIGINT), CAST(COALESCE(SUM(memory_bytes), 0) AS BIGINT) FROM instances WHERE status NOT IN ($1, $2) AND status_reason <> $3", string(model.InstanceStatusStopped), string(model.InstanceStatusQueued), model.StatusReasonProvisioningFailed).Scan(&sum.CPUMillis, &sum.MemoryBytes); err != nil {
+		return model.Resources{}, fmt.Errorf("database.SumResources: failed to sum resources: %w", err)
+	}
+	return sum, nil
+}
+
+func (c instanceCounter) SumResourcesByUser(ctx context.Context, userID string) (model.Resources, error) {
+	var sum model.Resources
+	if err := c.q.QueryRowContext(ctx, "SELECT CAST(COALESCE(SUM(cpu_millis), 0) AS BIGINT), CAST(COALESCE(SUM(memory_bytes), 0) AS BIGINT) FROM instances WHERE user_id = $1 AND status <> $2 AND status_reason <> $3", userID, string(model.InstanceStatusStopped), model.StatusReasonProvisioningFailed).Scan(&sum.CPUMillis, &sum.MemoryBytes); err != nil {
+		return model.Resources{}, fmt.Errorf("database.SumResourcesByUser: failed to sum resources: %w", err)
+	}
+	return sum, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInstance(row rowScanner) (*model.Instance, error) {
+	var instance model.Instance
+	var status, reapReason, parameters, template, workload, startupPhases string
+	var lastActiveAt, createdAt, startedAt, expiresAt, extendedUntil int64
+	if err := row.Scan(
+		&instance.InstanceID,
+		&instance.UserID,
+		&instance.Type,
+		&instance.DisplayName,
+		&instance.PodName,
+		&instance.PodIP,
+		&status,
+		&lastActiveAt,
+		&createdAt,
+		&startedAt,
+		&reapReason,
+		&expiresAt,
+		&extendedUntil,
+		&parameters,
+		&instance.TargetPort,
+		&template,
+		&workload,
+		&instance.Resources.CPUMillis,
+		&instance.Resources.MemoryBytes,
+		&instance.QueuePriority,
+		&instance.StatusReason,
+		&instance.StatusMessage,
+		&startupPhases,
+		&instance.BaseURL,
+	); err != nil {
+		return nil, err
+	}
+	instance.Status = model.InstanceStatus(status)
+	instance.LastActiveAt = fromMillis(lastActiveAt)
+	instance.CreatedAt = fromMillis(createdAt)
+	instance.StartedAt = fromMillis(startedAt)
+	instance.ReapReason = model.ReapReason(reapReason)
+	instance.ExpiresAt = fromMillis(expiresAt)
+	instance.ExtendedUntil = fromMillis(extendedUntil)
+	instance.Workload = model.WorkloadKind(workload)
+	if template != "" {
+		instance.Template = []byte(template)
+	}
+	if parameters != "" {
+		if err := json.Unmarshal([]byte(parameters), &instance.Parameters); err != nil {
+			return nil, fmt.Errorf("database.scanInstance: failed to decode parameters: %w", err)
+		}
+	}
+	if startupPhases != "" {
+		var phases []startupPhaseRecord
+		if err := json.Unmarshal([]byte(startupPhases), &phases); err != nil {
+			return nil, fmt.Errorf("database.scanInstance: failed to decode startup phases: %w", err)
+		}
+		for _, p := range phases {
+			instance.StartupPhases = append(instance.StartupPhases, model.StartupPhaseTime{Phase: model.StartupPhase(p.Phase), At: fromMillis(p.At)})
+		}
+	}
+	return &instance, nil
+}
+
+// startupPhaseRecord stores a startup phase as JSON, with its time in Unix milliseconds like the other timestamps.
+type startupPhaseRecord struct {
+	Phase string `json:"phase"`
+	At    int64  `json:"at"`
+}
+
+func encodeStartupPhases(phases []model.StartupPhaseTime) (string, error) {
+	if len(phases) == 0 {
+		return "", nil
+	}
+	records := make([]startupPhaseRecord, len(phases))
+	for i, p := range phases {
+		records[i] = startupPhaseRecord{Phase: string(p.Phase), At: toMillis(p.At)}
+	}
+	b, err := json.Marshal(records)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode startup phases: %w", err)
+	}
+	return string(b), nil
+}
+
+func scanInstances(rows *sql.Rows) ([]*model.Instance, error) {
+	defer rows.Close()
+
+	var result []*model.Instance
+	for rows.Next() {
+		instance, err := scanInstance(rows)
+		if err != nil {
+			return nil, fmt.Errorf("database.scanInstances: failed to scan instance: %w", err)
+		}
+		result = append(result, instance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database.scanInstances: failed to iterate instances: %w", err)
+	}
+	return result, nil
+}
+
+// toMillis stores timestamps as Unix milliseconds; the zero time is stored as 0.
+func toMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromMillis(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(v)
+}
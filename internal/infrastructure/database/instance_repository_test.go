@@ -0,0 +1,159 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/database"
+)
+
+func TestInstanceRepository_PersistsAcrossReopen(t *testing.T) {
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "hakoniwa.db")
+
+	db, err := database.Open(ctx, "sqlite", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	lastActiveAt := time.Now().Add(-30 * time.Minute).Truncate(time.Millisecond)
+	createdAt := time.Now().Add(-2 * time.Hour).Truncate(time.Millisecond)
+	repo := database.NewInstanceRepository(db, "sqlite")
+	if err := repo.Save(ctx, &model.Instance{
+		InstanceID:    "instance-1",
+		UserID:        "user-1",
+		Type:          "webtop",
+		DisplayName:   "Webtop",
+		PodName:       "hakoniwa-instance-1",
+		PodIP:         "10.0.0.1",
+		Status:        model.InstanceStatusFailed,
+		StatusReason:  "ImagePullBackOff",
+		StatusMessage: "container webtop: image not found",
+		LastActiveAt:  lastActiveAt,
+		CreatedAt:     createdAt,
+		Resources:     model.Resources{CPUMillis: 1500, MemoryBytes: 4 << 30},
+		BaseURL:       "/w/instance-1/",
+		StartupPhases: []model.StartupPhaseTime{
+			{Phase: model.StartupPhaseScheduled, At: createdAt},
+			{Phase: model.StartupPhasePullingImage, At: createdAt.Add(time.Second)},
+		},
+	}); err != nil {
+		t.Fatalf("Failed to save instance: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Failed to close database: %v", err)
+	}
+
+	// Reopen to simulate a restart; migrations must be idempotent.
+	db, err = database.Open(ctx, "sqlite", dsn)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+	repo = database.NewInstanceRepository(db, "sqlite")
+
+	got, err := repo.FindByID(ctx, "instance-1")
+	if err != nil {
+		t.Fatalf("Failed to find instance: %v", err)
+	}
+	if got.UserID != "user-1" || got.Type != "webtop" || got.Status != model.InstanceStatusFailed ||
+		got.StatusReason != "ImagePullBackOff" || got.StatusMessage != "container webtop: image not found" || got.BaseURL != "/w/instance-1/" {
+		t.Errorf("Unexpected instance: %+v", got)
+	}
+	if !got.LastActiveAt.Equal(lastActiveAt) {
+		t.Errorf("Expected LastActiveAt %v, got %v", lastActiveAt, got.LastActiveAt)
+	}
+	if !got.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected CreatedAt %v, got %v", createdAt, got.CreatedAt)
+	}
+	if got.StartupPhase() != model.StartupPhasePullingImage || !got.StartupPhases[1].At.Equal(createdAt.Add(time.Second)) {
+		t.Errorf("Unexpected startup phases: %+v", got.StartupPhases)
+	}
+
+	count, err := repo.CountByUserAndType(ctx, "user-1", "webtop")
+	if err != nil {
+		t.Fatalf("Failed to count instances: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected count 1, got %d", count)
+	}
+
+	sum, err := repo.SumResourcesByUser(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Failed to sum resources: %v", err)
+	}
+	if sum != (model.Resources{CPUMillis: 1500, MemoryBytes: 4 << 30}) {
+		t.Errorf("Unexpected resources: %+v", sum)
+	}
+
+	if err := repo.Delete(ctx, "instance-1"); err != nil {
+		t.Fatalf("Failed to delete instance: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, "instance-1"); err == nil {
+		t.Errorf("Expected error for deleted instance")
+	}
+}
+
+func TestInstanceRepository_UpdateStatusKeepsActivity(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.Open(ctx, "sqlite", filepath.Join(t.TempDir(), "hakoniwa.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+	repo := database.NewInstanceRepository(db, "sqlite")
+
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	if err := repo.Save(ctx, &model.Instance{
+		InstanceID:   "instance-1",
+		UserID:       "user-1",
+		Status:       model.InstanceStatusPending,
+		LastActiveAt: createdAt,
+		CreatedAt:    createdAt,
+	}); err != nil {
+		t.Fatalf("Failed to save instance: %v", err)
+	}
+	snapshot, err := repo.FindByID(ctx, "instance-1")
+	if err != nil {
+		t.Fatalf("Failed to find instance: %v", err)
+	}
+
+	// Another replica proxies requests to the instance while the syncer updates a stale snapshot
+	lastActiveAt := time.Now().Truncate(time.Millisecond)
+	if err := repo.UpdateLastActiveAt(ctx, "instance-1", lastActiveAt); err != nil {
+		t.Fatalf("Failed to update activity: %v", err)
+	}
+	if err := repo.UpdateExpiresAt(ctx, "instance-1", lastActiveAt.Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to update expiry: %v", err)
+	}
+	snapshot.Status = model.InstanceStatusRunning
+	snapshot.PodIP = "10.0.0.1"
+	snapshot.StartupPhases = []model.StartupPhaseTime{{Phase: model.StartupPhaseReady, At: lastActiveAt}}
+	if err := repo.UpdateStatus(ctx, snapshot, model.InstanceStatusPending); err != nil {
+		t.Fatalf("Failed to update status: %v", err)
+	}
+
+	got, err := repo.FindByID(ctx, "instance-1")
+	if err != nil {
+		t.Fatalf("Failed to find instance: %v", err)
+	}
+	if got.Status != model.InstanceStatusRunning || got.PodIP != "10.0.0.1" || got.StartupPhase() != model.StartupPhaseReady {
+		t.Errorf("Expected the status to be updated, got %+v", got)
+	}
+	if !got.LastActiveAt.Equal(lastActiveAt) || !got.ExpiresAt.Equal(lastActiveAt.Add(time.Hour)) {
+		t.Errorf("Expected the activity of the other replica to be kept, got LastActiveAt %v and ExpiresAt %v", got.LastActiveAt, got.ExpiresAt)
+	}
+
+	// A snapshot taken before the instance started doesn't roll its status back
+	snapshot.Status = model.InstanceStatusStopped
+	if err := repo.UpdateStatus(ctx, snapshot, model.InstanceStatusPending); !errors.Is(err, model.ErrInvalidInstanceState) {
+		t.Errorf("Expected the stale update to be refused, got %v", err)
+	}
+	if got, _ := repo.FindByID(ctx, "instance-1"); got.Status != model.InstanceStatusRunning {
+		t.Errorf("Expected the instance to stay running, got %s", got.Status)
+	}
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS instances (
+    instance_id    TEXT PRIMARY KEY,
+    user_id        TEXT NOT NULL,
+    type           TEXT NOT NULL,
+    display_name   TEXT NOT NULL,
+    pod_name       TEXT NOT NULL,
+    pod_ip         TEXT NOT NULL,
+    status         TEXT NOT NULL,
+    last_active_at BIGINT NOT NULL,
+    created_at     BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_instances_user_id ON instances (user_id);
+CREATE INDEX IF NOT EXISTS idx_instances_last_active_at ON instances (last_active_at);
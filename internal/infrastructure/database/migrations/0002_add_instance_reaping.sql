@@ -0,0 +1,4 @@
+ALTER TABLE instances ADD COLUMN started_at BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE instances ADD COLUMN reap_reason TEXT NOT NULL DEFAULT '';
+
+UPDATE instances SET started_at = created_at;
@@ -0,0 +1,2 @@
+ALTER TABLE instances ADD COLUMN expires_at BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE instances ADD COLUMN extended_until BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE instances ADD COLUMN parameters TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE instances ADD COLUMN target_port TEXT NOT NULL DEFAULT '';
+ALTER TABLE instances ADD COLUMN template TEXT NOT NULL DEFAULT '';
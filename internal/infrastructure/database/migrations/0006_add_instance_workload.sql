@@ -0,0 +1 @@
+ALTER TABLE instances ADD COLUMN workload TEXT NOT NULL DEFAULT 'pod';
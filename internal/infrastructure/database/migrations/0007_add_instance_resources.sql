@@ -0,0 +1,2 @@
+ALTER TABLE instances ADD COLUMN cpu_millis BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE instances ADD COLUMN memory_bytes BIGINT NOT NULL DEFAULT 0;
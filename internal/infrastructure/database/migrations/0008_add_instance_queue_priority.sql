@@ -0,0 +1 @@
+ALTER TABLE instances ADD COLUMN queue_priority BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE instances ADD COLUMN status_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE instances ADD COLUMN status_message TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE instances ADD COLUMN startup_phases TEXT NOT NULL DEFAULT '';
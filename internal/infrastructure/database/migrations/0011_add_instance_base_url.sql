@@ -0,0 +1 @@
+ALTER TABLE instances ADD COLUMN base_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,63 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+)
+
+// TemplateSource reads the pod template from POD_TEMPLATE_PATH (or the embedded default)
+// and polls the file for changes.
+type TemplateSource struct {
+	path     string
+	interval time.Duration
+}
+
+func NewTemplateSource(path string, interval time.Duration) *TemplateSource {
+	return &TemplateSource{
+		path:     path,
+		interval: interval,
+	}
+}
+
+func (s *TemplateSource) Read(ctx context.Context) ([]byte, error) {
+	if s.path == "" {
+		return config.DefaultPodTemplate(), nil
+	}
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("file.TemplateSource.Read: failed to read pod template %s: %w", s.path, err)
+	}
+	return content, nil
+}
+
+func (s *TemplateSource) Watch(ctx context.Context, onChange func(ctx context.Context)) {
+	if s.path == "" || s.interval <= 0 {
+		// The embedded default never changes
+		<-ctx.Done()
+		return
+	}
+
+	// Compare contents rather than modification times, as mounted ConfigMaps are updated by swapping symlinks.
+	last, _ := os.ReadFile(s.path)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			content, err := os.ReadFile(s.path)
+			if err != nil || bytes.Equal(content, last) {
+				continue
+			}
+			last = content
+			onChange(ctx)
+		}
+	}
+}
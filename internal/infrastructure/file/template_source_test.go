@@ -0,0 +1,29 @@
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/infrastructure/file"
+)
+
+func TestTemplateSource_ReadsConfiguredPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod_template.yaml")
+	if err := os.WriteFile(path, []byte("kind: Pod\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write pod template: %v", err)
+	}
+
+	content, err := file.NewTemplateSource(path, 0).Read(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read pod template: %v", err)
+	}
+	if string(content) != "kind: Pod\n" {
+		t.Errorf("Expected the configured pod template, got %q", content)
+	}
+
+	if _, err := file.NewTemplateSource(filepath.Join(t.TempDir(), "missing.yaml"), 0).Read(context.Background()); err == nil {
+		t.Errorf("Expected an error for a missing pod template")
+	}
+}
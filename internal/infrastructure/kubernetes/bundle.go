@@ -0,0 +1,223 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const (
+	instanceIDLabelKey = "hakoniwa.aplulu.me/instance-id"
+	fieldManager       = "hakoniwa"
+)
+
+// decodeTemplate splits a rendered template into the pod and the resources bundled with it.
+// A template is either a single pod or a List whose first item is the pod.
+func decodeTemplate(templateContent []byte) (*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(templateContent), 4096)
+	var u unstructured.Unstructured
+	if err := decoder.Decode(&u); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode pod template: %w", err)
+	}
+	if !u.IsList() {
+		return &u, nil, nil
+	}
+
+	list, err := u.ToList()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode pod template: %w", err)
+	}
+	if len(list.Items) == 0 || list.GetKind() != "List" || list.Items[0].GetKind() != "Pod" {
+		return nil, nil, fmt.Errorf("failed to decode pod template: the first item must be a pod")
+	}
+	pod := &list.Items[0]
+	resources := make([]*unstructured.Unstructured, 0, len(list.Items)-1)
+	for i := 1; i < len(list.Items); i++ {
+		resources = append(resources, &list.Items[i])
+	}
+	return pod, resources, nil
+}
+
+// instanceResourceName derives the name of a bundled resource from the name of the instance's pod or workload,
+// so each instance gets its own copy and the name is stable across restarts.
+func instanceResourceName(objectName, name string) string {
+	return objectName + "-" + name
+}
+
+// buildInstanceResources names and labels the bundled resources for the instance and returns
+// the renamed resources keyed by their template kind and name.
+func buildInstanceResources(instance *model.Instance, podName string, resources []*unstructured.Unstructured) map[string]string {
+	renamed := make(map[string]string, len(resources))
+	for _, u := range resources {
+		name := instanceResourceName(podName, u.GetName())
+		renamed[u.GetKind()+"/"+u.GetName()] = name
+		u.SetName(name)
+
+		labels := u.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[ManagedByLabelKey] = "hakoniwa"
+		labels["hakoniwa.aplulu.me/user-id"] = sanitizeUserID(instance.UserID)
+		labels[instanceIDLabelKey] = instance.InstanceID
+		u.SetLabels(labels)
+
+		annotations := u.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[UserIDAnnotationKey] = instance.UserID
+		annotations["hakoniwa.aplulu.me/instance-id"] = instance.InstanceID
+		annotations["hakoniwa.aplulu.me/instance-type"] = instance.Type
+		u.SetAnnotations(annotations)
+
+		// A template can't know the instance, so services are narrowed to the instance pod.
+		if u.GetKind() == "Service" {
+			selector, _, _ := unstructured.NestedStringMap(u.Object, "spec", "selector")
+			if selector == nil {
+				selector = make(map[string]string)
+			}
+			selector[instanceIDLabelKey] = instance.InstanceID
+			_ = unstructured.SetNestedStringMap(u.Object, selector, "spec", "selector")
+		}
+	}
+	return renamed
+}
+
+// renamePodReferences points the pod's references to bundled ConfigMaps, Secrets and
+// PersistentVolumeClaims at the instance's copies.
+func renamePodReferences(pod *corev1.Pod, renamed map[string]string) {
+	rename := func(kind string, name *string) {
+		if n, ok := renamed[kind+"/"+*name]; ok {
+			*name = n
+		}
+	}
+
+	for i := range pod.Spec.Volumes {
+		v := &pod.Spec.Volumes[i]
+		if v.ConfigMap != nil {
+			rename("ConfigMap", &v.ConfigMap.Name)
+		}
+		if v.Secret != nil {
+			rename("Secret", &v.Secret.SecretName)
+		}
+		if v.PersistentVolumeClaim != nil {
+			rename("PersistentVolumeClaim", &v.PersistentVolumeClaim.ClaimName)
+		}
+		if v.Projected != nil {
+			for j := range v.Projected.Sources {
+				src := &v.Projected.Sources[j]
+				if src.ConfigMap != nil {
+					rename("ConfigMap", &src.ConfigMap.Name)
+				}
+				if src.Secret != nil {
+					rename("Secret", &src.Secret.Name)
+				}
+			}
+		}
+	}
+
+	for i := range pod.Spec.ImagePullSecrets {
+		rename("Secret", &pod.Spec.ImagePullSecrets[i].Name)
+	}
+
+	containers := make([]*corev1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for i := range pod.Spec.InitContainers {
+		containers = append(containers, &pod.Spec.InitContainers[i])
+	}
+	for i := range pod.Spec.Containers {
+		containers = append(containers, &pod.Spec.Containers[i])
+	}
+	for _, c := range containers {
+		for j := range c.Env {
+			if from := c.Env[j].ValueFrom; from != nil {
+				if from.ConfigMapKeyRef != nil {
+					rename("ConfigMap", &from.ConfigMapKeyRef.Name)
+				}
+				if from.SecretKeyRef != nil {
+					rename("Secret", &from.SecretKeyRef.Name)
+				}
+			}
+		}
+		for j := range c.EnvFrom {
+			if c.EnvFrom[j].ConfigMapRef != nil {
+				rename("ConfigMap", &c.EnvFrom[j].ConfigMapRef.Name)
+			}
+			if c.EnvFrom[j].SecretRef != nil {
+				rename("Secret", &c.EnvFrom[j].SecretRef.Name)
+			}
+		}
+	}
+}
+
+// resourceClient returns the dynamic client for a bundled resource in the instance namespace.
+func (c *Client) resourceClient(u *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := u.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource kind %s: %w", gvk, err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return nil, fmt.Errorf("%s is not namespaced; only namespaced resources can be bundled", gvk.Kind)
+	}
+	return c.dynamicClient.Resource(mapping.Resource).Namespace(c.namespace), nil
+}
+
+// applyInstanceResources creates or updates the bundled resources, owned by the pod or workload
+// so they are garbage collected together with it.
+// Server-side apply takes over resources left behind by a previous pod of the same instance.
+func (c *Client) applyInstanceResources(ctx context.Context, owner metav1.OwnerReference, resources []*unstructured.Unstructured) error {
+	for _, u := range resources {
+		u.SetOwnerReferences([]metav1.OwnerReference{owner})
+		ri, err := c.resourceClient(u)
+		if err != nil {
+			return err
+		}
+		if _, err := ri.Apply(ctx, u.GetName(), u, metav1.ApplyOptions{FieldManager: fieldManager, Force: true}); err != nil {
+			return fmt.Errorf("failed to apply %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// DeleteInstanceResources deletes the resources bundled with the instance's template.
+// They are normally garbage collected with the pod; this cleans up after pods that
+// were removed without cascading.
+func (c *Client) DeleteInstanceResources(ctx context.Context, instance *model.Instance) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.DeleteInstanceResources: k8s client not configured (no-op mode)")
+	}
+	if len(instance.Template) == 0 {
+		return nil
+	}
+	_, resources, err := decodeTemplate(instance.Template)
+	if err != nil {
+		return fmt.Errorf("kubernetes.DeleteInstanceResources: %w", err)
+	}
+
+	for _, u := range resources {
+		ri, err := c.resourceClient(u)
+		if err != nil {
+			return fmt.Errorf("kubernetes.DeleteInstanceResources: %w", err)
+		}
+		name := instanceResourceName(instanceObjectName(instance.InstanceID), u.GetName())
+		err = ri.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("kubernetes.DeleteInstanceResources: failed to delete %s %s: %w", u.GetKind(), name, err)
+		}
+		if err == nil {
+			c.logger.Info("Deleted instance resource", "kind", u.GetKind(), "name", name, "instance_id", instance.InstanceID)
+		}
+	}
+	return nil
+}
@@ -1,7 +1,6 @@
 package kubernetes
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
@@ -11,27 +10,39 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/aplulu/hakoniwa/internal/config"
 	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
 )
 
 const (
 	UserIDAnnotationKey = "hakoniwa.aplulu.com/user-id"
 	ManagedByLabelKey   = "app.kubernetes.io/managed-by"
+
+	managedBySelector = ManagedByLabelKey + "=hakoniwa"
+
+	// baseURLAnnotationKey records the HAKONIWA_BASE_URL of the pod, so recovered instances are routed the same way.
+	baseURLAnnotationKey = "hakoniwa.aplulu.me/base-url"
 )
 
 type Client struct {
-	clientset *kubernetes.Clientset
-	namespace string
-	logger    *slog.Logger
+	clientset     *kubernetes.Clientset
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper // resolves the kinds of bundled resources
+	namespace     string
+	logger        *slog.Logger
+	podInformer   *podCache
 }
 
 func NewClient(logger *slog.Logger) (*Client, error) {
@@ -53,6 +64,10 @@ func NewClient(logger *slog.Logger) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("kubernetes.NewClient: failed to create clientset: %w", err)
 	}
+	dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.NewClient: failed to create dynamic client: %w", err)
+	}
 
 	// Get namespace from env or default
 	namespace := config.KubernetesNamespace()
@@ -61,26 +76,63 @@ func NewClient(logger *slog.Logger) (*Client, error) {
 	}
 
 	return &Client{
-		clientset: clientset,
-		namespace: namespace,
-		logger:    logger,
+		clientset:     clientset,
+		dynamicClient: dynamicClient,
+		mapper:        restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientset.Discovery())),
+		namespace:     namespace,
+		logger:        logger,
+		podInformer:   newPodCache(clientset, namespace, logger),
 	}, nil
 }
 
+// CreateInstancePod creates the pod of the instance, or the workload running it, and the resources bundled with it.
 func (c *Client) CreateInstancePod(ctx context.Context, instance *model.Instance, templateContent []byte) error {
 	if c.clientset == nil {
 		return fmt.Errorf("kubernetes.CreateInstancePod: k8s client not configured (no-op mode)")
 	}
 
+	pod, resources, err := buildInstancePod(instance, templateContent)
+	if err != nil {
+		return fmt.Errorf("kubernetes.CreateInstancePod: %w", err)
+	}
+
+	if err := c.attachHomeVolume(ctx, instance, pod.Annotations, pod); err != nil {
+		return fmt.Errorf("kubernetes.CreateInstancePod: failed to attach home volume: %w", err)
+	}
+
+	owner, err := c.createInstanceWorkload(ctx, instance, pod)
+	if err != nil {
+		return fmt.Errorf("kubernetes.CreateInstancePod: %w", err)
+	}
+
+	// The pod is created first so the resources can be owned by it; it waits for
+	// missing ConfigMaps, Secrets and claims to appear.
+	if err := c.applyInstanceResources(ctx, owner, resources); err != nil {
+		// Deleting the pod garbage collects the resources applied so far
+		if delErr := c.DeleteInstanceWorkload(ctx, instance); delErr != nil {
+			c.logger.Error("Failed to delete instance workload after resource failure", "name", pod.Name, "error", delErr)
+		}
+		return fmt.Errorf("kubernetes.CreateInstancePod: %w", err)
+	}
+
+	c.logger.Info("Created instance pod", "pod", pod.Name, "workload", instance.Workload, "user", instance.UserID, "type", instance.Type)
+	return nil
+}
+
+// buildInstancePod decodes the template and sets the Hakoniwa name, labels, annotations and environment
+// of the pod and the resources bundled with it.
+func buildInstancePod(instance *model.Instance, templateContent []byte) (*corev1.Pod, []*unstructured.Unstructured, error) {
 	// Generate Pod Name: hakoniwa-{instance_id}
 	// Assuming InstanceID is a UUID or safe string.
-	podName := fmt.Sprintf("hakoniwa-%s", instance.InstanceID)
-	instance.PodName = podName
+	podName := instanceObjectName(instance.InstanceID)
+	baseURL := instance.BaseURL
+	if baseURL == "" {
+		baseURL = "/"
+	}
 
-	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(templateContent), 4096)
-	var u unstructured.Unstructured
-	if err := decoder.Decode(&u); err != nil {
-		return fmt.Errorf("kubernetes.CreateInstancePod: failed to decode pod template: %w", err)
+	u, resources, err := decodeTemplate(templateContent)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	u.SetName(podName)
@@ -92,6 +144,7 @@ func (c *Client) CreateInstancePod(ctx context.Context, instance *model.Instance
 	// We keep user-id label for filtering/debugging, though instance-id is primary now
 	sanitizedUser := sanitizeUserID(instance.UserID)
 	labels["hakoniwa.aplulu.me/user-id"] = sanitizedUser
+	labels[instanceIDLabelKey] = instance.InstanceID
 	u.SetLabels(labels)
 
 	annotations := u.GetAnnotations()
@@ -102,40 +155,46 @@ func (c *Client) CreateInstancePod(ctx context.Context, instance *model.Instance
 	annotations["hakoniwa.aplulu.me/instance-id"] = instance.InstanceID
 	annotations["hakoniwa.aplulu.me/instance-type"] = instance.Type
 	annotations["hakoniwa.aplulu.me/display-name"] = instance.DisplayName
+	annotations[baseURLAnnotationKey] = baseURL
+	if instance.Workload != "" {
+		annotations[workloadAnnotationKey] = string(instance.Workload)
+	}
 	u.SetAnnotations(annotations)
 
 	var pod corev1.Pod
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &pod); err != nil {
-		return fmt.Errorf("kubernetes.CreateInstancePod: failed to convert unstructured to pod: %w", err)
+		return nil, nil, fmt.Errorf("failed to convert unstructured to pod: %w", err)
 	}
+	renamePodReferences(&pod, buildInstanceResources(instance, podName, resources))
 
 	// Inject Environment Variables
-	baseURL := "/"
 	envVars := []corev1.EnvVar{
 		{Name: "HAKONIWA_INSTANCE_ID", Value: instance.InstanceID},
 		{Name: "HAKONIWA_BASE_URL", Value: baseURL},
 	}
+	injectEnv(&pod, envVars)
 
-	for i := range pod.Spec.Containers {
-		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, envVars...)
-	}
+	return &pod, resources, nil
+}
 
-	_, err := c.clientset.CoreV1().Pods(c.namespace).Create(ctx, &pod, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("kubernetes.CreateInstancePod: failed to create pod: %w", err)
+// injectEnv adds the environment variables to the containers of the pod. They come before the template's variables,
+// so those can refer to them (e.g. "--base-url=$(HAKONIWA_BASE_URL)") and override them.
+func injectEnv(pod *corev1.Pod, envVars []corev1.EnvVar) {
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].Env = append(append([]corev1.EnvVar(nil), envVars...), pod.Spec.Containers[i].Env...)
 	}
-
-	c.logger.Info("Created instance pod", "pod", podName, "user", instance.UserID, "type", instance.Type)
-	return nil
 }
 
 func (c *Client) GetPodIP(ctx context.Context, podName string) (string, error) {
 	if c.clientset == nil {
-		return "", fmt.Errorf("kubernetes.CreateInstancePod: k8s client not configured (no-op mode)")
+		return "", fmt.Errorf("kubernetes.GetPodIP: k8s client not configured (no-op mode)")
 	}
-	pod, err := c.clientset.CoreV1().Pods(c.namespace).Get(ctx, podName, metav1.GetOptions{})
+	if podName == "" {
+		return "", nil // The workload has not created its pod yet
+	}
+	pod, err := c.getPod(ctx, podName)
 	if err != nil {
-		return "", fmt.Errorf("kubernetes.CreateInstancePod: failed to get pod: %w", err)
+		return "", fmt.Errorf("kubernetes.GetPodIP: failed to get pod: %w", err)
 	}
 
 	if isPodReady(pod) {
@@ -145,40 +204,44 @@ func (c *Client) GetPodIP(ctx context.Context, podName string) (string, error) {
 	return "", nil // Not running or not ready yet
 }
 
-func (c *Client) GetPodStatus(ctx context.Context, podName string) (model.InstanceStatus, string, error) {
+// GetPodStatus returns the status of the pod, or nil if it doesn't exist.
+func (c *Client) GetPodStatus(ctx context.Context, podName string) (*model.PodStatus, error) {
 	if c.clientset == nil {
-		return "", "", fmt.Errorf("kubernetes.GetPodStatus: k8s client not configured")
+		return nil, fmt.Errorf("kubernetes.GetPodStatus: k8s client not configured")
+	}
+	if podName == "" {
+		// The workload has not created its pod yet
+		return &model.PodStatus{Status: model.InstanceStatusPending}, nil
 	}
-	pod, err := c.clientset.CoreV1().Pods(c.namespace).Get(ctx, podName, metav1.GetOptions{})
+	pod, err := c.getPod(ctx, podName)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			return "", "", nil // Not Found
+			return nil, nil // Not Found
 		}
-		return "", "", fmt.Errorf("kubernetes.GetPodStatus: failed to get pod: %w", err)
+		return nil, fmt.Errorf("kubernetes.GetPodStatus: failed to get pod: %w", err)
 	}
 
 	// Check for termination/completion
 	if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
-		return model.InstanceStatusTerminating, "", nil
-	}
-
-	// Check Running & Ready
-	if isPodReady(pod) {
-		return model.InstanceStatusRunning, pod.Status.PodIP, nil
+		return &model.PodStatus{Status: model.InstanceStatusTerminating}, nil
 	}
 
-	// Default to Pending (Phase is Pending, or Running but not Ready, or Unknown)
-	return model.InstanceStatusPending, "", nil
+	status := podStatus(pod, c.podInformer.warningEvents(pod))
+	return &status, nil
 }
 
 func (c *Client) DeletePod(ctx context.Context, podName string) error {
 	if c.clientset == nil {
 		return fmt.Errorf("kubernetes.DeletePod: k8s client not configured (no-op mode)")
 	}
-	err := c.clientset.CoreV1().Pods(c.namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+	// Resources bundled with a bare pod are owned by it and deleted in the background
+	propagation := metav1.DeletePropagationBackground
+	err := c.clientset.CoreV1().Pods(c.namespace).Delete(ctx, podName, metav1.DeleteOptions{PropagationPolicy: &propagation})
 	if err != nil {
-		// If not found, consider it deleted
-		// Check error type if strict needed, but for now just return error
+		// If not found, consider it deleted (e.g. the instance is stopped)
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
 		return fmt.Errorf("kubernetes.DeletePod: failed to delete pod: %w", err)
 	}
 	c.logger.Info("Deleted instance pod", "pod", podName)
@@ -189,52 +252,126 @@ func (c *Client) ListInstancePods(ctx context.Context) ([]*model.Instance, error
 	if c.clientset == nil {
 		return nil, fmt.Errorf("k8s client not configured (no-op mode)")
 	}
-	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: ManagedByLabelKey + "=hakoniwa",
-	})
+	pods, err := c.listPods(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("kubernetes.ListInstancePods: failed to list pods: %w", err)
 	}
 
 	var instances []*model.Instance
-	for _, pod := range pods.Items {
-		// Skip terminating or finished pods
-		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+	for _, pod := range pods {
+		instance, ok := instanceFromPod(pod, c.podInformer.warningEvents(pod))
+		if !ok {
 			continue
 		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
 
-		userID := pod.Annotations[UserIDAnnotationKey]
-		instanceID := pod.Annotations["hakoniwa.aplulu.me/instance-id"]
-		instanceType := pod.Annotations["hakoniwa.aplulu.me/instance-type"]
-		displayName := pod.Annotations["hakoniwa.aplulu.me/display-name"]
+// WatchInstancePods registers handler to be notified whenever a managed pod changes, until ctx is done.
+// Events are delivered once the pod cache is started with Start.
+func (c *Client) WatchInstancePods(ctx context.Context, handler repository.InstancePodEventHandler) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.WatchInstancePods: k8s client not configured (no-op mode)")
+	}
+	if err := c.podInformer.addHandler(ctx, handler); err != nil {
+		return fmt.Errorf("kubernetes.WatchInstancePods: failed to add event handler: %w", err)
+	}
+	return nil
+}
 
-		// Fallback for legacy pods
-		if instanceID == "" {
-			continue
-		}
+// Start starts the shared pod informer and waits for the initial cache sync.
+func (c *Client) Start(ctx context.Context) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.Start: k8s client not configured (no-op mode)")
+	}
+	if err := c.podInformer.start(ctx); err != nil {
+		return fmt.Errorf("kubernetes.Start: %w", err)
+	}
+	return nil
+}
 
-		status := model.InstanceStatusPending
-		if isPodReady(&pod) {
-			status = model.InstanceStatusRunning
-		}
-		// If not running (e.g. Pending, Unknown), it defaults to Pending.
-		// Terminating (Succeeded/Failed) are filtered out above.
-
-		// For recovery, we assume they are active now to prevent immediate cleanup
-		lastActiveAt := time.Now()
-
-		instances = append(instances, &model.Instance{
-			InstanceID:   instanceID,
-			UserID:       userID,
-			Type:         instanceType,
-			DisplayName:  displayName,
-			PodName:      pod.Name,
-			PodIP:        pod.Status.PodIP,
-			Status:       status,
-			LastActiveAt: lastActiveAt,
-		})
+// getPod returns the pod from the informer cache, falling back to the API server until the cache has synced.
+// The returned pod is shared with the cache and must not be modified.
+func (c *Client) getPod(ctx context.Context, podName string) (*corev1.Pod, error) {
+	if c.podInformer.hasSynced() {
+		return c.podInformer.get(podName)
 	}
-	return instances, nil
+	return c.clientset.CoreV1().Pods(c.namespace).Get(ctx, podName, metav1.GetOptions{})
+}
+
+// listPods returns managed pods from the informer cache, falling back to the API server until the cache has synced.
+func (c *Client) listPods(ctx context.Context) ([]*corev1.Pod, error) {
+	if c.podInformer.hasSynced() {
+		return c.podInformer.list()
+	}
+	pods, err := c.clientset.CoreV1().Pods(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: managedBySelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*corev1.Pod, 0, len(pods.Items))
+	for i := range pods.Items {
+		result = append(result, &pods.Items[i])
+	}
+	return result, nil
+}
+
+// instanceFromPod converts a managed pod into an instance.
+// It returns false for terminating or finished pods and for pods without an instance ID,
+// such as unclaimed warm pool pods.
+func instanceFromPod(pod *corev1.Pod, events []*corev1.Event) (*model.Instance, bool) {
+	// Skip terminating or finished pods
+	if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return nil, false
+	}
+
+	userID := pod.Annotations[UserIDAnnotationKey]
+	instanceID := pod.Annotations["hakoniwa.aplulu.me/instance-id"]
+	instanceType := pod.Annotations["hakoniwa.aplulu.me/instance-type"]
+	displayName := pod.Annotations["hakoniwa.aplulu.me/display-name"]
+
+	// Fallback for legacy pods
+	if instanceID == "" {
+		return nil, false
+	}
+
+	workload := model.WorkloadKind(pod.Annotations[workloadAnnotationKey])
+	if workload == "" {
+		workload = model.WorkloadKindPod
+	}
+
+	targetPort := pod.Annotations["hakoniwa.aplulu.me/port"]
+	if targetPort == "" {
+		targetPort = "3000"
+	}
+
+	// Terminating (Succeeded/Failed) are filtered out above.
+	status := podStatus(pod, events)
+
+	// For recovery, we assume they are active now to prevent immediate cleanup
+	lastActiveAt := time.Now()
+
+	return &model.Instance{
+		InstanceID:    instanceID,
+		UserID:        userID,
+		Type:          instanceType,
+		DisplayName:   displayName,
+		PodName:       pod.Name,
+		PodIP:         status.PodIP,
+		Status:        status.Status,
+		StatusReason:  status.Reason,
+		StatusMessage: status.Message,
+		StartupPhases: status.Phases,
+		BaseURL:       pod.Annotations[baseURLAnnotationKey],
+		LastActiveAt:  lastActiveAt,
+		CreatedAt:     pod.CreationTimestamp.Time,
+		StartedAt:     pod.CreationTimestamp.Time,
+		TargetPort:    targetPort,
+		Workload:      workload,
+		Resources:     config.PodSpecResourceRequests(&pod.Spec),
+	}, true
 }
 
 // sanitizeUserID makes the user ID safe for use in Kubernetes resource names
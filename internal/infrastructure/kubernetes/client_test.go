@@ -0,0 +1,50 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const envTemplate = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: jupyter
+spec:
+  containers:
+  - name: jupyter
+    image: jupyter/base-notebook
+    env:
+    - name: NOTEBOOK_ARGS
+      value: "--NotebookApp.base_url=$(HAKONIWA_BASE_URL)"
+`
+
+func TestBuildInstancePodEnv(t *testing.T) {
+	pod, _, err := buildInstancePod(&model.Instance{InstanceID: "abc", BaseURL: "/w/abc/"}, []byte(envTemplate))
+	if err != nil {
+		t.Fatalf("buildInstancePod: %v", err)
+	}
+
+	// Kubernetes only expands references to variables defined before
+	env := pod.Spec.Containers[0].Env
+	names := make([]string, len(env))
+	for i, e := range env {
+		names[i] = e.Name
+	}
+	if len(env) != 3 || names[0] != "HAKONIWA_INSTANCE_ID" || names[1] != "HAKONIWA_BASE_URL" || names[2] != "NOTEBOOK_ARGS" {
+		t.Fatalf("unexpected env order: %v", names)
+	}
+	if env[1].Value != "/w/abc/" || pod.Annotations[baseURLAnnotationKey] != "/w/abc/" {
+		t.Errorf("unexpected base URL: env %q, annotation %q", env[1].Value, pod.Annotations[baseURLAnnotationKey])
+	}
+
+	// Instances created before the base URL was recorded are served at the root path
+	pod, _, err = buildInstancePod(&model.Instance{InstanceID: "abc"}, []byte(envTemplate))
+	if err != nil {
+		t.Fatalf("buildInstancePod: %v", err)
+	}
+	if got := pod.Spec.Containers[0].Env[1].Value; got != "/" {
+		t.Errorf("got base URL %q, want /", got)
+	}
+}
@@ -0,0 +1,72 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+)
+
+// RunLeaderElection competes for the named Lease and calls run whenever this replica becomes the leader.
+// The context passed to run is cancelled when leadership is lost; the replica then rejoins the election.
+// It blocks until ctx is done.
+func (c *Client) RunLeaderElection(ctx context.Context, leaseName string, run func(ctx context.Context)) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.RunLeaderElection: k8s client not configured (no-op mode)")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("kubernetes.RunLeaderElection: failed to get hostname: %w", err)
+	}
+	// Pod names are unique per replica; the suffix keeps restarted processes distinguishable.
+	identity := hostname + "_" + uuid.New().String()
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      leaseName,
+				Namespace: c.namespace,
+			},
+			Client: c.clientset.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: identity,
+			},
+		},
+		LeaseDuration:   config.LeaderElectionLeaseDuration(),
+		RenewDeadline:   config.LeaderElectionRenewDeadline(),
+		RetryPeriod:     config.LeaderElectionRetryPeriod(),
+		ReleaseOnCancel: true,
+		Name:            leaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				c.logger.Info("Acquired leadership", "lease", leaseName, "identity", identity)
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				c.logger.Info("Released leadership", "lease", leaseName, "identity", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					c.logger.Info("Observed new leader", "lease", leaseName, "leader", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("kubernetes.RunLeaderElection: failed to create leader elector: %w", err)
+	}
+
+	for {
+		elector.Run(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
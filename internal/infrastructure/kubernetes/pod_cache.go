@@ -0,0 +1,275 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+)
+
+// eventPodUIDIndex indexes events by the UID of the pod they are about.
+const eventPodUIDIndex = "podUID"
+
+// podCache is a shared informer on pods managed by Hakoniwa.
+// It serves pod lookups without hitting the API server and notifies handlers of pod changes.
+// It also keeps the Warning events of those pods, which explain pods that don't start.
+type podCache struct {
+	factory        informers.SharedInformerFactory
+	informer       cache.SharedIndexInformer
+	lister         corelisters.PodLister
+	events         *podEventStore
+	eventReflector *cache.Reflector
+	namespace      string
+	logger         *slog.Logger
+
+	mu sync.Mutex
+	// eventHandlers are notified of the pods whose Warning events change, by registration.
+	eventHandlers map[int]func(pod *corev1.Pod)
+	nextHandler   int
+}
+
+func newPodCache(clientset kubernetes.Interface, namespace string, logger *slog.Logger) *podCache {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		clientset,
+		0, // No periodic resync; the syncer reconciles from the cache on its own interval.
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = managedBySelector
+		}),
+	)
+	pods := factory.Core().V1().Pods()
+
+	p := &podCache{
+		factory:       factory,
+		informer:      pods.Informer(),
+		lister:        pods.Lister(),
+		namespace:     namespace,
+		logger:        logger,
+		eventHandlers: make(map[int]func(pod *corev1.Pod)),
+	}
+
+	// Events have no labels to select managed pods by, so the Warning events about pods are listed and those about
+	// other pods in the namespace are dropped before they are cached.
+	p.events = &podEventStore{
+		Indexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+			eventPodUIDIndex: func(obj any) ([]string, error) {
+				event, ok := obj.(*corev1.Event)
+				if !ok {
+					return nil, nil
+				}
+				return []string{string(event.InvolvedObject.UID)}, nil
+			},
+		}),
+		podOf:   p.eventPod,
+		changed: p.notifyEvent,
+	}
+	selectWarnings := func(opts *metav1.ListOptions) {
+		opts.FieldSelector = "type=" + corev1.EventTypeWarning + ",involvedObject.kind=Pod"
+	}
+	p.eventReflector = cache.NewReflector(&cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			selectWarnings(&opts)
+			return clientset.CoreV1().Events(namespace).List(context.Background(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			selectWarnings(&opts)
+			return clientset.CoreV1().Events(namespace).Watch(context.Background(), opts)
+		},
+	}, &corev1.Event{}, p.events, 0)
+
+	return p
+}
+
+func (p *podCache) start(ctx context.Context) error {
+	p.factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), p.informer.HasSynced) {
+		return fmt.Errorf("failed to sync pod cache")
+	}
+	p.logger.Info("Pod cache synced", "namespace", p.namespace)
+	// Events are kept for the pods in the cache, so they are listed once it has synced.
+	// They only add detail to pod statuses, so they are not waited for.
+	go p.eventReflector.Run(ctx.Done())
+	return nil
+}
+
+func (p *podCache) hasSynced() bool {
+	return p.informer.HasSynced()
+}
+
+func (p *podCache) get(podName string) (*corev1.Pod, error) {
+	return p.lister.Pods(p.namespace).Get(podName)
+}
+
+func (p *podCache) list() ([]*corev1.Pod, error) {
+	return p.lister.Pods(p.namespace).List(labels.Everything())
+}
+
+// warningEvents returns the Warning events about the pod, or nil until the events have been listed.
+func (p *podCache) warningEvents(pod *corev1.Pod) []*corev1.Event {
+	if !p.events.synced.Load() {
+		return nil
+	}
+	objs, err := p.events.ByIndex(eventPodUIDIndex, string(pod.UID))
+	if err != nil {
+		return nil
+	}
+	events := make([]*corev1.Event, 0, len(objs))
+	for _, obj := range objs {
+		if event, ok := obj.(*corev1.Event); ok {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// eventPod returns the managed pod the event is about, or nil if it is about another pod.
+func (p *podCache) eventPod(event *corev1.Event) *corev1.Pod {
+	pod, err := p.get(event.InvolvedObject.Name)
+	if err != nil || pod.UID != event.InvolvedObject.UID {
+		return nil
+	}
+	return pod
+}
+
+// notifyEvent notifies the handlers of a pod whose Warning events changed.
+func (p *podCache) notifyEvent(pod *corev1.Pod) {
+	p.mu.Lock()
+	handlers := make([]func(pod *corev1.Pod), 0, len(p.eventHandlers))
+	for _, handler := range p.eventHandlers {
+		handlers = append(handlers, handler)
+	}
+	p.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(pod)
+	}
+}
+
+// addHandler registers handler until ctx is done, so watchers tied to leadership do not accumulate.
+func (p *podCache) addHandler(ctx context.Context, handler repository.InstancePodEventHandler) error {
+	registration, err := p.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			p.notify(ctx, handler, obj)
+		},
+		UpdateFunc: func(_, newObj any) {
+			p.notify(ctx, handler, newObj)
+		},
+		DeleteFunc: func(obj any) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				return
+			}
+			if instanceID := pod.Annotations["hakoniwa.aplulu.me/instance-id"]; instanceID != "" {
+				handler.OnInstancePodRemoved(ctx, instanceID)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// A new Warning event can explain a pod whose status has not changed, e.g. a volume that fails to mount
+	p.mu.Lock()
+	eventRegistration := p.nextHandler
+	p.nextHandler++
+	p.eventHandlers[eventRegistration] = func(pod *corev1.Pod) {
+		p.notify(ctx, handler, pod)
+	}
+	p.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		if err := p.informer.RemoveEventHandler(registration); err != nil {
+			p.logger.Error("Failed to remove pod event handler", "error", err)
+		}
+		p.mu.Lock()
+		delete(p.eventHandlers, eventRegistration)
+		p.mu.Unlock()
+	}()
+	return nil
+}
+
+func (p *podCache) notify(ctx context.Context, handler repository.InstancePodEventHandler, obj any) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	instance, ok := instanceFromPod(pod, p.warningEvents(pod))
+	if !ok {
+		// Terminating or finished pods are treated as gone, matching ListInstancePods.
+		if instanceID := pod.Annotations["hakoniwa.aplulu.me/instance-id"]; instanceID != "" {
+			handler.OnInstancePodRemoved(ctx, instanceID)
+		}
+		return
+	}
+	handler.OnInstancePodChanged(ctx, instance)
+}
+
+// podEventStore caches the events about managed pods for the event reflector; events about other pods are dropped.
+// An event that arrives before its pod is in the pod cache is dropped too, until the event is updated.
+type podEventStore struct {
+	cache.Indexer
+	podOf   func(event *corev1.Event) *corev1.Pod
+	changed func(pod *corev1.Pod)
+	// synced is set once the events have been listed.
+	synced atomic.Bool
+}
+
+func (s *podEventStore) Add(obj any) error {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		return nil
+	}
+	pod := s.podOf(event)
+	if pod == nil {
+		return nil
+	}
+	if err := s.Indexer.Add(event); err != nil {
+		return err
+	}
+	s.changed(pod)
+	return nil
+}
+
+func (s *podEventStore) Update(obj any) error {
+	return s.Add(obj)
+}
+
+func (s *podEventStore) Replace(list []any, resourceVersion string) error {
+	var events []any
+	var pods []*corev1.Pod
+	for _, obj := range list {
+		event, ok := obj.(*corev1.Event)
+		if !ok {
+			continue
+		}
+		if pod := s.podOf(event); pod != nil {
+			events = append(events, event)
+			pods = append(pods, pod)
+		}
+	}
+	if err := s.Indexer.Replace(events, resourceVersion); err != nil {
+		return err
+	}
+	s.synced.Store(true)
+	for _, pod := range pods {
+		s.changed(pod)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func warningEvent(name string, pod *corev1.Pod) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: pod.Namespace},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod.Name, UID: pod.UID},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "FailedMount",
+	}
+}
+
+func TestPodCache_KeepsWarningEventsOfManagedPods(t *testing.T) {
+	managed := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "hakoniwa-abc", Namespace: "hakoniwa", UID: types.UID("managed"),
+		Labels: map[string]string{ManagedByLabelKey: "hakoniwa"}}}
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "hakoniwa", UID: types.UID("other")}}
+	clientset := fake.NewSimpleClientset(managed, other, warningEvent("managed.1", managed), warningEvent("other.1", other))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache := newPodCache(clientset, "hakoniwa", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := cache.start(ctx); err != nil {
+		t.Fatalf("Failed to start pod cache: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(cache.warningEvents(managed)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if events := cache.warningEvents(managed); len(events) != 1 || events[0].Name != "managed.1" {
+		t.Errorf("Expected the warning event of the managed pod, got %v", events)
+	}
+	if keys := cache.events.ListKeys(); len(keys) != 1 {
+		t.Errorf("Expected only the events of managed pods to be cached, got %v", keys)
+	}
+}
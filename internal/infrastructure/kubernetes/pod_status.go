@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// warningEventMaxAge is how long a Warning event explains a pod that is still starting.
+// Kubernetes repeats the events of problems that persist, such as volumes failing to mount.
+const warningEventMaxAge = 5 * time.Minute
+
+// podReadyToStartContainers is set once the pod sandbox is created and its images can be pulled (Kubernetes 1.29+).
+const podReadyToStartContainers corev1.PodConditionType = "PodReadyToStartContainers"
+
+// failedContainerReasons are the waiting reasons of containers that won't start without a change
+// to the template, the image or the cluster.
+var failedContainerReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"ErrImageNeverPull":          true,
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// podStatus derives the status of an instance from its pod, which must not be terminating or finished,
+// with the reason and message explaining a pod that is not ready yet.
+// Warning events fill in what the pod status doesn't tell, such as why a volume fails to mount or an image fails to pull.
+func podStatus(pod *corev1.Pod, events []*corev1.Event) model.PodStatus {
+	phases := startupPhases(pod)
+	if isPodReady(pod) {
+		return model.PodStatus{Status: model.InstanceStatusRunning, PodIP: pod.Status.PodIP, Phases: phases}
+	}
+
+	status := model.PodStatus{Status: model.InstanceStatusPending, PodIP: pod.Status.PodIP, Phases: phases}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.PodScheduled {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue {
+			status.Status = model.InstanceStatusStarting
+		} else if cond.Reason == corev1.PodReasonUnschedulable {
+			return model.PodStatus{Status: model.InstanceStatusFailed, Reason: cond.Reason, Message: cond.Message, Phases: phases}
+		}
+	}
+
+	// Init containers run first, so their problems are reported first
+	containers := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, cs := range containers {
+		waiting := cs.State.Waiting
+		if waiting == nil || waiting.Reason == "" {
+			continue
+		}
+		message := waiting.Message
+		if terminated := cs.LastTerminationState.Terminated; waiting.Reason == "CrashLoopBackOff" && terminated != nil {
+			message = fmt.Sprintf("exited with code %d", terminated.ExitCode)
+			if terminated.Reason != "" {
+				message += " (" + terminated.Reason + ")"
+			}
+		}
+		if failedContainerReasons[waiting.Reason] {
+			if event := latestWarningEvent(events, "Failed"); event != nil && waiting.Reason == "ImagePullBackOff" {
+				// The back-off message only names the image; the event has the registry's error
+				message = event.Message
+			}
+			status.Status = model.InstanceStatusFailed
+			status.Reason = waiting.Reason
+			status.Message = containerMessage(cs.Name, message)
+			return status
+		}
+		if status.Reason == "" {
+			status.Reason = waiting.Reason
+			status.Message = containerMessage(cs.Name, message)
+		}
+	}
+
+	// ContainerCreating and PodInitializing only say the pod is waiting; an event may say what for
+	if status.Reason == "" || status.Reason == "ContainerCreating" || status.Reason == "PodInitializing" {
+		if event := latestWarningEvent(events, ""); event != nil {
+			status.Reason = event.Reason
+			status.Message = event.Message
+		}
+	}
+	return status
+}
+
+// startupPhases returns the startup phases the pod has reached, in order, with when it entered each of them.
+// Kubernetes doesn't report image pulls in the pod status, so pulling starts when the pod sandbox is ready,
+// or when the pod is scheduled on older clusters, and ends when the first container starts.
+func startupPhases(pod *corev1.Pod) []model.StartupPhaseTime {
+	scheduled := podCondition(pod, corev1.PodScheduled)
+	if scheduled == nil {
+		return nil
+	}
+	phases := []model.StartupPhaseTime{{Phase: model.StartupPhaseScheduled, At: scheduled.LastTransitionTime.Time}}
+
+	pullingAt := scheduled.LastTransitionTime
+	if sandbox := podCondition(pod, podReadyToStartContainers); sandbox != nil {
+		pullingAt = sandbox.LastTransitionTime
+	}
+
+	var firstStart, lastStart metav1.Time
+	started := 0
+	for _, cs := range append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		at := containerStartedAt(cs)
+		if !at.IsZero() && (firstStart.IsZero() || at.Before(&firstStart)) {
+			firstStart = at
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Running == nil {
+			continue
+		}
+		started++
+		if at := cs.State.Running.StartedAt; lastStart.IsZero() || lastStart.Before(&at) {
+			lastStart = at
+		}
+	}
+
+	if firstStart.IsZero() {
+		if len(pod.Status.ContainerStatuses) > 0 || len(pod.Status.InitContainerStatuses) > 0 {
+			phases = append(phases, model.StartupPhaseTime{Phase: model.StartupPhasePullingImage, At: pullingAt.Time})
+		}
+		return phases
+	}
+	if firstStart.Before(&pullingAt) {
+		pullingAt = firstStart
+	}
+	phases = append(phases,
+		model.StartupPhaseTime{Phase: model.StartupPhasePullingImage, At: pullingAt.Time},
+		model.StartupPhaseTime{Phase: model.StartupPhaseInitializing, At: firstStart.Time},
+	)
+	if started == 0 || started < len(pod.Spec.Containers) {
+		return phases
+	}
+	phases = append(phases, model.StartupPhaseTime{Phase: model.StartupPhaseWaitingForReadiness, At: lastStart.Time})
+	if ready := podCondition(pod, corev1.PodReady); ready != nil {
+		// A container restarted without failing its readiness probe keeps the pod ready
+		readyAt := ready.LastTransitionTime
+		if readyAt.Before(&lastStart) {
+			readyAt = lastStart
+		}
+		phases = append(phases, model.StartupPhaseTime{Phase: model.StartupPhaseReady, At: readyAt.Time})
+	}
+	return phases
+}
+
+// podCondition returns the condition of the type if it is true.
+func podCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if cond := &pod.Status.Conditions[i]; cond.Type == conditionType && cond.Status == corev1.ConditionTrue {
+			return cond
+		}
+	}
+	return nil
+}
+
+// containerStartedAt returns when the container first started, as far as its status tells.
+func containerStartedAt(cs corev1.ContainerStatus) metav1.Time {
+	if t := cs.LastTerminationState.Terminated; t != nil {
+		return t.StartedAt
+	}
+	if cs.State.Running != nil {
+		return cs.State.Running.StartedAt
+	}
+	if cs.State.Terminated != nil {
+		return cs.State.Terminated.StartedAt
+	}
+	return metav1.Time{}
+}
+
+func containerMessage(container, message string) string {
+	if message == "" {
+		return ""
+	}
+	return "container " + container + ": " + message
+}
+
+// latestWarningEvent returns the most recent Warning event with the reason, or of any reason if reason is empty.
+// Events older than warningEventMaxAge are ignored.
+func latestWarningEvent(events []*corev1.Event, reason string) *corev1.Event {
+	var latest *corev1.Event
+	var latestAt time.Time
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning || (reason != "" && event.Reason != reason) {
+			continue
+		}
+		at := eventTime(event)
+		if time.Since(at) > warningEventMaxAge {
+			continue
+		}
+		if latest == nil || at.After(latestAt) {
+			latest, latestAt = event, at
+		}
+	}
+	return latest
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
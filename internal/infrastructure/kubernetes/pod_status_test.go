@@ -0,0 +1,200 @@
+package kubernetes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+func TestPodStatus(t *testing.T) {
+	scheduled := corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}
+	waiting := func(reason, message string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{
+			Name:  "desktop",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}},
+		}
+	}
+	warning := func(reason, message string, age time.Duration) *corev1.Event {
+		return &corev1.Event{
+			Type:          corev1.EventTypeWarning,
+			Reason:        reason,
+			Message:       message,
+			LastTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		events []*corev1.Event
+		want   model.PodStatus
+	}{
+		{
+			name: "ready",
+			status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{scheduled, {Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+			want: model.PodStatus{Status: model.InstanceStatusRunning, PodIP: "10.0.0.1"},
+		},
+		{
+			name:   "not scheduled yet",
+			status: corev1.PodStatus{Phase: corev1.PodPending},
+			want:   model.PodStatus{Status: model.InstanceStatusPending},
+		},
+		{
+			name: "unschedulable",
+			status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type: corev1.PodScheduled, Status: corev1.ConditionFalse,
+					Reason: corev1.PodReasonUnschedulable, Message: "0/3 nodes are available: 3 Insufficient memory.",
+				}},
+			},
+			want: model.PodStatus{Status: model.InstanceStatusFailed, Reason: "Unschedulable", Message: "0/3 nodes are available: 3 Insufficient memory."},
+		},
+		{
+			name: "image pull back-off",
+			status: corev1.PodStatus{
+				Phase:             corev1.PodPending,
+				Conditions:        []corev1.PodCondition{scheduled},
+				ContainerStatuses: []corev1.ContainerStatus{waiting("ImagePullBackOff", `Back-off pulling image "webtop:nope"`)},
+			},
+			events: []*corev1.Event{warning("Failed", `Failed to pull image "webtop:nope": not found`, time.Minute)},
+			want:   model.PodStatus{Status: model.InstanceStatusFailed, Reason: "ImagePullBackOff", Message: `container desktop: Failed to pull image "webtop:nope": not found`},
+		},
+		{
+			name: "crash loop",
+			status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{scheduled},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:                 "desktop",
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+				}},
+			},
+			want: model.PodStatus{Status: model.InstanceStatusFailed, PodIP: "10.0.0.1", Reason: "CrashLoopBackOff", Message: "container desktop: exited with code 137 (OOMKilled)"},
+		},
+		{
+			name: "volume fails to mount",
+			status: corev1.PodStatus{
+				Phase:             corev1.PodPending,
+				Conditions:        []corev1.PodCondition{scheduled},
+				ContainerStatuses: []corev1.ContainerStatus{waiting("ContainerCreating", "")},
+			},
+			events: []*corev1.Event{
+				warning("FailedMount", `configmap "old" not found`, 10*time.Minute),
+				warning("FailedMount", `configmap "settings" not found`, time.Minute),
+			},
+			want: model.PodStatus{Status: model.InstanceStatusStarting, Reason: "FailedMount", Message: `configmap "settings" not found`},
+		},
+		{
+			name: "starting",
+			status: corev1.PodStatus{
+				Phase:             corev1.PodPending,
+				Conditions:        []corev1.PodCondition{scheduled},
+				ContainerStatuses: []corev1.ContainerStatus{waiting("ContainerCreating", "")},
+			},
+			events: []*corev1.Event{warning("FailedMount", `configmap "old" not found`, 10*time.Minute)},
+			want:   model.PodStatus{Status: model.InstanceStatusStarting, Reason: "ContainerCreating"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := podStatus(&corev1.Pod{Status: tt.status}, tt.events)
+			got.Phases = nil // Covered by TestStartupPhases
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartupPhases(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(seconds int) metav1.Time { return metav1.NewTime(start.Add(time.Duration(seconds) * time.Second)) }
+	condition := func(conditionType corev1.PodConditionType, seconds int) corev1.PodCondition {
+		return corev1.PodCondition{Type: conditionType, Status: corev1.ConditionTrue, LastTransitionTime: at(seconds)}
+	}
+	running := func(seconds int) corev1.ContainerState {
+		return corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: at(seconds)}}
+	}
+	creating := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "desktop"}, {Name: "sidecar"}}}
+
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		want   []model.StartupPhase
+		times  []int
+	}{
+		{
+			name:   "not scheduled",
+			status: corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		{
+			name: "pulling images",
+			status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{condition(corev1.PodScheduled, 0), condition(podReadyToStartContainers, 2)},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "desktop", State: creating}, {Name: "sidecar", State: creating}},
+			},
+			want:  []model.StartupPhase{model.StartupPhaseScheduled, model.StartupPhasePullingImage},
+			times: []int{0, 2},
+		},
+		{
+			name: "init containers",
+			status: corev1.PodStatus{
+				Conditions:            []corev1.PodCondition{condition(corev1.PodScheduled, 0)},
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "init", State: running(30)}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "desktop"}, {Name: "sidecar"}},
+			},
+			want:  []model.StartupPhase{model.StartupPhaseScheduled, model.StartupPhasePullingImage, model.StartupPhaseInitializing},
+			times: []int{0, 0, 30},
+		},
+		{
+			name: "one container started",
+			status: corev1.PodStatus{
+				Conditions:        []corev1.PodCondition{condition(corev1.PodScheduled, 0), condition(podReadyToStartContainers, 2)},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "desktop", State: running(40)}, {Name: "sidecar", State: creating}},
+			},
+			want:  []model.StartupPhase{model.StartupPhaseScheduled, model.StartupPhasePullingImage, model.StartupPhaseInitializing},
+			times: []int{0, 2, 40},
+		},
+		{
+			name: "ready",
+			status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					condition(corev1.PodScheduled, 0), condition(podReadyToStartContainers, 2), condition(corev1.PodReady, 60),
+				},
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "desktop", State: running(40)}, {Name: "sidecar", State: running(45)}},
+			},
+			want: []model.StartupPhase{
+				model.StartupPhaseScheduled, model.StartupPhasePullingImage, model.StartupPhaseInitializing,
+				model.StartupPhaseWaitingForReadiness, model.StartupPhaseReady,
+			},
+			times: []int{0, 2, 40, 45, 60},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := startupPhases(&corev1.Pod{Spec: spec, Status: tt.status})
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %v", got, tt.want)
+			}
+			for i, phase := range got {
+				if phase.Phase != tt.want[i] || !phase.At.Equal(at(tt.times[i]).Time) {
+					t.Errorf("phase %d: got %s at %v, want %s at %v", i, phase.Phase, phase.At, tt.want[i], at(tt.times[i]).Time)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ConfigMapTemplateSource reads the pod template from a ConfigMap key and watches it through the API,
+// so updates are picked up immediately (unlike subPath mounts, which are never refreshed).
+type ConfigMapTemplateSource struct {
+	client *Client
+	name   string
+	key    string
+}
+
+// NewConfigMapTemplateSource returns a template source for a ConfigMap in the client namespace.
+func (c *Client) NewConfigMapTemplateSource(name, key string) *ConfigMapTemplateSource {
+	return &ConfigMapTemplateSource{
+		client: c,
+		name:   name,
+		key:    key,
+	}
+}
+
+func (s *ConfigMapTemplateSource) Read(ctx context.Context) ([]byte, error) {
+	cm, err := s.client.clientset.CoreV1().ConfigMaps(s.client.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.ConfigMapTemplateSource.Read: failed to get configmap %s: %w", s.name, err)
+	}
+	content, ok := cm.Data[s.key]
+	if !ok {
+		return nil, fmt.Errorf("kubernetes.ConfigMapTemplateSource.Read: key %s not found in configmap %s", s.key, s.name)
+	}
+	return []byte(content), nil
+}
+
+func (s *ConfigMapTemplateSource) Watch(ctx context.Context, onChange func(ctx context.Context)) {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		s.client.clientset,
+		0,
+		informers.WithNamespace(s.client.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", s.name).String()
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		// Also fires for the initial list; reloading an unchanged template is a no-op.
+		AddFunc: func(obj interface{}) {
+			onChange(ctx)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCM, ok1 := oldObj.(*corev1.ConfigMap)
+			newCM, ok2 := newObj.(*corev1.ConfigMap)
+			if !ok1 || !ok2 || oldCM.Data[s.key] == newCM.Data[s.key] {
+				return
+			}
+			onChange(ctx)
+		},
+	})
+	if err != nil {
+		s.client.logger.Error("Failed to watch pod template configmap", "name", s.name, "error", err)
+		return
+	}
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+}
@@ -0,0 +1,93 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const (
+	// validationInstanceID has the length of a real instance ID so name length limits are checked.
+	validationInstanceID = "00000000-0000-0000-0000-000000000000"
+	validationUserID     = "hakoniwa-template-validation"
+)
+
+// ValidateInstancePod checks a rendered template with Hakoniwa-specific rules and
+// a server-side dry-run create, which runs admission and schema validation without persisting anything.
+func (c *Client) ValidateInstancePod(ctx context.Context, instanceType string, templateContent []byte, targetPort string) ([]model.TemplateProblem, error) {
+	if c.clientset == nil {
+		return nil, fmt.Errorf("kubernetes.ValidateInstancePod: k8s client not configured (no-op mode)")
+	}
+
+	instance := &model.Instance{
+		InstanceID:  validationInstanceID,
+		UserID:      validationUserID,
+		Type:        instanceType,
+		DisplayName: instanceType,
+	}
+	pod, resources, err := buildInstancePod(instance, templateContent)
+	if err != nil {
+		return []model.TemplateProblem{{Check: model.TemplateCheckDecode, Message: err.Error()}}, nil
+	}
+
+	problems := checkInstancePod(pod, targetPort)
+
+	// Mount the home volume as CreateInstancePod would, without creating the claim.
+	size := pod.Annotations[volumeSizeAnnotationKey]
+	mountPath := pod.Annotations[volumeMountPathAnnotationKey]
+	if size != "" && mountPath != "" {
+		mountHomeVolume(pod, homeVolumeClaimName(instance.UserID, instance.Type), mountPath)
+	}
+
+	_, err = c.clientset.CoreV1().Pods(c.namespace).Create(ctx, pod, metav1.CreateOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		problems = append(problems, model.TemplateProblem{Check: model.TemplateCheckDryRun, Message: err.Error()})
+	}
+
+	for _, u := range resources {
+		ri, err := c.resourceClient(u)
+		if err == nil {
+			_, err = ri.Apply(ctx, u.GetName(), u, metav1.ApplyOptions{FieldManager: fieldManager, Force: true, DryRun: []string{metav1.DryRunAll}})
+		}
+		if err != nil {
+			problems = append(problems, model.TemplateProblem{Check: model.TemplateCheckDryRun, Message: fmt.Sprintf("%s %s: %v", u.GetKind(), u.GetName(), err)})
+		}
+	}
+
+	return problems, nil
+}
+
+// checkInstancePod checks that the target port is exposed by a container with a readiness probe,
+// as the gateway only proxies to ready pods.
+func checkInstancePod(pod *corev1.Pod, targetPort string) []model.TemplateProblem {
+	portNumber, numeric := 0, false
+	if n, err := strconv.Atoi(targetPort); err == nil {
+		portNumber, numeric = n, true
+	}
+
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if (numeric && int(port.ContainerPort) == portNumber) || (!numeric && port.Name == targetPort) {
+				if container.ReadinessProbe == nil {
+					return []model.TemplateProblem{{
+						Check:   model.TemplateCheckReadinessProbe,
+						Message: fmt.Sprintf("container %s serving port %s has no readiness probe; the instance is reported as running before it can serve requests", container.Name, targetPort),
+					}}
+				}
+				return nil
+			}
+		}
+	}
+
+	return []model.TemplateProblem{{
+		Check:   model.TemplateCheckPort,
+		Message: fmt.Sprintf("hakoniwa.aplulu.me/port %s does not match any container port", targetPort),
+	}}
+}
@@ -0,0 +1,179 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const (
+	volumeSizeAnnotationKey         = "hakoniwa.aplulu.me/volume-size"
+	volumeMountPathAnnotationKey    = "hakoniwa.aplulu.me/volume-mount-path"
+	volumeStorageClassAnnotationKey = "hakoniwa.aplulu.me/volume-storage-class"
+	volumeNameAnnotationKey         = "hakoniwa.aplulu.me/volume-name"
+	userHashLabelKey                = "hakoniwa.aplulu.me/user-hash"
+
+	homeVolumeName = "hakoniwa-home"
+)
+
+// ListUserVolumes returns the home volumes owned by the user.
+func (c *Client) ListUserVolumes(ctx context.Context, userID string) ([]*model.Volume, error) {
+	if c.clientset == nil {
+		return nil, fmt.Errorf("kubernetes.ListUserVolumes: k8s client not configured (no-op mode)")
+	}
+	pvcs, err := c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: managedBySelector + "," + userHashLabelKey + "=" + hashUserID(userID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.ListUserVolumes: failed to list persistent volume claims: %w", err)
+	}
+
+	var volumes []*model.Volume
+	for _, pvc := range pvcs.Items {
+		// The hash label narrows the search; the annotation holds the exact owner.
+		if pvc.Annotations[UserIDAnnotationKey] != userID {
+			continue
+		}
+		size := ""
+		if q, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+			size = q.String()
+		}
+		volumes = append(volumes, &model.Volume{
+			Name:         pvc.Name,
+			UserID:       userID,
+			InstanceType: pvc.Annotations["hakoniwa.aplulu.me/instance-type"],
+			Size:         size,
+			Status:       string(pvc.Status.Phase),
+			CreatedAt:    pvc.CreationTimestamp.Time,
+		})
+	}
+	return volumes, nil
+}
+
+// DeleteVolume deletes the persistent volume claim backing a home volume.
+func (c *Client) DeleteVolume(ctx context.Context, volumeName string) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.DeleteVolume: k8s client not configured (no-op mode)")
+	}
+	err := c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).Delete(ctx, volumeName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("kubernetes.DeleteVolume: failed to delete persistent volume claim: %w", err)
+	}
+	c.logger.Info("Deleted home volume", "volume", volumeName)
+	return nil
+}
+
+// attachHomeVolume creates or reuses the user's home volume for the instance type and mounts it
+// into every container when the template declares the volume annotations.
+// The claim is not owned by the pod, so it survives instance deletion.
+func (c *Client) attachHomeVolume(ctx context.Context, instance *model.Instance, templateAnnotations map[string]string, pod *corev1.Pod) error {
+	size := templateAnnotations[volumeSizeAnnotationKey]
+	mountPath := templateAnnotations[volumeMountPathAnnotationKey]
+	if size == "" || mountPath == "" {
+		return nil
+	}
+
+	claimName, err := c.ensureHomeVolume(ctx, instance, size, templateAnnotations[volumeStorageClassAnnotationKey])
+	if err != nil {
+		return err
+	}
+
+	mountHomeVolume(pod, claimName, mountPath)
+	return nil
+}
+
+// mountHomeVolume adds the claim to the pod and mounts it into every container.
+func mountHomeVolume(pod *corev1.Pod, claimName, mountPath string) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: homeVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+			},
+		},
+	})
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      homeVolumeName,
+			MountPath: mountPath,
+		})
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[volumeNameAnnotationKey] = claimName
+}
+
+func (c *Client) ensureHomeVolume(ctx context.Context, instance *model.Instance, size, storageClass string) (string, error) {
+	claimName := homeVolumeClaimName(instance.UserID, instance.Type)
+
+	_, err := c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).Get(ctx, claimName, metav1.GetOptions{})
+	if err == nil {
+		return claimName, nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return "", fmt.Errorf("kubernetes.ensureHomeVolume: failed to get persistent volume claim: %w", err)
+	}
+
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return "", fmt.Errorf("kubernetes.ensureHomeVolume: invalid volume size %q: %w", size, err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: claimName,
+			Labels: map[string]string{
+				ManagedByLabelKey:            "hakoniwa",
+				userHashLabelKey:             hashUserID(instance.UserID),
+				"hakoniwa.aplulu.me/user-id": sanitizeUserID(instance.UserID),
+			},
+			Annotations: map[string]string{
+				UserIDAnnotationKey:                instance.UserID,
+				"hakoniwa.aplulu.me/instance-type": instance.Type,
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: quantity,
+				},
+			},
+		},
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	_, err = c.clientset.CoreV1().PersistentVolumeClaims(c.namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("kubernetes.ensureHomeVolume: failed to create persistent volume claim: %w", err)
+	}
+
+	c.logger.Info("Created home volume", "volume", claimName, "user", instance.UserID, "type", instance.Type, "size", size)
+	return claimName, nil
+}
+
+// homeVolumeClaimName derives a stable claim name from the user and instance type.
+// The user ID is hashed because sanitizing it for a resource name is lossy and could collide.
+func homeVolumeClaimName(userID, instanceType string) string {
+	if len(instanceType) > 40 {
+		instanceType = instanceType[:40]
+	}
+	return fmt.Sprintf("hakoniwa-home-%s-%s", instanceType, hashUserID(userID))
+}
+
+func hashUserID(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return hex.EncodeToString(sum[:])[:16]
+}
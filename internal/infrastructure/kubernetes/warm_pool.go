@@ -0,0 +1,201 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const (
+	// warmPoolLabelKey holds the instance type of an unclaimed pool pod. Claiming a pod removes it.
+	warmPoolLabelKey          = "hakoniwa.aplulu.me/warm-pool"
+	templateHashAnnotationKey = "hakoniwa.aplulu.me/template-hash"
+
+	warmPoolPodNamePrefix = "hakoniwa-pool-"
+)
+
+// ListWarmPoolPods returns the unclaimed warm pool pods, excluding terminating ones.
+func (c *Client) ListWarmPoolPods(ctx context.Context) ([]*model.WarmPoolPod, error) {
+	if c.clientset == nil {
+		return nil, fmt.Errorf("kubernetes.ListWarmPoolPods: k8s client not configured (no-op mode)")
+	}
+	pods, err := c.listPods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("kubernetes.ListWarmPoolPods: failed to list pods: %w", err)
+	}
+
+	var result []*model.WarmPoolPod
+	for _, pod := range pods {
+		instanceType := pod.Labels[warmPoolLabelKey]
+		if instanceType == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		result = append(result, &model.WarmPoolPod{
+			PodName:      pod.Name,
+			InstanceType: instanceType,
+			TemplateHash: pod.Annotations[templateHashAnnotationKey],
+			Ready:        isPodReady(pod),
+			Failed:       pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed,
+			CreatedAt:    pod.CreationTimestamp.Time,
+		})
+	}
+	return result, nil
+}
+
+// CreateWarmPoolPod starts an unclaimed pod of the instance type from the rendered template.
+func (c *Client) CreateWarmPoolPod(ctx context.Context, instanceType string, templateContent []byte) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.CreateWarmPoolPod: k8s client not configured (no-op mode)")
+	}
+	pod, err := buildWarmPoolPod(instanceType, templateContent)
+	if err != nil {
+		return fmt.Errorf("kubernetes.CreateWarmPoolPod: %w", err)
+	}
+	created, err := c.clientset.CoreV1().Pods(c.namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("kubernetes.CreateWarmPoolPod: failed to create pod: %w", err)
+	}
+	c.logger.Info("Created warm pool pod", "pod", created.Name, "type", instanceType)
+	return nil
+}
+
+// buildWarmPoolPod decodes the template and sets the labels and annotations of an unclaimed pool pod.
+// The pod has no owner yet, so HAKONIWA_INSTANCE_ID is not set; HAKONIWA_BASE_URL is "/", as instance types that
+// preserve the path prefix have no pool.
+func buildWarmPoolPod(instanceType string, templateContent []byte) (*corev1.Pod, error) {
+	u, resources, err := decodeTemplate(templateContent)
+	if err != nil {
+		return nil, err
+	}
+	if len(resources) > 0 {
+		return nil, fmt.Errorf("warm pool pods can't have bundled resources")
+	}
+
+	u.SetName("")
+	u.SetGenerateName(warmPoolPodNamePrefix)
+	labels := u.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[ManagedByLabelKey] = "hakoniwa"
+	labels[warmPoolLabelKey] = instanceType
+	u.SetLabels(labels)
+
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations["hakoniwa.aplulu.me/instance-type"] = instanceType
+	annotations[templateHashAnnotationKey] = config.TemplateHash(templateContent)
+	annotations[baseURLAnnotationKey] = "/"
+	u.SetAnnotations(annotations)
+
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &pod); err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured to pod: %w", err)
+	}
+	injectEnv(&pod, []corev1.EnvVar{{Name: "HAKONIWA_BASE_URL", Value: "/"}})
+	return &pod, nil
+}
+
+// ClaimWarmPoolPod hands an unclaimed pod of the instance's type started from the same template over to the instance
+// by labelling and annotating it as the instance's pod. Ready pods are claimed first, then the oldest ones.
+// A ready pod makes the instance running right away. It returns false if there is no pod to claim.
+func (c *Client) ClaimWarmPoolPod(ctx context.Context, instance *model.Instance, templateContent []byte) (bool, error) {
+	if c.clientset == nil {
+		return false, fmt.Errorf("kubernetes.ClaimWarmPoolPod: k8s client not configured (no-op mode)")
+	}
+	pods, err := c.listPods(ctx)
+	if err != nil {
+		return false, fmt.Errorf("kubernetes.ClaimWarmPoolPod: failed to list pods: %w", err)
+	}
+
+	hash := config.TemplateHash(templateContent)
+	var candidates []*corev1.Pod
+	for _, pod := range pods {
+		if pod.Labels[warmPoolLabelKey] != instance.Type || pod.Annotations[templateHashAnnotationKey] != hash {
+			continue
+		}
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if ri, rj := isPodReady(candidates[i]), isPodReady(candidates[j]); ri != rj {
+			return ri
+		}
+		return candidates[i].CreationTimestamp.Before(&candidates[j].CreationTimestamp)
+	})
+
+	for _, candidate := range candidates {
+		pod := candidate.DeepCopy()
+		delete(pod.Labels, warmPoolLabelKey)
+		pod.Labels["hakoniwa.aplulu.me/user-id"] = sanitizeUserID(instance.UserID)
+		pod.Labels[instanceIDLabelKey] = instance.InstanceID
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[UserIDAnnotationKey] = instance.UserID
+		pod.Annotations["hakoniwa.aplulu.me/instance-id"] = instance.InstanceID
+		pod.Annotations["hakoniwa.aplulu.me/display-name"] = instance.DisplayName
+		pod.Annotations[workloadAnnotationKey] = string(model.WorkloadKindPod)
+
+		// The update carries the cached resource version, so it fails if another request claimed the pod first
+		claimed, err := c.clientset.CoreV1().Pods(c.namespace).Update(ctx, pod, metav1.UpdateOptions{})
+		if err != nil {
+			if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("kubernetes.ClaimWarmPoolPod: failed to update pod: %w", err)
+		}
+		instance.PodName = claimed.Name
+		instance.StartupPhases = startupPhases(claimed)
+		if isPodReady(claimed) {
+			instance.Status = model.InstanceStatusRunning
+			instance.PodIP = claimed.Status.PodIP
+		}
+		c.logger.Info("Claimed warm pool pod", "pod", claimed.Name, "user", instance.UserID, "type", instance.Type)
+		return true, nil
+	}
+	return false, nil
+}
+
+// DeleteWarmPoolPod deletes an unclaimed pool pod. A pod claimed in the meantime is left alone.
+func (c *Client) DeleteWarmPoolPod(ctx context.Context, podName string) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.DeleteWarmPoolPod: k8s client not configured (no-op mode)")
+	}
+	pod, err := c.getPod(ctx, podName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("kubernetes.DeleteWarmPoolPod: failed to get pod: %w", err)
+	}
+	if pod.Labels[warmPoolLabelKey] == "" {
+		return nil
+	}
+
+	// The precondition fails if the pod was claimed after it was read
+	resourceVersion := pod.ResourceVersion
+	err = c.clientset.CoreV1().Pods(c.namespace).Delete(ctx, podName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &pod.UID, ResourceVersion: &resourceVersion},
+	})
+	if err != nil {
+		if k8serrors.IsNotFound(err) || k8serrors.IsConflict(err) {
+			return nil
+		}
+		return fmt.Errorf("kubernetes.DeleteWarmPoolPod: failed to delete pod: %w", err)
+	}
+	c.logger.Info("Deleted warm pool pod", "pod", podName)
+	return nil
+}
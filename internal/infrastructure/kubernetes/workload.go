@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const workloadAnnotationKey = "hakoniwa.aplulu.me/workload"
+
+// instanceObjectName is the name of the instance's pod or workload: hakoniwa-{instance_id}.
+func instanceObjectName(instanceID string) string {
+	return fmt.Sprintf("hakoniwa-%s", instanceID)
+}
+
+// createInstanceWorkload creates the pod, or a single-replica workload running it, and returns
+// the owner of the resources bundled with the instance.
+func (c *Client) createInstanceWorkload(ctx context.Context, instance *model.Instance, pod *corev1.Pod) (metav1.OwnerReference, error) {
+	switch instance.Workload {
+	case model.WorkloadKindStatefulSet, model.WorkloadKindDeployment:
+	default:
+		created, err := c.clientset.CoreV1().Pods(c.namespace).Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				// The previous pod of a restarted instance may still be terminating.
+				return metav1.OwnerReference{}, fmt.Errorf("pod %s already exists: %w", pod.Name, model.ErrInvalidInstanceState)
+			}
+			return metav1.OwnerReference{}, fmt.Errorf("failed to create pod: %w", err)
+		}
+		instance.PodName = created.Name
+		return metav1.OwnerReference{APIVersion: "v1", Kind: "Pod", Name: created.Name, UID: created.UID}, nil
+	}
+
+	// Kubernetes only recreates pods of workloads that always restart.
+	pod.Spec.RestartPolicy = corev1.RestartPolicyAlways
+	replicas := int32(1)
+	meta := metav1.ObjectMeta{
+		Name:        pod.Name,
+		Labels:      pod.Labels,
+		Annotations: pod.Annotations,
+	}
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{instanceIDLabelKey: instance.InstanceID},
+	}
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      pod.Labels,
+			Annotations: pod.Annotations,
+		},
+		Spec: pod.Spec,
+	}
+
+	if instance.Workload == model.WorkloadKindStatefulSet {
+		created, err := c.clientset.AppsV1().StatefulSets(c.namespace).Create(ctx, &appsv1.StatefulSet{
+			ObjectMeta: meta,
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: &replicas,
+				Selector: selector,
+				Template: template,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return metav1.OwnerReference{}, fmt.Errorf("statefulset %s already exists: %w", pod.Name, model.ErrInvalidInstanceState)
+			}
+			return metav1.OwnerReference{}, fmt.Errorf("failed to create statefulset: %w", err)
+		}
+		// The only replica of a StatefulSet has a stable name
+		instance.PodName = created.Name + "-0"
+		return metav1.OwnerReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: created.Name, UID: created.UID}, nil
+	}
+
+	created, err := c.clientset.AppsV1().Deployments(c.namespace).Create(ctx, &appsv1.Deployment{
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: selector,
+			Template: template,
+			// Never run two pods of an instance, e.g. on the same home volume
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return metav1.OwnerReference{}, fmt.Errorf("deployment %s already exists: %w", pod.Name, model.ErrInvalidInstanceState)
+		}
+		return metav1.OwnerReference{}, fmt.Errorf("failed to create deployment: %w", err)
+	}
+	// The pod name is generated; the syncer fills it in when the pod appears.
+	instance.PodName = ""
+	return metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: created.Name, UID: created.UID}, nil
+}
+
+// DeleteInstanceWorkload deletes the pod or workload of the instance.
+// Resources bundled with it are owned by it and deleted in the background.
+func (c *Client) DeleteInstanceWorkload(ctx context.Context, instance *model.Instance) error {
+	if c.clientset == nil {
+		return fmt.Errorf("kubernetes.DeleteInstanceWorkload: k8s client not configured (no-op mode)")
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &propagation}
+	name := instanceObjectName(instance.InstanceID)
+
+	var err error
+	switch instance.Workload {
+	case model.WorkloadKindStatefulSet:
+		err = c.clientset.AppsV1().StatefulSets(c.namespace).Delete(ctx, name, opts)
+	case model.WorkloadKindDeployment:
+		err = c.clientset.AppsV1().Deployments(c.namespace).Delete(ctx, name, opts)
+	default:
+		return c.DeletePod(ctx, instance.PodName)
+	}
+	if err != nil {
+		// If not found, consider it deleted (e.g. the instance is stopped)
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("kubernetes.DeleteInstanceWorkload: failed to delete %s: %w", instance.Workload, err)
+	}
+	c.logger.Info("Deleted instance workload", "kind", instance.Workload, "name", name)
+	return nil
+}
+
+// InstanceWorkloadExists returns true if the workload of the instance still exists, even while it has no pod.
+// Bare pods have no workload, so it returns false for them.
+func (c *Client) InstanceWorkloadExists(ctx context.Context, instance *model.Instance) (bool, error) {
+	if c.clientset == nil {
+		return false, fmt.Errorf("kubernetes.InstanceWorkloadExists: k8s client not configured (no-op mode)")
+	}
+
+	name := instanceObjectName(instance.InstanceID)
+	var err error
+	switch instance.Workload {
+	case model.WorkloadKindStatefulSet:
+		_, err = c.clientset.AppsV1().StatefulSets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	case model.WorkloadKindDeployment:
+		_, err = c.clientset.AppsV1().Deployments(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return false, nil
+	}
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("kubernetes.InstanceWorkloadExists: %w", err)
+	}
+	return true, nil
+}
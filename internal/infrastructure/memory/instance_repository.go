@@ -3,14 +3,18 @@ package memory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
 )
 
 type InstanceRepository struct {
 	instances sync.Map // map[string]*domain.Instance (Key: InstanceID)
+	// reserveMu serializes reservations so their quota checks see each other's instances.
+	reserveMu sync.Mutex
 }
 
 func NewInstanceRepository() *InstanceRepository {
@@ -18,7 +22,18 @@ func NewInstanceRepository() *InstanceRepository {
 }
 
 func (r *InstanceRepository) Save(ctx context.Context, instance *model.Instance) error {
-	r.instances.Store(instance.InstanceID, instance)
+	r.instances.Store(instance.InstanceID, cloneInstance(instance))
+	return nil
+}
+
+func (r *InstanceRepository) Reserve(ctx context.Context, instance *model.Instance, check func(ctx context.Context, counter repository.InstanceCounter) error) error {
+	r.reserveMu.Lock()
+	defer r.reserveMu.Unlock()
+
+	if err := check(ctx, r); err != nil {
+		return err
+	}
+	r.instances.Store(instance.InstanceID, cloneInstance(instance))
 	return nil
 }
 
@@ -27,7 +42,7 @@ func (r *InstanceRepository) FindByID(ctx context.Context, instanceID string) (*
 	if !ok {
 		return nil, fmt.Errorf("instance not found: %s", instanceID)
 	}
-	return val.(*model.Instance), nil
+	return cloneInstance(val.(*model.Instance)), nil
 }
 
 func (r *InstanceRepository) FindByUser(ctx context.Context, userID string) ([]*model.Instance, error) {
@@ -35,38 +50,108 @@ func (r *InstanceRepository) FindByUser(ctx context.Context, userID string) ([]*
 	r.instances.Range(func(key, value any) bool {
 		inst := value.(*model.Instance)
 		if inst.UserID == userID {
-			result = append(result, inst)
+			result = append(result, cloneInstance(inst))
 		}
 		return true
 	})
 	return result, nil
 }
 
+func (r *InstanceRepository) List(ctx context.Context) ([]*model.Instance, error) {
+	var result []*model.Instance
+	r.instances.Range(func(key, value any) bool {
+		result = append(result, cloneInstance(value.(*model.Instance)))
+		return true
+	})
+	return result, nil
+}
+
 func (r *InstanceRepository) Delete(ctx context.Context, instanceID string) error {
 	r.instances.Delete(instanceID)
 	return nil
 }
 
-func (r *InstanceRepository) ListInactive(ctx context.Context, threshold time.Duration) ([]*model.Instance, error) {
-	var inactiveInstances []*model.Instance
-	now := time.Now()
+func (r *InstanceRepository) UpdateLastActiveAt(ctx context.Context, instanceID string, lastActiveAt time.Time) error {
+	return r.update(instanceID, func(inst *model.Instance) error {
+		inst.LastActiveAt = lastActiveAt
+		return nil
+	})
+}
 
-	r.instances.Range(func(key, value any) bool {
-		instance := value.(*model.Instance)
-		// If LastActiveAt is older than the threshold, it's inactive.
-		if now.Sub(instance.LastActiveAt) > threshold {
-			inactiveInstances = append(inactiveInstances, instance)
+func (r *InstanceRepository) UpdateExpiresAt(ctx context.Context, instanceID string, expiresAt time.Time) error {
+	return r.update(instanceID, func(inst *model.Instance) error {
+		inst.ExpiresAt = expiresAt
+		return nil
+	})
+}
+
+func (r *InstanceRepository) UpdateLease(ctx context.Context, instanceID string, expiresAt, extendedUntil time.Time) error {
+	return r.update(instanceID, func(inst *model.Instance) error {
+		inst.ExpiresAt = expiresAt
+		inst.ExtendedUntil = extendedUntil
+		return nil
+	})
+}
+
+func (r *InstanceRepository) UpdateStatus(ctx context.Context, instance *model.Instance, expected model.InstanceStatus) error {
+	return r.update(instance.InstanceID, func(inst *model.Instance) error {
+		if inst.Status != expected {
+			return fmt.Errorf("instance %s is not %s: %w", instance.InstanceID, expected, model.ErrInvalidInstanceState)
 		}
-		return true
+		inst.Status = instance.Status
+		inst.PodName = instance.PodName
+		inst.PodIP = instance.PodIP
+		inst.StatusReason = instance.StatusReason
+		inst.StatusMessage = instance.StatusMessage
+		inst.StartupPhases = instance.StartupPhases
+		inst.ReapReason = instance.ReapReason
+		return nil
 	})
+}
+
+func (r *InstanceRepository) Requeue(ctx context.Context, instance *model.Instance) error {
+	return r.update(instance.InstanceID, func(inst *model.Instance) error {
+		if inst.Status != model.InstanceStatusPending {
+			return fmt.Errorf("instance %s is not %s: %w", instance.InstanceID, model.InstanceStatusPending, model.ErrInvalidInstanceState)
+		}
+		inst.Status = model.InstanceStatusQueued
+		inst.PodName = ""
+		inst.PodIP = ""
+		inst.StartedAt = instance.StartedAt
+		return nil
+	})
+}
+
+// update replaces the stored instance with a modified copy, so instances handed out are never changed underneath
+// their readers. It retries if the instance changes in the meantime, and gives up if modify returns an error.
+func (r *InstanceRepository) update(instanceID string, modify func(inst *model.Instance) error) error {
+	for {
+		val, ok := r.instances.Load(instanceID)
+		if !ok {
+			return fmt.Errorf("instance not found: %s", instanceID)
+		}
+		updated := cloneInstance(val.(*model.Instance))
+		if err := modify(updated); err != nil {
+			return err
+		}
+		if r.instances.CompareAndSwap(instanceID, val, updated) {
+			return nil
+		}
+	}
+}
 
-	return inactiveInstances, nil
+// cloneInstance copies an instance, so the repository and its callers never share one.
+func cloneInstance(instance *model.Instance) *model.Instance {
+	cloned := *instance
+	return &cloned
 }
 
 func (r *InstanceRepository) Count(ctx context.Context) (int, error) {
 	count := 0
 	r.instances.Range(func(key, value any) bool {
-		count++
+		if value.(*model.Instance).HasPod() {
+			count++
+		}
 		return true
 	})
 	return count, nil
@@ -94,4 +179,76 @@ func (r *InstanceRepository) CountByUserAndType(ctx context.Context, userID, ins
 		return true
 	})
 	return count, nil
-}
\ No newline at end of file
+}
+
+func (r *InstanceRepository) CountByUserAndTypeWithPod(ctx context.Context, userID, instanceType string) (int, error) {
+	count := 0
+	r.instances.Range(func(key, value any) bool {
+		inst := value.(*model.Instance)
+		if inst.UserID == userID && inst.Type == instanceType && inst.HasPod() {
+			count++
+		}
+		return true
+	})
+	return count, nil
+}
+
+func (r *InstanceRepository) CountByType(ctx context.Context, instanceType string) (int, error) {
+	count := 0
+	r.instances.Range(func(key, value any) bool {
+		inst := value.(*model.Instance)
+		if inst.Type == instanceType && inst.HasPod() {
+			count++
+		}
+		return true
+	})
+	return count, nil
+}
+
+func (r *InstanceRepository) CountQueued(ctx context.Context) (int, error) {
+	count := 0
+	r.instances.Range(func(key, value any) bool {
+		if value.(*model.Instance).Status == model.InstanceStatusQueued {
+			count++
+		}
+		return true
+	})
+	return count, nil
+}
+
+func (r *InstanceRepository) ListQueued(ctx context.Context) ([]*model.Instance, error) {
+	var result []*model.Instance
+	r.instances.Range(func(key, value any) bool {
+		if inst := value.(*model.Instance); inst.Status == model.InstanceStatusQueued {
+			result = append(result, cloneInstance(inst))
+		}
+		return true
+	})
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].QueuePriority != result[j].QueuePriority {
+			return result[i].QueuePriority > result[j].QueuePriority
+		}
+		return result[i].StartedAt.Before(result[j].StartedAt)
+	})
+	return result, nil
+}
+
+func (r *InstanceRepository) SumResources(ctx context.Context) (model.Resources, error) {
+	return r.sumResources(func(inst *model.Instance) bool { return inst.HasPod() }), nil
+}
+
+func (r *InstanceRepository) SumResourcesByUser(ctx context.Context, userID string) (model.Resources, error) {
+	return r.sumResources(func(inst *model.Instance) bool { return inst.UserID == userID }), nil
+}
+
+func (r *InstanceRepository) sumResources(match func(*model.Instance) bool) model.Resources {
+	var sum model.Resources
+	r.instances.Range(func(key, value any) bool {
+		inst := value.(*model.Instance)
+		if inst.Status != model.InstanceStatusStopped && inst.StatusReason != model.StatusReasonProvisioningFailed && match(inst) {
+			sum = sum.Add(inst.Resources)
+		}
+		return true
+	})
+	return sum
+}
@@ -5,32 +5,50 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/aplulu/hakoniwa/internal/domain/model"
 	"github.com/aplulu/hakoniwa/internal/domain/repository"
 )
 
+const (
+	// InactivityActionDelete deletes reaped instances.
+	InactivityActionDelete = "delete"
+	// InactivityActionStop stops reaped instances, keeping the record and volumes so they can be started again.
+	InactivityActionStop = "stop"
+)
+
 type InactivityCleaner struct {
 	instanceRepo repository.InstanceRepository
 	k8sClient    repository.KubernetesClient
 	logger       *slog.Logger
-	timeout      time.Duration
+	policy       ReapPolicy
+	action       string
+	// warningPeriod is how long an instance is marked as expiring before it is reaped.
+	warningPeriod time.Duration
+	dryRun        bool
 }
 
 func NewInactivityCleaner(
 	instanceRepo repository.InstanceRepository,
 	k8sClient repository.KubernetesClient,
 	logger *slog.Logger,
-	timeout time.Duration,
+	policy ReapPolicy,
+	action string,
+	warningPeriod time.Duration,
+	dryRun bool,
 ) *InactivityCleaner {
 	return &InactivityCleaner{
-		instanceRepo: instanceRepo,
-		k8sClient:    k8sClient,
-		logger:       logger,
-		timeout:      timeout,
+		instanceRepo:  instanceRepo,
+		k8sClient:     k8sClient,
+		logger:        logger,
+		policy:        policy,
+		action:        action,
+		warningPeriod: warningPeriod,
+		dryRun:        dryRun,
 	}
 }
 
 func (c *InactivityCleaner) Start(ctx context.Context) {
-	c.logger.Info("Starting inactivity cleaner", "timeout", c.timeout)
+	c.logger.Info("Starting inactivity cleaner", "action", c.action, "warning_period", c.warningPeriod, "dry_run", c.dryRun)
 	ticker := time.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
@@ -46,29 +64,119 @@ func (c *InactivityCleaner) Start(ctx context.Context) {
 }
 
 func (c *InactivityCleaner) cleanup(ctx context.Context) {
-	instances, err := c.instanceRepo.ListInactive(ctx, c.timeout)
+	all, err := c.instanceRepo.List(ctx)
 	if err != nil {
-		c.logger.Error("Failed to list inactive instances", "error", err)
+		c.logger.Error("Failed to list instances", "error", err)
 		return
 	}
 
-	if len(instances) > 0 {
-		c.logger.Info("Found inactive instances", "count", len(instances))
+	instances := make([]*model.Instance, 0, len(all))
+	for _, instance := range all {
+		if instance.Status.HasPod() {
+			instances = append(instances, instance)
+		}
 	}
 
-	for _, instance := range instances {
-		c.logger.Info("Cleaning up inactive instance", "user_id", instance.UserID, "pod_name", instance.PodName)
+	now := time.Now()
+	decisions := c.policy.Evaluate(now, instances)
+	if len(decisions) > 0 {
+		c.logger.Info("Found instances to reap", "count", len(decisions), "dry_run", c.dryRun)
+	}
 
-		// Delete Pod from K8s
-		if err := c.k8sClient.DeletePod(ctx, instance.PodName); err != nil {
-			c.logger.Error("Failed to delete pod", "pod_name", instance.PodName, "error", err)
-			// If K8s deletion fails, skip repository deletion to retry later
+	selected := make(map[string]bool, len(decisions))
+	for _, d := range decisions {
+		instance := d.Instance
+		if instance.ExtendedUntil.After(now) {
+			// The user extended the lease
 			continue
 		}
+		selected[instance.InstanceID] = true
+
+		if c.dryRun {
+			c.logger.Info("Would reap instance", "instance_id", instance.InstanceID, "user_id", instance.UserID, "action", c.action, "reason", d.Reason, "detail", d.Detail)
+			continue
+		}
+
+		// Warn first so the user gets a chance to extend the lease.
+		if c.warningPeriod > 0 {
+			if instance.ExpiresAt.IsZero() {
+				expiresAt := now.Add(c.warningPeriod)
+				c.logger.Info("Instance is expiring", "instance_id", instance.InstanceID, "user_id", instance.UserID, "expires_at", expiresAt, "reason", d.Reason, "detail", d.Detail)
+				if err := c.instanceRepo.UpdateExpiresAt(ctx, instance.InstanceID, expiresAt); err != nil {
+					c.logger.Error("Failed to mark instance as expiring", "instance_id", instance.InstanceID, "error", err)
+				}
+				continue
+			}
+			if now.Before(instance.ExpiresAt) {
+				continue
+			}
+		}
+
+		if c.action == InactivityActionStop {
+			c.stop(ctx, d)
+			continue
+		}
+
+		c.logger.Info("Deleting instance", "instance_id", instance.InstanceID, "user_id", instance.UserID, "pod_name", instance.PodName, "reason", d.Reason, "detail", d.Detail)
+
+		// Delete Pod from K8s, unless it could not be created
+		if instance.HasPod() {
+			if err := c.k8sClient.DeleteInstanceWorkload(ctx, instance); err != nil {
+				c.logger.Error("Failed to delete pod", "pod_name", instance.PodName, "error", err)
+				// If K8s deletion fails, skip repository deletion to retry later
+				continue
+			}
+		}
 
 		// Delete from Repository
-		if err := c.instanceRepo.Delete(ctx, instance.UserID); err != nil {
-			c.logger.Error("Failed to delete instance from repository", "user_id", instance.UserID, "error", err)
+		if err := c.instanceRepo.Delete(ctx, instance.InstanceID); err != nil {
+			c.logger.Error("Failed to delete instance from repository", "instance_id", instance.InstanceID, "error", err)
+		}
+	}
+
+	// Cancel the warning of instances no policy selects anymore (e.g. the user came back).
+	for _, instance := range instances {
+		if instance.ExpiresAt.IsZero() || selected[instance.InstanceID] {
+			continue
+		}
+		c.logger.Info("Instance is no longer expiring", "instance_id", instance.InstanceID, "user_id", instance.UserID)
+		if err := c.instanceRepo.UpdateExpiresAt(ctx, instance.InstanceID, time.Time{}); err != nil {
+			c.logger.Error("Failed to clear instance expiry", "instance_id", instance.InstanceID, "error", err)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// stop deletes the pod of a reaped instance but keeps its record so the user can start it again.
+func (c *InactivityCleaner) stop(ctx context.Context, d ReapDecision) {
+	instance := d.Instance
+	c.logger.Info("Stopping instance", "instance_id", instance.InstanceID, "user_id", instance.UserID, "pod_name", instance.PodName, "reason", d.Reason, "detail", d.Detail)
+
+	// Mark as stopped first so the syncer keeps the record when the pod disappears.
+	stopped := *instance
+	stopped.Status = model.InstanceStatusStopped
+	stopped.PodIP = ""
+	stopped.StatusReason = ""
+	stopped.StatusMessage = ""
+	stopped.StartupPhases = nil
+	stopped.ReapReason = d.Reason
+	stopped.ExpiresAt = time.Time{}
+	if err := c.instanceRepo.UpdateStatus(ctx, &stopped, instance.Status); err != nil {
+		c.logger.Error("Failed to save stopped instance", "instance_id", instance.InstanceID, "error", err)
+		return
+	}
+	if err := c.instanceRepo.UpdateExpiresAt(ctx, instance.InstanceID, stopped.ExpiresAt); err != nil {
+		c.logger.Error("Failed to clear instance expiry", "instance_id", instance.InstanceID, "error", err)
+	}
+
+	if !instance.HasPod() {
+		// Its pod could not be created; there is nothing to delete
+		return
+	}
+	if err := c.k8sClient.DeleteInstanceWorkload(ctx, instance); err != nil {
+		c.logger.Error("Failed to delete pod", "pod_name", instance.PodName, "error", err)
+		// Restore the previous state to retry later
+		if err := c.instanceRepo.UpdateStatus(ctx, instance, model.InstanceStatusStopped); err != nil {
+			c.logger.Error("Failed to restore instance", "instance_id", instance.InstanceID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package background
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/memory"
+)
+
+// reapAllPolicy selects every instance, to test what the cleaner does with its decisions.
+type reapAllPolicy struct{}
+
+func (reapAllPolicy) Evaluate(now time.Time, instances []*model.Instance) []ReapDecision {
+	decisions := make([]ReapDecision, 0, len(instances))
+	for _, inst := range instances {
+		decisions = append(decisions, ReapDecision{Instance: inst, Reason: model.ReapReasonIdleTimeout})
+	}
+	return decisions
+}
+
+// fakeWorkloadDeleter records the instances whose workload the cleaner deletes.
+type fakeWorkloadDeleter struct {
+	repository.KubernetesClient
+	deleted []string
+}
+
+func (f *fakeWorkloadDeleter) DeleteInstanceWorkload(ctx context.Context, instance *model.Instance) error {
+	f.deleted = append(f.deleted, instance.InstanceID)
+	return nil
+}
+
+func newTestCleaner(t *testing.T, action string) (*InactivityCleaner, *memory.InstanceRepository, *fakeWorkloadDeleter) {
+	t.Helper()
+	repo := memory.NewInstanceRepository()
+	if err := repo.Save(context.Background(), &model.Instance{
+		InstanceID:   "instance-1",
+		UserID:       "alice",
+		PodName:      "hakoniwa-instance-1",
+		Status:       model.InstanceStatusRunning,
+		LastActiveAt: time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatalf("Failed to save instance: %v", err)
+	}
+	k8s := &fakeWorkloadDeleter{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewInactivityCleaner(repo, k8s, logger, reapAllPolicy{}, action, time.Hour, false), repo, k8s
+}
+
+func TestInactivityCleaner_WarnsBeforeReaping(t *testing.T) {
+	ctx := context.Background()
+	cleaner, repo, k8s := newTestCleaner(t, InactivityActionDelete)
+
+	cleaner.cleanup(ctx)
+
+	got, err := repo.FindByID(ctx, "instance-1")
+	if err != nil {
+		t.Fatalf("Expected the instance to be kept during the warning period: %v", err)
+	}
+	if until := time.Until(got.ExpiresAt); until <= 0 || until > time.Hour {
+		t.Errorf("Expected the instance to expire within the warning period, got %v", got.ExpiresAt)
+	}
+	if len(k8s.deleted) != 0 {
+		t.Errorf("Expected no workload to be deleted, got %v", k8s.deleted)
+	}
+}
+
+func TestInactivityCleaner_KeepsExtendedInstances(t *testing.T) {
+	ctx := context.Background()
+	cleaner, repo, k8s := newTestCleaner(t, InactivityActionDelete)
+
+	cleaner.cleanup(ctx)
+	// The user extends the lease after the warning, and the warning period ends
+	if err := repo.UpdateLease(ctx, "instance-1", time.Time{}, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to extend the lease: %v", err)
+	}
+	cleaner.cleanup(ctx)
+
+	got, err := repo.FindByID(ctx, "instance-1")
+	if err != nil {
+		t.Fatalf("Expected the extended instance to be kept: %v", err)
+	}
+	if !got.ExpiresAt.IsZero() {
+		t.Errorf("Expected the extended instance not to be warned again, got %v", got.ExpiresAt)
+	}
+	if len(k8s.deleted) != 0 {
+		t.Errorf("Expected no workload to be deleted, got %v", k8s.deleted)
+	}
+}
+
+func TestInactivityCleaner_ReapsAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delete", func(t *testing.T) {
+		cleaner, repo, k8s := newTestCleaner(t, InactivityActionDelete)
+		cleaner.cleanup(ctx)
+		if err := repo.UpdateExpiresAt(ctx, "instance-1", time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("Failed to end the warning period: %v", err)
+		}
+		cleaner.cleanup(ctx)
+
+		if _, err := repo.FindByID(ctx, "instance-1"); err == nil {
+			t.Errorf("Expected the instance to be deleted")
+		}
+		if len(k8s.deleted) != 1 || k8s.deleted[0] != "instance-1" {
+			t.Errorf("Expected the workload to be deleted, got %v", k8s.deleted)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		cleaner, repo, k8s := newTestCleaner(t, InactivityActionStop)
+		cleaner.cleanup(ctx)
+		if err := repo.UpdateExpiresAt(ctx, "instance-1", time.Now().Add(-time.Second)); err != nil {
+			t.Fatalf("Failed to end the warning period: %v", err)
+		}
+		cleaner.cleanup(ctx)
+
+		got, err := repo.FindByID(ctx, "instance-1")
+		if err != nil {
+			t.Fatalf("Expected the stopped instance to be kept: %v", err)
+		}
+		if got.Status != model.InstanceStatusStopped || got.ReapReason != model.ReapReasonIdleTimeout || !got.ExpiresAt.IsZero() {
+			t.Errorf("Unexpected stopped instance: %+v", got)
+		}
+		if len(k8s.deleted) != 1 || k8s.deleted[0] != "instance-1" {
+			t.Errorf("Expected the workload to be deleted, got %v", k8s.deleted)
+		}
+	})
+}
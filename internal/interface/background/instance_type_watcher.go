@@ -0,0 +1,40 @@
+package background
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+	"github.com/aplulu/hakoniwa/internal/usecase"
+)
+
+// InstanceTypeWatcher reloads the instance types when the pod template source changes.
+// Unlike the other workers it runs on every replica.
+type InstanceTypeWatcher struct {
+	source  repository.TemplateSource
+	usecase usecase.InstanceTypeManagement
+	logger  *slog.Logger
+}
+
+func NewInstanceTypeWatcher(
+	source repository.TemplateSource,
+	usecase usecase.InstanceTypeManagement,
+	logger *slog.Logger,
+) *InstanceTypeWatcher {
+	return &InstanceTypeWatcher{
+		source:  source,
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (w *InstanceTypeWatcher) Start(ctx context.Context) {
+	w.logger.Info("Starting instance type watcher")
+	w.source.Watch(ctx, func(ctx context.Context) {
+		if _, err := w.usecase.ReloadInstanceTypes(ctx); err != nil {
+			// Keep serving the previous instance types until the template is fixed.
+			w.logger.Error("Failed to reload instance types", "error", err)
+		}
+	})
+	w.logger.Info("Stopping instance type watcher")
+}
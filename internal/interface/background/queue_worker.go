@@ -0,0 +1,44 @@
+package background
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/usecase"
+)
+
+// QueueWorker provisions queued instances as cluster capacity frees up.
+type QueueWorker struct {
+	usecase usecase.InstanceManagement
+	logger  *slog.Logger
+}
+
+func NewQueueWorker(
+	usecase usecase.InstanceManagement,
+	logger *slog.Logger,
+) *QueueWorker {
+	return &QueueWorker{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (w *QueueWorker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Starting queue worker", "interval", interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Stopping queue worker")
+			return
+		case <-ticker.C:
+			if err := w.usecase.ProvisionQueuedInstances(ctx); err != nil {
+				w.logger.Error("Failed to provision queued instances", "error", err)
+			}
+		}
+	}
+}
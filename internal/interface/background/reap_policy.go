@@ -0,0 +1,228 @@
+package background
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const (
+	ReapPolicyIdleTimeout = "idle-timeout"
+	ReapPolicyMaxLifetime = "max-lifetime"
+	ReapPolicyOffHours    = "off-hours"
+	ReapPolicyOverQuota   = "over-quota"
+)
+
+// ReapDecision is an instance selected for reaping by a policy.
+type ReapDecision struct {
+	Instance *model.Instance
+	Reason   model.ReapReason
+	Detail   string
+}
+
+// ReapPolicy selects the instances to reap among the active ones.
+type ReapPolicy interface {
+	Evaluate(now time.Time, instances []*model.Instance) []ReapDecision
+}
+
+// ReapPolicyChain evaluates policies in order. An instance is reaped by the first policy that selects it,
+// and later policies only see the instances left by the earlier ones.
+type ReapPolicyChain []ReapPolicy
+
+// NewReapPolicyChain builds a chain from policy names.
+func NewReapPolicyChain(names []string) (ReapPolicyChain, error) {
+	chain := make(ReapPolicyChain, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case ReapPolicyIdleTimeout:
+			chain = append(chain, idleTimeoutPolicy{})
+		case ReapPolicyMaxLifetime:
+			chain = append(chain, maxLifetimePolicy{})
+		case ReapPolicyOffHours:
+			chain = append(chain, offHoursPolicy{})
+		case ReapPolicyOverQuota:
+			// The users' auth methods and groups are unknown here, so the most generous quota profile applies.
+			maxPerUser := config.MaxInstancesPerUser()
+			for _, profile := range config.QuotaProfiles() {
+				maxPerUser = max(maxPerUser, profile.MaxInstancesPerUser)
+			}
+			chain = append(chain, overQuotaPolicy{
+				maxTotal:          config.MaxPodCount(),
+				maxPerUser:        maxPerUser,
+				maxPerUserPerType: config.MaxInstancesPerUserPerType(),
+			})
+		default:
+			return nil, fmt.Errorf("background.NewReapPolicyChain: unknown policy: %s", name)
+		}
+	}
+	return chain, nil
+}
+
+func (c ReapPolicyChain) Evaluate(now time.Time, instances []*model.Instance) []ReapDecision {
+	var decisions []ReapDecision
+	remaining := instances
+	for _, p := range c {
+		selected := p.Evaluate(now, remaining)
+		if len(selected) == 0 {
+			continue
+		}
+		decisions = append(decisions, selected...)
+
+		reaped := make(map[string]bool, len(selected))
+		for _, d := range selected {
+			reaped[d.Instance.InstanceID] = true
+		}
+		next := make([]*model.Instance, 0, len(remaining)-len(selected))
+		for _, inst := range remaining {
+			if !reaped[inst.InstanceID] {
+				next = append(next, inst)
+			}
+		}
+		remaining = next
+	}
+	return decisions
+}
+
+// idleTimeoutPolicy reaps instances that have not been accessed for longer than the idle timeout.
+type idleTimeoutPolicy struct{}
+
+func (idleTimeoutPolicy) Evaluate(now time.Time, instances []*model.Instance) []ReapDecision {
+	var decisions []ReapDecision
+	for _, inst := range instances {
+		timeout := config.InstanceInactivityTimeout()
+		if it, ok := config.GetInstanceType(inst.Type); ok {
+			timeout = it.IdleTimeout
+		}
+		if timeout <= 0 {
+			continue
+		}
+		if idle := now.Sub(inst.LastActiveAt); idle > timeout {
+			decisions = append(decisions, ReapDecision{
+				Instance: inst,
+				Reason:   model.ReapReasonIdleTimeout,
+				Detail:   fmt.Sprintf("idle for %s (timeout %s)", idle.Truncate(time.Second), timeout),
+			})
+		}
+	}
+	return decisions
+}
+
+// maxLifetimePolicy reaps instances that have been running for longer than the maximum lifetime.
+type maxLifetimePolicy struct{}
+
+func (maxLifetimePolicy) Evaluate(now time.Time, instances []*model.Instance) []ReapDecision {
+	var decisions []ReapDecision
+	for _, inst := range instances {
+		lifetime := config.InstanceMaxLifetime()
+		if it, ok := config.GetInstanceType(inst.Type); ok {
+			lifetime = it.MaxLifetime
+		}
+		if lifetime <= 0 {
+			continue
+		}
+		startedAt := inst.StartedAt
+		if startedAt.IsZero() {
+			startedAt = inst.CreatedAt
+		}
+		if age := now.Sub(startedAt); age > lifetime {
+			decisions = append(decisions, ReapDecision{
+				Instance: inst,
+				Reason:   model.ReapReasonMaxLifetime,
+				Detail:   fmt.Sprintf("running for %s (max lifetime %s)", age.Truncate(time.Second), lifetime),
+			})
+		}
+	}
+	return decisions
+}
+
+// offHoursPolicy reaps instances started before the current off-hours window began.
+// Instances started during off-hours are left alone so users can still work late on purpose.
+type offHoursPolicy struct{}
+
+func (offHoursPolicy) Evaluate(now time.Time, instances []*model.Instance) []ReapDecision {
+	var decisions []ReapDecision
+	for _, inst := range instances {
+		window := config.GetOffHours()
+		if it, ok := config.GetInstanceType(inst.Type); ok {
+			window = it.OffHours
+		}
+		if window == nil {
+			continue
+		}
+		windowStart, ok := window.WindowStart(now)
+		if !ok {
+			continue
+		}
+		startedAt := inst.StartedAt
+		if startedAt.IsZero() {
+			startedAt = inst.CreatedAt
+		}
+		if startedAt.Before(windowStart) {
+			decisions = append(decisions, ReapDecision{
+				Instance: inst,
+				Reason:   model.ReapReasonOffHours,
+				Detail:   fmt.Sprintf("off-hours since %s", windowStart.Format(time.RFC3339)),
+			})
+		}
+	}
+	return decisions
+}
+
+// overQuotaPolicy evicts the least recently active instances exceeding the instance limits,
+// e.g. after the limits were lowered.
+type overQuotaPolicy struct {
+	maxTotal          int
+	maxPerUser        int
+	maxPerUserPerType int
+}
+
+func (p overQuotaPolicy) Evaluate(now time.Time, instances []*model.Instance) []ReapDecision {
+	sorted := make([]*model.Instance, len(instances))
+	copy(sorted, instances)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LastActiveAt.After(sorted[j].LastActiveAt)
+	})
+
+	var decisions []ReapDecision
+	total := 0
+	perUser := make(map[string]int)
+	perType := make(map[string]int)
+	perUserPerType := make(map[string]int)
+	for _, inst := range sorted {
+		typeKey := inst.UserID + "/" + inst.Type
+
+		maxPerType, maxPerUserPerType := 0, p.maxPerUserPerType
+		if it, ok := config.GetInstanceType(inst.Type); ok {
+			maxPerType, maxPerUserPerType = it.MaxInstances, it.MaxInstancesPerUser
+		}
+
+		var detail string
+		switch {
+		case p.maxTotal > 0 && total >= p.maxTotal:
+			detail = fmt.Sprintf("exceeds global limit of %d", p.maxTotal)
+		case maxPerType > 0 && perType[inst.Type] >= maxPerType:
+			detail = fmt.Sprintf("exceeds limit of %d for type %s", maxPerType, inst.Type)
+		case p.maxPerUser > 0 && perUser[inst.UserID] >= p.maxPerUser:
+			detail = fmt.Sprintf("exceeds per-user limit of %d", p.maxPerUser)
+		case maxPerUserPerType > 0 && perUserPerType[typeKey] >= maxPerUserPerType:
+			detail = fmt.Sprintf("exceeds per-user limit of %d for type %s", maxPerUserPerType, inst.Type)
+		}
+		if detail != "" {
+			decisions = append(decisions, ReapDecision{
+				Instance: inst,
+				Reason:   model.ReapReasonOverQuota,
+				Detail:   detail,
+			})
+			continue
+		}
+
+		total++
+		perType[inst.Type]++
+		perUser[inst.UserID]++
+		perUserPerType[typeKey]++
+	}
+	return decisions
+}
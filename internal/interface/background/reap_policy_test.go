@@ -0,0 +1,40 @@
+package background
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+func TestOverQuotaPolicyEvictsLeastRecentlyActive(t *testing.T) {
+	now := time.Now()
+	instances := []*model.Instance{
+		{InstanceID: "a1", UserID: "alice", Type: "webtop", LastActiveAt: now.Add(-3 * time.Minute)},
+		{InstanceID: "a2", UserID: "alice", Type: "webtop", LastActiveAt: now.Add(-1 * time.Minute)},
+		{InstanceID: "a3", UserID: "alice", Type: "vscode", LastActiveAt: now.Add(-2 * time.Minute)},
+		{InstanceID: "b1", UserID: "bob", Type: "webtop", LastActiveAt: now},
+	}
+
+	policy := ReapPolicyChain{overQuotaPolicy{maxTotal: 2, maxPerUser: 2, maxPerUserPerType: 1}}
+	decisions := policy.Evaluate(now, instances)
+
+	got := make(map[string]bool)
+	for _, d := range decisions {
+		if d.Reason != model.ReapReasonOverQuota {
+			t.Errorf("reason for %s = %s, want %s", d.Instance.InstanceID, d.Reason, model.ReapReasonOverQuota)
+		}
+		got[d.Instance.InstanceID] = true
+	}
+
+	// b1 and a2 are kept; a3 exceeds the global limit and a1 the per-type limit.
+	want := map[string]bool{"a1": true, "a3": true}
+	if len(got) != len(want) {
+		t.Fatalf("reaped %v, want %v", got, want)
+	}
+	for id := range want {
+		if !got[id] {
+			t.Errorf("expected %s to be reaped, got %v", id, got)
+		}
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/aplulu/hakoniwa/internal/domain/model"
 	"github.com/aplulu/hakoniwa/internal/domain/repository"
 )
 
+// recentInstanceGracePeriod protects freshly created or started instances, which are reserved before their pods
+// are created and whose pods may not be in the pod cache yet.
+const recentInstanceGracePeriod = 30 * time.Second
+
 type InstanceSyncer struct {
 	instanceRepo repository.InstanceRepository
 	k8sClient    repository.KubernetesClient
@@ -32,6 +37,11 @@ func (s *InstanceSyncer) Start(ctx context.Context, interval time.Duration) {
 
 	s.logger.Info("Starting instance syncer", "interval", interval)
 
+	// Pod changes are applied as they happen; the periodic sync only reconciles missed events.
+	if err := s.k8sClient.WatchInstancePods(ctx, s); err != nil {
+		s.logger.Error("Failed to watch instance pods", "error", err)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -47,6 +57,8 @@ func (s *InstanceSyncer) Start(ctx context.Context, interval time.Duration) {
 
 func (s *InstanceSyncer) sync(ctx context.Context) error {
 	// 1. Get all instances from K8s
+	// Unclaimed warm pool pods are not instances and are left to the WarmPoolWorker; a claimed pool pod
+	// carries the instance's annotations and is synced like any other instance pod.
 	k8sInstances, err := s.k8sClient.ListInstancePods(ctx)
 	if err != nil {
 		return err
@@ -56,57 +68,133 @@ func (s *InstanceSyncer) sync(ctx context.Context) error {
 	k8sMap := make(map[string]struct{})
 	for _, inst := range k8sInstances {
 		k8sMap[inst.InstanceID] = struct{}{}
-		
+
 		// 2. Update or Add to Repo
-		existing, err := s.instanceRepo.FindByID(ctx, inst.InstanceID)
-		if err != nil {
-			// Not found in repo -> Add (Recovery)
-			// Log?
-			// s.logger.Info("Recovering instance from K8s", "id", inst.InstanceID)
-			if err := s.instanceRepo.Save(ctx, inst); err != nil {
-				s.logger.Error("Failed to save recovered instance", "id", inst.InstanceID, "error", err)
-			}
-		} else {
-			// Found -> Update Status and IP only
-			existing.Status = inst.Status
-			existing.PodIP = inst.PodIP
-			// existing.LastActiveAt is PRESERVED
-			if err := s.instanceRepo.Save(ctx, existing); err != nil {
-				s.logger.Error("Failed to update instance", "id", inst.InstanceID, "error", err)
-			}
-		}
+		s.OnInstancePodChanged(ctx, inst)
 	}
 
 	// 3. Remove instances from Repo that are not in K8s
 	// Note: ListInstancePods excludes Terminated/Failed/Succeeded.
 	// So if it's in Repo but not in k8sInstances, it's effectively dead/gone.
-	
-	// We need a way to iterate ALL instances in repo.
-	// InstanceRepository.Count/List?
-	// The current interface doesn't have "ListAll".
-	// It has `ListInactive`.
-	// I should add `ListAll` or `Iterate` to Repository interface?
-	// Memory implementation has `Range`.
-	// Let's add `ListAll(ctx)` to repository interface.
-	
-	// Wait, `ListInactive(0)` returns all?
-	// `now.Sub(instance.LastActiveAt) > threshold`.
-	// If threshold is -1h (negative), `now - lastActive > -1h` -> always true?
-	// Yes.
-	allInstances, err := s.instanceRepo.ListInactive(ctx, -10000*time.Hour)
+	allInstances, err := s.instanceRepo.List(ctx)
 	if err != nil {
 		return err
 	}
 
 	for _, repoInst := range allInstances {
+		// Stopped and queued instances keep their record without a pod
+		if !repoInst.Status.HasPod() {
+			continue
+		}
 		if _, ok := k8sMap[repoInst.InstanceID]; !ok {
+			if time.Since(repoInst.StartedAt) < recentInstanceGracePeriod {
+				continue
+			}
+			// Its pod was never created; it stays failed so the user sees why
+			if repoInst.StatusReason == model.StatusReasonProvisioningFailed {
+				continue
+			}
 			// Not in K8s list -> Delete
 			// s.logger.Info("Removing missing instance from repo", "id", repoInst.InstanceID)
-			if err := s.instanceRepo.Delete(ctx, repoInst.InstanceID); err != nil {
-				s.logger.Error("Failed to delete missing instance", "id", repoInst.InstanceID, "error", err)
-			}
+			s.removeInstance(ctx, repoInst)
 		}
 	}
 
 	return nil
 }
+
+// OnInstancePodChanged updates the status and IP of the instance, recovering it into the repository if unknown.
+func (s *InstanceSyncer) OnInstancePodChanged(ctx context.Context, inst *model.Instance) {
+	existing, err := s.instanceRepo.FindByID(ctx, inst.InstanceID)
+	if err != nil {
+		// Not found in repo -> Add (Recovery)
+		if err := s.instanceRepo.Save(ctx, inst); err != nil {
+			s.logger.Error("Failed to save recovered instance", "id", inst.InstanceID, "error", err)
+		}
+		return
+	}
+
+	// A stopped instance's pod is being deleted; ignore its late updates.
+	if existing.Status == model.InstanceStatusStopped {
+		return
+	}
+
+	if existing.Status == inst.Status && existing.PodIP == inst.PodIP && existing.PodName == inst.PodName &&
+		existing.StatusReason == inst.StatusReason && existing.StatusMessage == inst.StatusMessage &&
+		model.EqualStartupPhases(existing.StartupPhases, inst.StartupPhases) {
+		return
+	}
+	if inst.StartupPhase() == model.StartupPhaseReady && existing.StartupPhase() != model.StartupPhaseReady {
+		s.logStartup(inst)
+	}
+
+	// Found -> Update Status and IP only (and the pod name, which changes when a workload recreates its pod)
+	previous := existing.Status
+	existing.Status = inst.Status
+	existing.StatusReason = inst.StatusReason
+	existing.StatusMessage = inst.StatusMessage
+	existing.StartupPhases = inst.StartupPhases
+	existing.PodIP = inst.PodIP
+	existing.PodName = inst.PodName
+	// existing.LastActiveAt is PRESERVED
+	if err := s.instanceRepo.UpdateStatus(ctx, existing, previous); err != nil {
+		s.logger.Error("Failed to update instance", "id", inst.InstanceID, "error", err)
+	}
+}
+
+// logStartup logs how long the instance spent in each startup phase, to see where startup time goes.
+func (s *InstanceSyncer) logStartup(inst *model.Instance) {
+	phases := inst.StartupPhases
+	args := []any{"id", inst.InstanceID, "type", inst.Type, "since_scheduled", phases[len(phases)-1].At.Sub(phases[0].At)}
+	for i := 0; i < len(phases)-1; i++ {
+		args = append(args, string(phases[i].Phase), phases[i+1].At.Sub(phases[i].At))
+	}
+	s.logger.Info("Instance is ready", args...)
+}
+
+// OnInstancePodRemoved removes the instance whose pod is gone from the repository.
+func (s *InstanceSyncer) OnInstancePodRemoved(ctx context.Context, instanceID string) {
+	existing, err := s.instanceRepo.FindByID(ctx, instanceID)
+	if err != nil {
+		return
+	}
+	// Stopped and queued instances keep their record without a pod
+	if !existing.Status.HasPod() {
+		return
+	}
+	s.removeInstance(ctx, existing)
+}
+
+// removeInstance removes an instance whose pod is gone, along with any resources bundled with the pod
+// that were not garbage collected.
+// Instances running as a workload are kept as pending while the workload recreates the pod.
+func (s *InstanceSyncer) removeInstance(ctx context.Context, instance *model.Instance) {
+	exists, err := s.k8sClient.InstanceWorkloadExists(ctx, instance)
+	if err != nil {
+		s.logger.Error("Failed to get instance workload", "id", instance.InstanceID, "error", err)
+		return
+	}
+	if exists {
+		if instance.Status == model.InstanceStatusPending && instance.PodIP == "" && instance.StatusReason == "" {
+			return
+		}
+		s.logger.Info("Instance pod is gone; waiting for the workload to recreate it", "id", instance.InstanceID, "workload", instance.Workload)
+		pending := *instance
+		pending.Status = model.InstanceStatusPending
+		pending.StatusReason = ""
+		pending.StatusMessage = ""
+		pending.StartupPhases = nil
+		pending.PodIP = ""
+		if err := s.instanceRepo.UpdateStatus(ctx, &pending, instance.Status); err != nil {
+			s.logger.Error("Failed to update instance", "id", instance.InstanceID, "error", err)
+		}
+		return
+	}
+
+	if err := s.k8sClient.DeleteInstanceResources(ctx, instance); err != nil {
+		s.logger.Error("Failed to delete instance resources", "id", instance.InstanceID, "error", err)
+	}
+	if err := s.instanceRepo.Delete(ctx, instance.InstanceID); err != nil {
+		s.logger.Error("Failed to delete removed instance", "id", instance.InstanceID, "error", err)
+	}
+}
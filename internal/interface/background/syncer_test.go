@@ -0,0 +1,57 @@
+package background
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/memory"
+)
+
+// fakePodLister has no instance pods.
+type fakePodLister struct {
+	repository.KubernetesClient
+}
+
+func (fakePodLister) ListInstancePods(ctx context.Context) ([]*model.Instance, error) {
+	return nil, nil
+}
+
+func (fakePodLister) InstanceWorkloadExists(ctx context.Context, instance *model.Instance) (bool, error) {
+	return false, nil
+}
+
+func (fakePodLister) DeleteInstanceResources(ctx context.Context, instance *model.Instance) error {
+	return nil
+}
+
+func TestInstanceSyncer_KeepsStoppedInstances(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInstanceRepository()
+	startedAt := time.Now().Add(-time.Hour)
+	for _, inst := range []*model.Instance{
+		{InstanceID: "stopped", Status: model.InstanceStatusStopped, StartedAt: startedAt},
+		{InstanceID: "gone", PodName: "hakoniwa-gone", Status: model.InstanceStatusRunning, StartedAt: startedAt},
+	} {
+		if err := repo.Save(ctx, inst); err != nil {
+			t.Fatalf("Failed to save instance: %v", err)
+		}
+	}
+	syncer := NewInstanceSyncer(repo, fakePodLister{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := syncer.sync(ctx); err != nil {
+		t.Fatalf("Failed to sync instances: %v", err)
+	}
+	syncer.OnInstancePodRemoved(ctx, "stopped")
+
+	if _, err := repo.FindByID(ctx, "stopped"); err != nil {
+		t.Errorf("Expected the stopped instance to be kept: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, "gone"); err == nil {
+		t.Errorf("Expected the instance whose pod is gone to be removed")
+	}
+}
@@ -0,0 +1,44 @@
+package background
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/usecase"
+)
+
+// WarmPoolWorker refills the warm pools as their pods are claimed, and replaces failed and outdated pool pods.
+type WarmPoolWorker struct {
+	usecase usecase.WarmPoolManagement
+	logger  *slog.Logger
+}
+
+func NewWarmPoolWorker(
+	usecase usecase.WarmPoolManagement,
+	logger *slog.Logger,
+) *WarmPoolWorker {
+	return &WarmPoolWorker{
+		usecase: usecase,
+		logger:  logger,
+	}
+}
+
+func (w *WarmPoolWorker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Starting warm pool worker", "interval", interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Stopping warm pool worker")
+			return
+		case <-ticker.C:
+			if err := w.usecase.ReconcileWarmPools(ctx); err != nil {
+				w.logger.Error("Failed to reconcile warm pools", "error", err)
+			}
+		}
+	}
+}
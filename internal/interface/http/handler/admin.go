@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aplulu/hakoniwa/internal/api/hakoniwa"
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/interface/http/middleware"
+)
+
+// isAdmin returns true if the current user may use the admin API.
+func isAdmin(ctx context.Context) (bool, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return false, errors.New("unauthorized")
+	}
+	return config.IsAdmin(user.ID), nil
+}
+
+// ReloadInstanceTypes implements reloadInstanceTypes operation.
+// POST /admin/instance-types/reload
+func (h *APIHandler) ReloadInstanceTypes(ctx context.Context) (hakoniwa.ReloadInstanceTypesRes, error) {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return &hakoniwa.ReloadInstanceTypesForbidden{}, nil
+	}
+
+	changes, err := h.instanceTypeUsecase.ReloadInstanceTypes(ctx)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidPodTemplate) {
+			return &hakoniwa.Error{Message: err.Error()}, nil
+		}
+		// The pod template could not be read
+		return nil, err
+	}
+
+	return &hakoniwa.InstanceTypeReloadResult{
+		Added:   changes.Added,
+		Removed: changes.Removed,
+		Changed: changes.Changed,
+	}, nil
+}
+
+// ValidateInstanceTypes implements validateInstanceTypes operation.
+// GET /admin/instance-types/validation
+func (h *APIHandler) ValidateInstanceTypes(ctx context.Context) (hakoniwa.ValidateInstanceTypesRes, error) {
+	admin, err := isAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !admin {
+		return &hakoniwa.ValidateInstanceTypesForbidden{}, nil
+	}
+
+	results, err := h.instanceTypeUsecase.ValidateInstanceTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(hakoniwa.ValidateInstanceTypesOKApplicationJSON, 0, len(results))
+	for _, result := range results {
+		problems := make([]hakoniwa.TemplateProblem, 0, len(result.Problems))
+		for _, p := range result.Problems {
+			problems = append(problems, hakoniwa.TemplateProblem{
+				Check:   hakoniwa.TemplateProblemCheck(p.Check),
+				Message: p.Message,
+			})
+		}
+		res = append(res, hakoniwa.InstanceTypeValidation{
+			Type:     result.InstanceType,
+			Problems: problems,
+		})
+	}
+	return &res, nil
+}
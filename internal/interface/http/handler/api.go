@@ -2,24 +2,31 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aplulu/hakoniwa/internal/api/hakoniwa"
 	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
 	"github.com/aplulu/hakoniwa/internal/interface/http/middleware"
 	"github.com/aplulu/hakoniwa/internal/usecase"
 )
 
 type APIHandler struct {
-	authUsecase     usecase.Auth
-	instanceUsecase usecase.InstanceManagement
+	authUsecase         usecase.Auth
+	instanceUsecase     usecase.InstanceManagement
+	volumeUsecase       usecase.VolumeManagement
+	instanceTypeUsecase usecase.InstanceTypeManagement
 }
 
-func NewAPIHandler(auth usecase.Auth, instance usecase.InstanceManagement) *APIHandler {
+func NewAPIHandler(auth usecase.Auth, instance usecase.InstanceManagement, volume usecase.VolumeManagement, instanceType usecase.InstanceTypeManagement) *APIHandler {
 	return &APIHandler{
-		authUsecase:     auth,
-		instanceUsecase: instance,
+		authUsecase:         auth,
+		instanceUsecase:     instance,
+		volumeUsecase:       volume,
+		instanceTypeUsecase: instanceType,
 	}
 }
 
@@ -119,13 +126,7 @@ func (h *APIHandler) ListInstances(ctx context.Context) ([]hakoniwa.Instance, er
 
 	res := make([]hakoniwa.Instance, 0, len(instances))
 	for _, inst := range instances {
-		res = append(res, hakoniwa.Instance{
-			ID:     inst.InstanceID,
-			Name:   inst.DisplayName,
-			Type:   inst.Type,
-			Status: hakoniwa.InstanceStatus(inst.Status),
-			PodIP:  hakoniwa.NewOptString(inst.PodIP),
-		})
+		res = append(res, *toAPIInstance(inst))
 	}
 
 	return res, nil
@@ -139,27 +140,30 @@ func (h *APIHandler) CreateInstance(ctx context.Context, req *hakoniwa.CreateIns
 		return nil, errors.New("unauthorized")
 	}
 
-	inst, err := h.instanceUsecase.CreateInstance(ctx, user.ID, req.Type)
+	params := make(map[string]any, len(req.Parameters.Value))
+	for name, raw := range req.Parameters.Value {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return &hakoniwa.CreateInstanceBadRequest{Message: fmt.Sprintf("invalid parameter %s", name)}, nil
+		}
+		params[name] = v
+	}
+
+	inst, err := h.instanceUsecase.CreateInstance(ctx, user, req.Type, params)
 	if err != nil {
 		// Check for specific errors
-		if err.Error() == "max pod count reached" || err.Error() == "max instances per user reached" || err.Error() == "max instances for this type reached" {
-			return &hakoniwa.CreateInstanceServiceUnavailable{}, nil
+		if errors.Is(err, model.ErrMaxInstancesReached) || errors.Is(err, model.ErrBudgetExceeded) {
+			return &hakoniwa.CreateInstanceServiceUnavailable{Message: err.Error()}, nil
 		}
 		// Assuming invalid type returns 400?
-		if err.Error() == fmt.Sprintf("invalid instance type: %s", req.Type) {
-			return &hakoniwa.CreateInstanceBadRequest{}, nil
+		if err.Error() == fmt.Sprintf("invalid instance type: %s", req.Type) || errors.Is(err, model.ErrInvalidParameter) {
+			return &hakoniwa.CreateInstanceBadRequest{Message: err.Error()}, nil
 		}
 
 		return nil, err
 	}
 
-	return &hakoniwa.Instance{
-		ID:     inst.InstanceID,
-		Name:   inst.DisplayName,
-		Type:   inst.Type,
-		Status: hakoniwa.InstanceStatus(inst.Status),
-		PodIP:  hakoniwa.NewOptString(inst.PodIP),
-	}, nil
+	return toAPIInstance(inst), nil
 }
 
 // DeleteInstance implements deleteInstance operation.
@@ -182,22 +186,188 @@ func (h *APIHandler) DeleteInstance(ctx context.Context, params hakoniwa.DeleteI
 	return &hakoniwa.DeleteInstanceNoContent{}, nil
 }
 
+// ListVolumes implements listVolumes operation.
+// GET /volumes
+func (h *APIHandler) ListVolumes(ctx context.Context) ([]hakoniwa.Volume, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	volumes, err := h.volumeUsecase.ListVolumes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]hakoniwa.Volume, 0, len(volumes))
+	for _, vol := range volumes {
+		res = append(res, hakoniwa.Volume{
+			ID:           vol.Name,
+			InstanceType: vol.InstanceType,
+			Size:         vol.Size,
+			Status:       vol.Status,
+			CreatedAt:    vol.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+// DeleteVolume implements deleteVolume operation.
+// DELETE /volumes/{volumeId}
+func (h *APIHandler) DeleteVolume(ctx context.Context, params hakoniwa.DeleteVolumeParams) (hakoniwa.DeleteVolumeRes, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	err := h.volumeUsecase.DeleteVolume(ctx, user.ID, params.VolumeId)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return &hakoniwa.DeleteVolumeNotFound{}, nil
+		}
+		if errors.Is(err, model.ErrVolumeInUse) {
+			return &hakoniwa.DeleteVolumeConflict{}, nil
+		}
+		return nil, err
+	}
+
+	return &hakoniwa.DeleteVolumeNoContent{}, nil
+}
+
+// GetInstance implements getInstance operation.
+// GET /instances/{instanceId}
+func (h *APIHandler) GetInstance(ctx context.Context, params hakoniwa.GetInstanceParams) (hakoniwa.GetInstanceRes, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	inst, err := h.instanceUsecase.GetInstance(ctx, params.InstanceId)
+	if err != nil || inst.UserID != user.ID {
+		return &hakoniwa.GetInstanceNotFound{}, nil
+	}
+
+	return toAPIInstance(inst), nil
+}
+
+// StopInstance implements stopInstance operation.
+// POST /instances/{instanceId}/stop
+func (h *APIHandler) StopInstance(ctx context.Context, params hakoniwa.StopInstanceParams) (hakoniwa.StopInstanceRes, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	inst, err := h.instanceUsecase.StopInstance(ctx, user.ID, params.InstanceId)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return &hakoniwa.StopInstanceNotFound{}, nil
+		}
+		if errors.Is(err, model.ErrInvalidInstanceState) {
+			return &hakoniwa.StopInstanceConflict{}, nil
+		}
+		return nil, err
+	}
+
+	return toAPIInstance(inst), nil
+}
+
+// StartInstance implements startInstance operation.
+// POST /instances/{instanceId}/start
+func (h *APIHandler) StartInstance(ctx context.Context, params hakoniwa.StartInstanceParams) (hakoniwa.StartInstanceRes, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	inst, err := h.instanceUsecase.StartInstance(ctx, user.ID, params.InstanceId)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return &hakoniwa.StartInstanceNotFound{}, nil
+		}
+		if errors.Is(err, model.ErrInvalidInstanceState) {
+			return &hakoniwa.StartInstanceConflict{}, nil
+		}
+		if errors.Is(err, model.ErrMaxInstancesReached) || errors.Is(err, model.ErrBudgetExceeded) {
+			return &hakoniwa.Error{Message: err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	return toAPIInstance(inst), nil
+}
+
+// ExtendInstance implements extendInstance operation.
+// POST /instances/{instanceId}/extend
+func (h *APIHandler) ExtendInstance(ctx context.Context, params hakoniwa.ExtendInstanceParams) (hakoniwa.ExtendInstanceRes, error) {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return nil, errors.New("unauthorized")
+	}
+
+	inst, err := h.instanceUsecase.ExtendInstance(ctx, user.ID, params.InstanceId)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return &hakoniwa.ExtendInstanceNotFound{}, nil
+		}
+		if errors.Is(err, model.ErrInvalidInstanceState) {
+			return &hakoniwa.ExtendInstanceConflict{}, nil
+		}
+		return nil, err
+	}
+
+	return toAPIInstance(inst), nil
+}
+
 // ListInstanceTypes implements listInstanceTypes operation.
 // GET /instance-types
 func (h *APIHandler) ListInstanceTypes(ctx context.Context) ([]hakoniwa.InstanceType, error) {
+	user, _ := middleware.GetUserFromContext(ctx)
+
 	types := config.GetInstanceTypes()
 	res := make([]hakoniwa.InstanceType, 0, len(types))
 	for _, t := range types {
+		if !t.Allows(user) {
+			continue
+		}
 		res = append(res, hakoniwa.InstanceType{
 			ID:          t.ID,
 			Name:        t.DisplayName,
 			Description: hakoniwa.NewOptString(t.Description),
 			LogoURL:     hakoniwa.NewOptString(t.LogoURL),
+			Parameters:  toAPIInstanceParameters(t.Parameters),
 		})
 	}
 	return res, nil
 }
 
+func toAPIInstanceParameters(params []config.InstanceParameter) []hakoniwa.InstanceParameter {
+	res := make([]hakoniwa.InstanceParameter, 0, len(params))
+	for _, p := range params {
+		param := hakoniwa.InstanceParameter{
+			Name:        p.Name,
+			DisplayName: hakoniwa.NewOptString(p.DisplayName),
+			Description: hakoniwa.NewOptString(p.Description),
+			Type:        hakoniwa.InstanceParameterType(p.Type),
+			Enum:        p.Enum,
+		}
+		if p.Default != nil {
+			if raw, err := json.Marshal(p.Default); err == nil {
+				param.Default = raw
+			}
+		}
+		if p.Minimum != nil {
+			param.Minimum = hakoniwa.NewOptString(p.Minimum.String())
+		}
+		if p.Maximum != nil {
+			param.Maximum = hakoniwa.NewOptString(p.Maximum.String())
+		}
+		res = append(res, param)
+	}
+	return res
+}
+
 // GetConfiguration implements getConfiguration operation.
 // GET /configuration
 func (h *APIHandler) GetConfiguration(ctx context.Context) (*hakoniwa.Configuration, error) {
@@ -210,9 +380,57 @@ func (h *APIHandler) GetConfiguration(ctx context.Context) (*hakoniwa.Configurat
 		AuthMethods:       config.AuthMethodsList(),
 		OidcName:          hakoniwa.NewOptString(config.OIDCName()),
 		AuthAutoLogin:     config.AuthAutoLogin(),
+		InstanceRouting:   hakoniwa.ConfigurationInstanceRouting(config.InstanceRouting()),
 	}, nil
 }
 
+func toAPIInstance(inst *model.Instance) *hakoniwa.Instance {
+	res := &hakoniwa.Instance{
+		ID:     inst.InstanceID,
+		Name:   inst.DisplayName,
+		Type:   inst.Type,
+		Status: hakoniwa.InstanceStatus(inst.Status),
+		PodIP:  hakoniwa.NewOptString(inst.PodIP),
+	}
+	now := time.Now()
+	if inst.ExpiresAt.After(now) {
+		res.ExpiresAt = hakoniwa.NewOptDateTime(inst.ExpiresAt)
+	}
+	if inst.ExtendedUntil.After(now) {
+		res.ExtendedUntil = hakoniwa.NewOptDateTime(inst.ExtendedUntil)
+	}
+	if len(inst.Parameters) > 0 {
+		params := make(hakoniwa.InstanceParameters, len(inst.Parameters))
+		for name, v := range inst.Parameters {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			params[name] = raw
+		}
+		res.Parameters = hakoniwa.NewOptInstanceParameters(params)
+	}
+	if inst.Status == model.InstanceStatusQueued {
+		res.QueuePosition = hakoniwa.NewOptInt(inst.QueuePosition)
+	}
+	if inst.StatusReason != "" {
+		res.StatusReason = hakoniwa.NewOptString(inst.StatusReason)
+	}
+	if inst.StatusMessage != "" {
+		res.StatusMessage = hakoniwa.NewOptString(inst.StatusMessage)
+	}
+	if phase := inst.StartupPhase(); phase != "" {
+		res.StartupPhase = hakoniwa.NewOptStartupPhase(hakoniwa.StartupPhase(phase))
+		for _, p := range inst.StartupPhases {
+			res.StartupPhases = append(res.StartupPhases, hakoniwa.StartupPhaseTime{Phase: hakoniwa.StartupPhase(p.Phase), At: p.At})
+		}
+	}
+	if inst.ReapReason != "" {
+		res.ReapReason = hakoniwa.NewOptInstanceReapReason(hakoniwa.InstanceReapReason(inst.ReapReason))
+	}
+	return res
+}
+
 // Logout implements logout operation.
 
 // POST /auth/logout
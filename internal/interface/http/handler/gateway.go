@@ -3,6 +3,8 @@ package handler
 import (
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +14,18 @@ import (
 	"github.com/aplulu/hakoniwa/internal/usecase"
 )
 
+const (
+	// instanceSessionPath is where an instance host exchanges a hand-off token for its session cookie.
+	instanceSessionPath = "/_hakoniwa/session"
+	// instanceOpenPathPrefix is where the dashboard host hands the session over to an instance host.
+	instanceOpenPathPrefix = "/_hakoniwa/open/"
+	// portPathPrefix is where the forwarded ports of an instance are served, followed by the port.
+	portPathPrefix = "/_hakoniwa/port/"
+
+	// instanceHandOffExpiration is how long the token in the hand-off URL is valid.
+	instanceHandOffExpiration = time.Minute
+)
+
 type GatewayHandler struct {
 	authUsecase     usecase.Auth
 	instanceUsecase usecase.InstanceManagement
@@ -42,6 +56,12 @@ func NewGatewayHandler(
 func (h *GatewayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 
+	// Instance hosts of subdomain routing only serve their instance
+	if instanceID, port := config.InstanceIDFromHost(r.Host); instanceID != "" {
+		h.serveInstanceHost(w, r, instanceID, port)
+		return
+	}
+
 	// 1. API Handling
 	if strings.HasPrefix(path, "/_hakoniwa/api") {
 		// Wrap with CookieSetter/Clearer
@@ -78,6 +98,27 @@ func (h *GatewayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if instanceID, ok := strings.CutPrefix(path, instanceOpenPathPrefix); ok && config.InstanceRouting() == config.InstanceRoutingSubdomain {
+		h.openInstanceHost(w, r, instanceID)
+		return
+	}
+
+	// Forwarded ports and the waiting page polls of the instance selected by the cookie
+	if _, _, isPortPath := cutPortPath(path, ""); (isPortPath || path == instanceWaitPath) && config.InstanceRouting() == config.InstanceRoutingCookie {
+		user, ok := middleware.GetUserFromContext(r.Context())
+		if !ok {
+			h.redirectToDashboard(w, r)
+			return
+		}
+		instance := h.cookieInstance(r, user)
+		if instance == nil {
+			h.redirectToDashboard(w, r)
+			return
+		}
+		h.serveCookieInstance(w, r, instance)
+		return
+	}
+
 	// 2. Static Assets Handling (always allow access to assets)
 	if strings.HasPrefix(path, "/_hakoniwa/") {
 		// Serve static files (assets)
@@ -92,6 +133,12 @@ func (h *GatewayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Path routing serves the instances under /w/{instance-id}/ next to the dashboard
+	if instanceID := config.InstanceIDFromPath(path); instanceID != "" {
+		h.serveInstancePath(w, r, user, instanceID)
+		return
+	}
+
 	// 4. Instance Routing Logic (Cookie-based)
 	
 	// Explicit Dashboard Access -> Clear Instance Cookie
@@ -110,31 +157,241 @@ func (h *GatewayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check for Active Instance Cookie
+	if instance := h.cookieInstance(r, user); instance != nil {
+		h.serveCookieInstance(w, r, instance)
+		return
+	}
+
+	// 5. Dashboard (Default)
+	h.redirectToDashboard(w, r)
+}
+
+// cookieInstance returns the instance of the user selected by the instance cookie with cookie routing,
+// or nil if there is none.
+func (h *GatewayHandler) cookieInstance(r *http.Request, user *model.User) *model.Instance {
 	cookie, err := r.Cookie("hakoniwa_instance_id")
-	if err == nil && cookie.Value != "" {
-		instanceID := cookie.Value
-		instance, err := h.instanceUsecase.GetInstance(r.Context(), instanceID)
+	if err != nil || cookie.Value == "" || config.InstanceRouting() != config.InstanceRoutingCookie {
+		return nil
+	}
+	instanceID := cookie.Value
+	instance, err := h.instanceUsecase.GetInstance(r.Context(), instanceID)
+	if err != nil {
+		h.logger.Error("Failed to get instance from cookie", "id", instanceID, "error", err)
+		return nil
+	}
+	if instance == nil || instance.UserID != user.ID {
+		return nil
+	}
+	return instance
+}
+
+// serveCookieInstance serves a request of the instance selected by the cookie on the dashboard host.
+func (h *GatewayHandler) serveCookieInstance(w http.ResponseWriter, r *http.Request, instance *model.Instance) {
+	if h.waitForInstance(w, r, instance, "", "/_hakoniwa/") {
+		return
+	}
+	if port, prefix, ok := cutPortPath(r.URL.Path, ""); ok {
+		h.proxyToInstancePort(w, r, instance, port, prefix)
+		return
+	}
+	h.proxyToInstance(w, r, instance, "")
+}
+
+// proxyToInstance proxies the request to the pod of the running instance, removing the path prefix if it is not empty.
+func (h *GatewayHandler) proxyToInstance(w http.ResponseWriter, r *http.Request, instance *model.Instance, stripPrefix string) {
+	it, hasType := config.GetInstanceType(instance.Type)
+	port := instance.TargetPort
+	if port == "" {
+		// Instances created before the port was recorded
+		port = "3000"
+		if hasType && it.TargetPort != "" {
+			port = it.TargetPort
+		}
+	}
+	h.proxyHandler.Proxy(instance.InstanceID, ProxyTarget{
+		URL:         "http://" + instance.PodIP + ":" + port,
+		StripPrefix: stripPrefix,
+		H2C:         hasType && it.H2C,
+	}, w, r)
+}
+
+// cutPortPath returns the port of a path under the forwarded ports path after the base prefix, and the prefix of
+// that port without a trailing slash.
+func cutPortPath(path, basePrefix string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(path, basePrefix+portPathPrefix)
+	if !ok {
+		return 0, "", false
+	}
+	value, _, _ := strings.Cut(rest, "/")
+	port := config.ParsePort(value)
+	if port == 0 || strconv.Itoa(port) != value {
+		return 0, "", false
+	}
+	return port, basePrefix + portPathPrefix + value, true
+}
+
+// proxyToInstancePort proxies the request to a forwarded port of the pod of the running instance, removing the path
+// prefix if it is not empty. Only the ports the current instance type forwards are accessible.
+func (h *GatewayHandler) proxyToInstancePort(w http.ResponseWriter, r *http.Request, instance *model.Instance, port int, stripPrefix string) {
+	if stripPrefix != "" && r.URL.Path == stripPrefix {
+		// Relative URLs of the app resolve against the trailing slash
+		target := stripPrefix + "/"
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+	it, ok := config.GetInstanceType(instance.Type)
+	if !ok || !it.ForwardsPort(port) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Apps on forwarded ports must not see the session either
+	removeCookie(r, "hakoniwa_session")
+	h.proxyHandler.Proxy(instance.InstanceID, ProxyTarget{
+		URL:         "http://" + instance.PodIP + ":" + strconv.Itoa(port),
+		StripPrefix: stripPrefix,
+	}, w, r)
+}
+
+// serveInstancePath serves a request under the path prefix of an instance with path routing.
+// The prefix is passed on to instances started with it as their base URL, and stripped for the others.
+func (h *GatewayHandler) serveInstancePath(w http.ResponseWriter, r *http.Request, user *model.User, instanceID string) {
+	prefix := config.InstancePathPrefix(instanceID)
+	if r.URL.Path == prefix {
+		// Relative URLs of the instance resolve against the trailing slash
+		target := prefix + "/"
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+
+	instance, err := h.instanceUsecase.GetInstance(r.Context(), instanceID)
+	if err != nil || instance.UserID != user.ID {
+		h.redirectToDashboard(w, r)
+		return
+	}
+	if h.waitForInstance(w, r, instance, prefix, "/_hakoniwa/") {
+		return
+	}
+
+	if port, portPrefix, ok := cutPortPath(r.URL.Path, prefix); ok {
+		h.proxyToInstancePort(w, r, instance, port, portPrefix)
+		return
+	}
+
+	// Instances share the origin of the dashboard, so they must not see its session
+	removeCookie(r, "hakoniwa_session")
+	if instance.BaseURL == prefix+"/" {
+		h.proxyToInstance(w, r, instance, "")
+		return
+	}
+	h.proxyToInstance(w, r, instance, prefix)
+}
+
+// openInstanceHost hands the session of the dashboard over to the host of the instance with a short-lived token
+// that is only valid for that host. The port query selects the host of a forwarded port of the instance.
+func (h *GatewayHandler) openInstanceHost(w http.ResponseWriter, r *http.Request, instanceID string) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		h.redirectToDashboard(w, r)
+		return
+	}
+	instance, err := h.instanceUsecase.GetInstance(r.Context(), instanceID)
+	if err != nil || instance.UserID != user.ID {
+		h.redirectToDashboard(w, r)
+		return
+	}
+	token, err := h.authUsecase.CreateInstanceToken(r.Context(), user, instance.InstanceID, instanceHandOffExpiration)
+	if err != nil {
+		h.logger.Error("Failed to create instance token", "id", instance.InstanceID, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	port := config.ParsePort(r.URL.Query().Get("port"))
+	http.Redirect(w, r, config.InstanceURL(instance.InstanceID, port, instanceSessionPath+"?token="+url.QueryEscape(token)), http.StatusFound)
+}
+
+// serveInstanceHost serves a request on the host of an instance with subdomain routing.
+// The host has its own session cookie, so the instances of a user don't share a cookie and can be used side by side.
+// A non-zero port serves a forwarded port of the instance on its own host.
+func (h *GatewayHandler) serveInstanceHost(w http.ResponseWriter, r *http.Request, instanceID string, port int) {
+	if r.URL.Path == instanceSessionPath {
+		user, err := h.authUsecase.VerifyInstanceToken(r.Context(), r.URL.Query().Get("token"), instanceID)
 		if err != nil {
-			h.logger.Error("Failed to get instance from cookie", "id", instanceID, "error", err)
-			// Fallthrough to dashboard
-		} else if instance != nil && instance.UserID == user.ID {
-			if instance.Status == model.InstanceStatusRunning && instance.PodIP != "" {
-				// Get Port
-				it, ok := config.GetInstanceType(instance.Type)
-				port := "3000"
-				if ok && it.TargetPort != "" {
-					port = it.TargetPort
-				}
-				// Proxy to Pod
-				targetURL := "http://" + instance.PodIP + ":" + port
-				h.proxyHandler.Proxy(instance.InstanceID, targetURL, w, r)
-				return
-			}
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
 		}
+		token, err := h.authUsecase.CreateInstanceToken(r.Context(), user, instanceID, config.SessionExpiration())
+		if err != nil {
+			h.logger.Error("Failed to create instance token", "id", instanceID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		// Host-only, so it isn't sent to the other instances
+		http.SetCookie(w, &http.Cookie{
+			Name:     "hakoniwa_session",
+			Value:    token,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   strings.HasPrefix(config.DashboardURL(), "https://"),
+			SameSite: http.SameSiteLaxMode,
+			Expires:  time.Now().Add(config.SessionExpiration()),
+		})
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
 	}
 
-	// 5. Dashboard (Default)
-	h.redirectToDashboard(w, r)
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		// Signing in again through the dashboard comes back here if the dashboard session is still valid
+		target := config.DashboardURL() + instanceOpenPathPrefix + url.PathEscape(instanceID)
+		if port != 0 {
+			target += "?port=" + strconv.Itoa(port)
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+	instance, err := h.instanceUsecase.GetInstance(r.Context(), instanceID)
+	if err != nil || instance.UserID != user.ID {
+		http.Redirect(w, r, config.DashboardURL()+"/_hakoniwa/", http.StatusFound)
+		return
+	}
+	if h.waitForInstance(w, r, instance, "", config.DashboardURL()+"/_hakoniwa/") {
+		return
+	}
+
+	if port != 0 {
+		h.proxyToInstancePort(w, r, instance, port, "")
+		return
+	}
+	if port, prefix, ok := cutPortPath(r.URL.Path, ""); ok {
+		h.proxyToInstancePort(w, r, instance, port, prefix)
+		return
+	}
+
+	// The session of the instance host is Hakoniwa's, not the instance's
+	removeCookie(r, "hakoniwa_session")
+	h.proxyToInstance(w, r, instance, "")
+}
+
+// removeCookie removes the cookie from the request.
+func removeCookie(r *http.Request, name string) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, c := range cookies {
+		if c.Name != name {
+			r.AddCookie(c)
+		}
+	}
 }
 
 func (h *GatewayHandler) redirectToDashboard(w http.ResponseWriter, r *http.Request) {
@@ -1,15 +1,24 @@
 package handler_test
 
 import (
+	"context"
+	"encoding/json"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
 	"github.com/aplulu/hakoniwa/internal/interface/http/handler"
+	"github.com/aplulu/hakoniwa/internal/interface/http/middleware"
+	"github.com/aplulu/hakoniwa/internal/usecase"
 )
 
 func TestGatewayHandler_RedirectToDashboard_CacheControl(t *testing.T) {
@@ -73,3 +82,316 @@ func TestGatewayHandler_RedirectToDashboard_CacheControl(t *testing.T) {
 		t.Errorf("Expected Expires 0, got %q", expires)
 	}
 }
+
+func TestGatewayHandler_SubdomainRouting(t *testing.T) {
+	t.Cleanup(func() {
+		if err := config.LoadConf(); err != nil {
+			t.Errorf("Failed to restore config: %v", err)
+		}
+	})
+	t.Setenv("INSTANCE_ROUTING", "subdomain")
+	t.Setenv("INSTANCE_DOMAIN", "ws.example.com")
+	t.Setenv("DASHBOARD_URL", "https://example.com")
+	if err := config.LoadConf(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	auth, err := usecase.NewAuthInteractor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := middleware.NewAuthMiddleware(auth).Handle(handler.NewGatewayHandler(auth, nil, nil, nil, t.TempDir(), logger))
+
+	serve := func(target string, cookie *http.Cookie) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	// Without a session of the instance host, the dashboard signs the user in to it
+	dashboardToken, user, err := auth.LoginAnonymous(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, cookie := range map[string]*http.Cookie{
+		"no session":        nil,
+		"dashboard session": {Name: "hakoniwa_session", Value: dashboardToken},
+	} {
+		resp := serve("http://abc.ws.example.com/lab", cookie)
+		if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "https://example.com/_hakoniwa/open/abc" {
+			t.Errorf("%s: got %d %q, want a redirect to the dashboard", name, resp.StatusCode, resp.Header.Get("Location"))
+		}
+	}
+
+	handOff, err := auth.CreateInstanceToken(context.Background(), user, "abc", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp := serve("http://def.ws.example.com/_hakoniwa/session?token="+url.QueryEscape(handOff), nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected the token of another instance to be rejected, got %d", resp.StatusCode)
+	}
+
+	resp := serve("http://abc.ws.example.com/_hakoniwa/session?token="+url.QueryEscape(handOff), nil)
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/" {
+		t.Fatalf("Expected a redirect to the instance, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	var session *http.Cookie
+	for _, c := range resp.Cookies() {
+		if c.Name == "hakoniwa_session" {
+			session = c
+		}
+	}
+	if session == nil || session.Domain != "" || !session.Secure || !session.HttpOnly {
+		t.Fatalf("Expected a secure host-only session cookie, got %+v", session)
+	}
+	if got, err := auth.VerifyInstanceToken(context.Background(), session.Value, "abc"); err != nil || got.ID != user.ID {
+		t.Errorf("Expected the session to sign %s in to the instance, got %v, %v", user.ID, got, err)
+	}
+	if _, _, err := auth.VerifySession(context.Background(), session.Value); err == nil {
+		t.Error("Expected the session of the instance host not to be valid on the dashboard")
+	}
+}
+
+type fakeInstanceUsecase struct {
+	usecase.InstanceManagement
+	instances map[string]*model.Instance
+}
+
+func (f *fakeInstanceUsecase) GetInstance(ctx context.Context, instanceID string) (*model.Instance, error) {
+	instance, ok := f.instances[instanceID]
+	if !ok {
+		return nil, model.ErrNotFound
+	}
+	return instance, nil
+}
+
+func (f *fakeInstanceUsecase) UpdateLastActive(ctx context.Context, instanceID string) error {
+	return nil
+}
+
+func TestGatewayHandler_PathRouting(t *testing.T) {
+	t.Cleanup(func() {
+		if err := config.LoadConf(); err != nil {
+			t.Errorf("Failed to restore config: %v", err)
+		}
+	})
+	t.Setenv("INSTANCE_ROUTING", "path")
+	if err := config.LoadConf(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("hakoniwa_session"); err == nil {
+			t.Error("Expected the session cookie not to be passed to the instance")
+		}
+		w.Header().Set("X-Path", r.URL.Path)
+		w.Header().Set("X-Prefix", r.Header.Get("X-Forwarded-Prefix"))
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	auth, err := usecase.NewAuthInteractor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, user, err := auth.LoginAnonymous(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	running := func(id, baseURL, userID string) *model.Instance {
+		return &model.Instance{InstanceID: id, UserID: userID, Status: model.InstanceStatusRunning,
+			PodIP: upstreamURL.Hostname(), TargetPort: upstreamURL.Port(), BaseURL: baseURL}
+	}
+	instances := &fakeInstanceUsecase{instances: map[string]*model.Instance{
+		"strip":    running("strip", "/", user.ID),
+		"preserve": running("preserve", "/w/preserve/", user.ID),
+		"other":    running("other", "/", "someone-else"),
+	}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := middleware.NewAuthMiddleware(auth).Handle(handler.NewGatewayHandler(auth, instances, nil, handler.NewProxyHandler(instances, logger), t.TempDir(), logger))
+
+	serve := func(target string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.AddCookie(&http.Cookie{Name: "hakoniwa_session", Value: token})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	for target, want := range map[string]struct{ location, path, prefix string }{
+		"/w/strip?folder=1": {location: "/w/strip/?folder=1"},
+		"/w/strip/lab":      {location: "/w/strip/login", path: "/lab", prefix: "/w/strip"},
+		"/w/strip/":         {location: "/w/strip/login", path: "/", prefix: "/w/strip"},
+		"/w/preserve/lab":   {location: "/login", path: "/w/preserve/lab"},
+		"/w/other/lab":      {location: "/_hakoniwa/"},
+		"/w/missing/":       {location: "/_hakoniwa/"},
+	} {
+		resp := serve(target)
+		if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != want.location {
+			t.Errorf("%s: got %d %q, want a redirect to %q", target, resp.StatusCode, resp.Header.Get("Location"), want.location)
+		}
+		if got := resp.Header.Get("X-Path"); got != want.path {
+			t.Errorf("%s: the instance got path %q, want %q", target, got, want.path)
+		}
+		if got := resp.Header.Get("X-Prefix"); got != want.prefix {
+			t.Errorf("%s: the instance got prefix %q, want %q", target, got, want.prefix)
+		}
+	}
+}
+
+func TestGatewayHandler_PortForwarding(t *testing.T) {
+	previous := make(map[string]config.InstanceType)
+	for _, it := range config.GetInstanceTypes() {
+		previous[it.ID] = it
+	}
+	t.Cleanup(func() {
+		config.SetInstanceTypes(previous)
+		if err := config.LoadConf(); err != nil {
+			t.Errorf("Failed to restore config: %v", err)
+		}
+	})
+	t.Setenv("INSTANCE_ROUTING", "path")
+	if err := config.LoadConf(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("hakoniwa_session"); err == nil {
+			t.Error("Expected the session cookie not to be passed to the forwarded port")
+		}
+		w.Header().Set("X-Path", r.URL.Path)
+		w.Header().Set("X-Prefix", r.Header.Get("X-Forwarded-Prefix"))
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+	port := upstreamURL.Port()
+	portNumber := config.ParsePort(port)
+	config.SetInstanceTypes(map[string]config.InstanceType{
+		"dev": {ID: "dev", ForwardedPorts: []config.PortRange{{From: portNumber, To: portNumber}}},
+	})
+
+	auth, err := usecase.NewAuthInteractor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, user, err := auth.LoginAnonymous(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	instances := &fakeInstanceUsecase{instances: map[string]*model.Instance{
+		"dev": {InstanceID: "dev", UserID: user.ID, Type: "dev", Status: model.InstanceStatusRunning,
+			PodIP: upstreamURL.Hostname(), TargetPort: "1", BaseURL: "/"},
+	}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := middleware.NewAuthMiddleware(auth).Handle(handler.NewGatewayHandler(auth, instances, nil, handler.NewProxyHandler(instances, logger), t.TempDir(), logger))
+
+	serve := func(target string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.AddCookie(&http.Cookie{Name: "hakoniwa_session", Value: token})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	prefix := "/w/dev/_hakoniwa/port/" + port
+	resp := serve(prefix + "/src/main.ts")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Path") != "/src/main.ts" || resp.Header.Get("X-Prefix") != prefix {
+		t.Errorf("Expected the forwarded port to get /src/main.ts under %s, got %d %q %q", prefix, resp.StatusCode, resp.Header.Get("X-Path"), resp.Header.Get("X-Prefix"))
+	}
+	if resp := serve(prefix + "?v=1"); resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != prefix+"/?v=1" {
+		t.Errorf("Expected a redirect to the trailing slash, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if resp := serve("/w/dev/_hakoniwa/port/22/"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected a port that is not forwarded to be forbidden, got %d", resp.StatusCode)
+	}
+}
+
+func TestGatewayHandler_WaitingPage(t *testing.T) {
+	t.Cleanup(func() {
+		if err := config.LoadConf(); err != nil {
+			t.Errorf("Failed to restore config: %v", err)
+		}
+	})
+	t.Setenv("INSTANCE_ROUTING", "path")
+	if err := config.LoadConf(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "workspace")
+	}))
+	defer upstream.Close()
+	upstreamURL, _ := url.Parse(upstream.URL)
+
+	auth, err := usecase.NewAuthInteractor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, user, err := auth.LoginAnonymous(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := &model.Instance{InstanceID: "dev", UserID: user.ID, DisplayName: "Dev", Status: model.InstanceStatusStarting,
+		TargetPort: upstreamURL.Port(), BaseURL: "/",
+		StartupPhases: []model.StartupPhaseTime{{Phase: model.StartupPhaseScheduled}, {Phase: model.StartupPhasePullingImage}}}
+	instances := &fakeInstanceUsecase{instances: map[string]*model.Instance{
+		"dev":     instance,
+		"stopped": {InstanceID: "stopped", UserID: user.ID, Status: model.InstanceStatusStopped},
+		"unprovisioned": {InstanceID: "unprovisioned", UserID: user.ID, Status: model.InstanceStatusFailed,
+			StatusReason: model.StatusReasonProvisioningFailed},
+	}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := middleware.NewAuthMiddleware(auth).Handle(handler.NewGatewayHandler(auth, instances, nil, handler.NewProxyHandler(instances, logger), t.TempDir(), logger))
+
+	serve := func(target, accept string) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept", accept)
+		req.AddCookie(&http.Cookie{Name: "hakoniwa_session", Value: token})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Result(), w.Body.String()
+	}
+
+	resp, body := serve("/w/dev/lab", "text/html,application/xhtml+xml")
+	if resp.StatusCode != http.StatusServiceUnavailable || !strings.Contains(body, "Pulling image") || !strings.Contains(body, "/w/dev/_hakoniwa/wait") {
+		t.Errorf("Expected the waiting page, got %d %q", resp.StatusCode, body)
+	}
+	if resp, body := serve("/w/dev/app.js", "*/*"); resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" || strings.Contains(body, "<html") {
+		t.Errorf("Expected other requests to be retried later, got %d %q", resp.StatusCode, body)
+	}
+	if resp, _ := serve("/w/stopped/", "text/html"); resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/_hakoniwa/" {
+		t.Errorf("Expected a stopped instance to redirect to the dashboard, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if resp, _ := serve("/w/unprovisioned/", "text/html"); resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/_hakoniwa/" {
+		t.Errorf("Expected an instance whose pod could not be created to redirect to the dashboard, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	var status struct {
+		State   string `json:"state"`
+		Ready   bool   `json:"ready"`
+		Waiting bool   `json:"waiting"`
+		Text    string `json:"text"`
+	}
+	_, body = serve("/w/dev/_hakoniwa/wait", "*/*")
+	if err := json.Unmarshal([]byte(body), &status); err != nil || status.Ready || !status.Waiting || !strings.Contains(status.Text, "Pulling image") {
+		t.Fatalf("Expected the status of the starting instance, got %q (%v)", body, err)
+	}
+
+	// A held poll returns once the instance is ready, and the page then reloads into the workspace
+	instance.Status = model.InstanceStatusRunning
+	instance.PodIP = upstreamURL.Hostname()
+	instance.StartupPhases = append(instance.StartupPhases, model.StartupPhaseTime{Phase: model.StartupPhaseReady})
+	_, body = serve("/w/dev/_hakoniwa/wait?state="+url.QueryEscape(status.State), "*/*")
+	if err := json.Unmarshal([]byte(body), &status); err != nil || !status.Ready {
+		t.Errorf("Expected the instance to be ready, got %q (%v)", body, err)
+	}
+	if resp, body := serve("/w/dev/lab", "text/html"); resp.StatusCode != http.StatusOK || body != "workspace" {
+		t.Errorf("Expected the workspace, got %d %q", resp.StatusCode, body)
+	}
+}
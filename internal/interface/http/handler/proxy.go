@@ -9,81 +9,236 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
 	"github.com/aplulu/hakoniwa/internal/usecase"
 )
 
+// activityUpdateInterval throttles the LastActiveAt updates of an instance while it is being used.
+const activityUpdateInterval = 10 * time.Second
+
+// ProxyTarget is where the requests of an instance are proxied to.
+type ProxyTarget struct {
+	// URL is the base URL of the pod, e.g. http://10.0.0.1:8888.
+	URL string
+	// StripPrefix is removed from request paths for instances that expect requests at the root path.
+	// It is passed in X-Forwarded-Prefix and added back to the redirects of the instance.
+	StripPrefix string
+	// H2C sends requests other than upgrades to the pod over HTTP/2 without TLS.
+	H2C bool
+}
+
+// ProxyHandler proxies requests to instance pods. The reverse proxy of each port of an instance is built once and reused
+// until the instance's pod goes away or its target changes, and all of them share pooled connections.
 type ProxyHandler struct {
 	instanceUsecase usecase.InstanceManagement
 	logger          *slog.Logger
+	transport       *http.Transport
+	h2cTransport    *http.Transport
+	bufferPool      httputil.BufferPool
+
+	mu sync.Mutex
+	// proxies holds the reverse proxies of each instance by target URL.
+	proxies map[string]map[string]*instanceProxy
+}
+
+type instanceProxy struct {
+	target     ProxyTarget
+	podIP      string
+	proxy      *httputil.ReverseProxy
+	lastUpdate atomic.Int64 // Unix nanoseconds of the last LastActiveAt update
 }
 
 func NewProxyHandler(instanceUsecase usecase.InstanceManagement, logger *slog.Logger) *ProxyHandler {
+	transport := newProxyTransport()
+	// Only unencrypted HTTP/2 is enabled, so plain http:// requests use h2c with prior knowledge
+	h2cTransport := newProxyTransport()
+	h2cTransport.Protocols = new(http.Protocols)
+	h2cTransport.Protocols.SetUnencryptedHTTP2(true)
+
 	return &ProxyHandler{
 		instanceUsecase: instanceUsecase,
 		logger:          logger,
+		transport:       transport,
+		h2cTransport:    h2cTransport,
+		bufferPool:      newBufferPool(),
+		proxies:         make(map[string]map[string]*instanceProxy),
 	}
 }
 
-func (h *ProxyHandler) Proxy(instanceID, targetURL string, w http.ResponseWriter, r *http.Request) {
-	url, err := url.Parse(targetURL)
+// newProxyTransport returns a transport for connecting to pods directly, keeping enough idle connections per pod
+// for browsers loading many assets in parallel.
+func newProxyTransport() *http.Transport {
+	return &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   config.ProxyDialTimeout(),
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          0, // Limited per pod instead
+		MaxIdleConnsPerHost:   config.ProxyMaxIdleConnsPerInstance(),
+		IdleConnTimeout:       config.ProxyIdleConnTimeout(),
+		ResponseHeaderTimeout: config.ProxyResponseHeaderTimeout(),
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// Proxy proxies the request to the instance, reusing its reverse proxy if the target has not changed.
+func (h *ProxyHandler) Proxy(instanceID string, target ProxyTarget, w http.ResponseWriter, r *http.Request) {
+	p, err := h.instanceProxy(instanceID, target)
 	if err != nil {
-		h.logger.Error("Failed to parse proxy target URL", "url", targetURL, "error", err)
+		h.logger.Error("Failed to parse proxy target URL", "url", target.URL, "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	// Activity Tracker Setup
-	var lastUpdate time.Time
-	var mu sync.Mutex
-
-	tracker := func() {
-		mu.Lock()
-		now := time.Now()
-		if now.Sub(lastUpdate) < 10*time.Second {
-			mu.Unlock()
-			return
-		}
-		lastUpdate = now
-		mu.Unlock()
+	wrappedWriter := &ActivityTrackingResponseWriter{
+		ResponseWriter: w,
+		tracker:        func() { h.trackActivity(instanceID, p) },
+	}
+	p.proxy.ServeHTTP(wrappedWriter, r)
+}
 
-		// Update LastActiveAt asynchronously
-		go func() {
-			if err := h.instanceUsecase.UpdateLastActive(context.Background(), instanceID); err != nil {
-				h.logger.Error("Failed to update instance activity", "instance_id", instanceID, "error", err)
-			}
-		}()
+// instanceProxy returns the reverse proxy of the instance for the target URL, replacing it if the target has changed.
+func (h *ProxyHandler) instanceProxy(instanceID string, target ProxyTarget) (*instanceProxy, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if p, ok := h.proxies[instanceID][target.URL]; ok && p.target == target {
+		return p, nil
+	}
+	p, err := h.newInstanceProxy(target)
+	if err != nil {
+		return nil, err
 	}
+	if h.proxies[instanceID] == nil {
+		h.proxies[instanceID] = make(map[string]*instanceProxy)
+	}
+	h.proxies[instanceID][target.URL] = p
+	return p, nil
+}
 
-	// Wrap ResponseWriter
-	wrappedWriter := &ActivityTrackingResponseWriter{
-		ResponseWriter: w,
-		tracker:        tracker,
+func (h *ProxyHandler) newInstanceProxy(target ProxyTarget) (*instanceProxy, error) {
+	targetURL, err := url.Parse(target.URL)
+	if err != nil {
+		return nil, err
 	}
 
-	proxy := httputil.NewSingleHostReverseProxy(url)
+	proxy := httputil.NewSingleHostReverseProxy(targetURL)
+	proxy.BufferPool = h.bufferPool
+	proxy.Transport = h.transport
+	if target.H2C {
+		proxy.Transport = &h2cRoundTripper{h2c: h.h2cTransport, http1: h.transport}
+	}
 
 	originalDirector := proxy.Director
 	proxy.Director = func(req *http.Request) {
 		originalDirector(req)
-		// Webtop/KasmVNC requires Upgrade header for WebSocket
-		if r.Header.Get("Upgrade") != "" {
-			req.Header.Set("Upgrade", r.Header.Get("Upgrade"))
-			req.Header.Set("Connection", r.Header.Get("Connection"))
-		}
 		// Ensure Host header matches target or is handled correctly
-		req.Host = url.Host
+		// (Upgrade headers for WebSockets, e.g. Webtop/KasmVNC, are kept by the reverse proxy)
+		req.Host = targetURL.Host
+		if target.StripPrefix != "" {
+			req.URL.Path = stripPathPrefix(req.URL.Path, target.StripPrefix)
+			if req.URL.RawPath != "" {
+				req.URL.RawPath = stripPathPrefix(req.URL.RawPath, target.StripPrefix)
+			}
+			req.Header.Set("X-Forwarded-Prefix", target.StripPrefix)
+		}
+	}
+	if target.StripPrefix != "" {
+		proxy.ModifyResponse = func(resp *http.Response) error {
+			// Redirects to absolute paths would leave the prefix
+			if location := resp.Header.Get("Location"); strings.HasPrefix(location, "/") && !strings.HasPrefix(location, "//") {
+				resp.Header.Set("Location", target.StripPrefix+location)
+			}
+			return nil
+		}
 	}
 
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
-		h.logger.Error("Proxy error", "error", err, "target", targetURL)
+		h.logger.Error("Proxy error", "error", err, "target", target.URL)
 		// Gateway logic should handle 502/reloads, but here we just return error
 		http.Error(w, "Bad Gateway", http.StatusBadGateway)
 	}
 
-	proxy.ServeHTTP(wrappedWriter, r)
+	return &instanceProxy{target: target, podIP: targetURL.Hostname(), proxy: proxy}, nil
+}
+
+// trackActivity updates LastActiveAt of the instance asynchronously, at most once per activityUpdateInterval.
+func (h *ProxyHandler) trackActivity(instanceID string, p *instanceProxy) {
+	now := time.Now().UnixNano()
+	last := p.lastUpdate.Load()
+	if now-last < int64(activityUpdateInterval) || !p.lastUpdate.CompareAndSwap(last, now) {
+		return
+	}
+	go func() {
+		if err := h.instanceUsecase.UpdateLastActive(context.Background(), instanceID); err != nil {
+			h.logger.Error("Failed to update instance activity", "instance_id", instanceID, "error", err)
+		}
+	}()
+}
+
+// OnInstancePodChanged evicts the reverse proxies of the instance if its pod is no longer running at the same IP.
+func (h *ProxyHandler) OnInstancePodChanged(ctx context.Context, instance *model.Instance) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for targetURL, p := range h.proxies[instance.InstanceID] {
+		if instance.Status != model.InstanceStatusRunning || instance.PodIP != p.podIP {
+			delete(h.proxies[instance.InstanceID], targetURL)
+		}
+	}
+	if len(h.proxies[instance.InstanceID]) == 0 {
+		delete(h.proxies, instance.InstanceID)
+	}
+}
+
+// OnInstancePodRemoved evicts the reverse proxies of the instance whose pod is gone.
+func (h *ProxyHandler) OnInstancePodRemoved(ctx context.Context, instanceID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.proxies, instanceID)
+}
+
+// h2cRoundTripper sends requests over h2c, except for upgrades such as WebSockets, which need HTTP/1.1.
+type h2cRoundTripper struct {
+	h2c   http.RoundTripper
+	http1 http.RoundTripper
+}
+
+func (t *h2cRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Upgrade") != "" {
+		return t.http1.RoundTrip(req)
+	}
+	return t.h2c.RoundTrip(req)
+}
+
+// bufferPool reuses the buffers the reverse proxies copy response bodies with.
+type bufferPool struct {
+	pool sync.Pool
+}
+
+func newBufferPool() *bufferPool {
+	return &bufferPool{pool: sync.Pool{New: func() any { return make([]byte, 32*1024) }}}
+}
+
+func (p *bufferPool) Get() []byte {
+	return p.pool.Get().([]byte)
+}
+
+func (p *bufferPool) Put(b []byte) {
+	p.pool.Put(b)
+}
+
+// stripPathPrefix removes the prefix from the path, keeping it absolute.
+func stripPathPrefix(path, prefix string) string {
+	path = strings.TrimPrefix(path, prefix)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
 }
 
 // --- Activity Tracking Wrappers ---
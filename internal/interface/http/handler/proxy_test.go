@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/usecase"
+)
+
+type nopActivityUsecase struct {
+	usecase.InstanceManagement
+}
+
+func (nopActivityUsecase) UpdateLastActive(ctx context.Context, instanceID string) error {
+	return nil
+}
+
+func newTestProxyHandler() *ProxyHandler {
+	return NewProxyHandler(nopActivityUsecase{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func proxyBody(t *testing.T, h *ProxyHandler, target ProxyTarget, header http.Header) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.Proxy("instance-1", target, w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d from %s", w.Code, target.URL)
+	}
+	return w.Body.String()
+}
+
+func TestProxyHandler_Cache(t *testing.T) {
+	upstreams := make([]*httptest.Server, 2)
+	for i := range upstreams {
+		body := []string{"first", "second"}[i]
+		upstreams[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, body)
+		}))
+		defer upstreams[i].Close()
+	}
+	first := ProxyTarget{URL: upstreams[0].URL}
+	h := newTestProxyHandler()
+
+	if got := proxyBody(t, h, first, nil); got != "first" {
+		t.Fatalf("got %q, want first", got)
+	}
+	cached := h.proxies["instance-1"][first.URL]
+	proxyBody(t, h, first, nil)
+	if h.proxies["instance-1"][first.URL] != cached {
+		t.Error("Expected the proxy of the instance to be reused")
+	}
+
+	u, _ := url.Parse(upstreams[0].URL)
+	h.OnInstancePodChanged(context.Background(), &model.Instance{InstanceID: "instance-1", Status: model.InstanceStatusRunning, PodIP: u.Hostname()})
+	if h.proxies["instance-1"][first.URL] != cached {
+		t.Error("Expected the proxy to be kept while the pod runs at the same IP")
+	}
+	h.OnInstancePodChanged(context.Background(), &model.Instance{InstanceID: "instance-1", Status: model.InstanceStatusRunning, PodIP: "10.0.0.2"})
+	if _, ok := h.proxies["instance-1"]; ok {
+		t.Error("Expected the proxy to be evicted when the pod IP changes")
+	}
+
+	// A new target replaces the proxy of the instance
+	if got := proxyBody(t, h, ProxyTarget{URL: upstreams[1].URL}, nil); got != "second" {
+		t.Errorf("got %q, want second", got)
+	}
+	h.OnInstancePodRemoved(context.Background(), "instance-1")
+	if len(h.proxies) != 0 {
+		t.Error("Expected the proxy to be evicted when the pod is removed")
+	}
+}
+
+func TestProxyHandler_H2C(t *testing.T) {
+	upstream := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	}))
+	upstream.Config.Protocols = new(http.Protocols)
+	upstream.Config.Protocols.SetHTTP1(true)
+	upstream.Config.Protocols.SetUnencryptedHTTP2(true)
+	upstream.Start()
+	defer upstream.Close()
+	h := newTestProxyHandler()
+
+	if got := proxyBody(t, h, ProxyTarget{URL: upstream.URL, H2C: true}, nil); got != "HTTP/2.0" {
+		t.Errorf("got %s, want HTTP/2.0", got)
+	}
+	// WebSockets are only upgraded over HTTP/1.1
+	if got := proxyBody(t, h, ProxyTarget{URL: upstream.URL, H2C: true}, http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}}); got != "HTTP/1.1" {
+		t.Errorf("got %s for an upgrade, want HTTP/1.1", got)
+	}
+	if got := proxyBody(t, h, ProxyTarget{URL: upstream.URL}, nil); got != "HTTP/1.1" {
+		t.Errorf("got %s without h2c, want HTTP/1.1", got)
+	}
+}
+
+// BenchmarkProxy compares building a reverse proxy per request on http.DefaultTransport, as the proxy used to,
+// with the cached proxy of the instance, for the many parallel asset requests of workspaces like code-server.
+func BenchmarkProxy(b *testing.B) {
+	asset := strings.Repeat("x", 64<<10)
+	upstream := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/javascript")
+		io.WriteString(w, asset)
+	}))
+	// New connections to the pod show how well they are reused
+	var conns atomic.Int64
+	upstream.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	upstream.Start()
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	run := func(b *testing.B, serve func(w http.ResponseWriter, r *http.Request)) {
+		conns.Store(0)
+		b.SetBytes(int64(len(asset)))
+		b.ReportAllocs()
+		// Browsers load assets over several connections at once
+		b.SetParallelism(8)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				w := httptest.NewRecorder()
+				serve(w, httptest.NewRequest(http.MethodGet, "/static/out/vs/workbench.js", nil))
+				if w.Code != http.StatusOK {
+					b.Fatalf("got status %d", w.Code)
+				}
+			}
+		})
+		b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+	}
+
+	b.Run("per-request", func(b *testing.B) {
+		run(b, func(w http.ResponseWriter, r *http.Request) {
+			httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+		})
+	})
+	b.Run("cached", func(b *testing.B) {
+		h := newTestProxyHandler()
+		h.transport.MaxIdleConnsPerHost = 64
+		run(b, func(w http.ResponseWriter, r *http.Request) {
+			h.Proxy("instance-1", ProxyTarget{URL: upstream.URL}, w, r)
+		})
+	})
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+const (
+	// instanceWaitPath is where the waiting page polls the status of the instance, relative to the instance.
+	instanceWaitPath = "/_hakoniwa/wait"
+	// instanceWaitTimeout is how long a poll is held while the status of the instance doesn't change.
+	instanceWaitTimeout = 25 * time.Second
+	// instanceWaitInterval is how often a held poll checks the status of the instance.
+	instanceWaitInterval = time.Second
+)
+
+//go:embed html/waiting.html
+var waitingHTML string
+
+var waitingTemplate = template.Must(template.New("waiting").Parse(waitingHTML))
+
+// startupPhaseTexts describe the startup phases on the waiting page, like the dashboard does.
+var startupPhaseTexts = map[model.StartupPhase]string{
+	model.StartupPhaseScheduled:           "Scheduled",
+	model.StartupPhasePullingImage:        "Pulling image",
+	model.StartupPhaseInitializing:        "Initializing",
+	model.StartupPhaseWaitingForReadiness: "Waiting for readiness",
+	model.StartupPhaseReady:               "Ready",
+}
+
+// instanceWaitStatus is the status of an instance shown on the waiting page.
+type instanceWaitStatus struct {
+	// State changes whenever the rest does, so polls are held until it differs from the one the page shows.
+	State string `json:"state"`
+	// Ready is true once requests can be proxied to the instance.
+	Ready bool `json:"ready"`
+	// Waiting is false if the instance won't become ready without the user, e.g. it was stopped.
+	Waiting bool   `json:"waiting"`
+	Failed  bool   `json:"failed"`
+	Text    string `json:"text"`
+	Message string `json:"message,omitempty"`
+}
+
+// instanceReady returns true if requests can be proxied to the pod of the instance.
+func instanceReady(instance *model.Instance) bool {
+	return instance.Status == model.InstanceStatusRunning && instance.PodIP != ""
+}
+
+func newInstanceWaitStatus(instance *model.Instance) instanceWaitStatus {
+	status := instanceWaitStatus{Ready: instanceReady(instance), Waiting: true, Message: instance.StatusMessage}
+	switch {
+	case status.Ready:
+		status.Text = "Ready"
+	case instance.Status == model.InstanceStatusQueued:
+		status.Text = fmt.Sprintf("Waiting for cluster capacity (#%d in queue)", instance.QueuePosition)
+	case instance.Status == model.InstanceStatusPending:
+		status.Text = "Waiting to be scheduled"
+	case instance.Status == model.InstanceStatusStarting || instance.Status == model.InstanceStatusRunning:
+		status.Text = "Starting"
+		if text, ok := startupPhaseTexts[instance.StartupPhase()]; ok {
+			status.Text = fmt.Sprintf("Starting: %s (%d/%d)", text, len(instance.StartupPhases), len(startupPhaseTexts))
+		}
+	case instance.Status == model.InstanceStatusFailed:
+		// A failed instance recovers if the cause is fixed, unless its pod could not be created at all
+		status.Failed = true
+		status.Waiting = instance.StatusReason != model.StatusReasonProvisioningFailed
+		status.Text = "Failed to start"
+		if instance.StatusReason != "" {
+			status.Text += ": " + instance.StatusReason
+		}
+	default:
+		status.Waiting = false
+		status.Text = "Not running"
+	}
+	status.State = strings.Join([]string{string(instance.Status), instance.PodIP, status.Text, status.Message}, "\n")
+	return status
+}
+
+// waitForInstance handles the requests of an instance of the user that can't be proxied to it: the polls of the
+// waiting page, and requests while the instance is not running yet, e.g. while it starts or its pod restarts.
+// It returns false if the request is to be proxied to the running instance.
+func (h *GatewayHandler) waitForInstance(w http.ResponseWriter, r *http.Request, instance *model.Instance, basePrefix, dashboardURL string) bool {
+	if r.URL.Path == basePrefix+instanceWaitPath {
+		h.serveInstanceWait(w, r, instance)
+		return true
+	}
+	if instanceReady(instance) {
+		return false
+	}
+
+	status := newInstanceWaitStatus(instance)
+	w.Header().Set("Cache-Control", "no-store")
+	if !status.Waiting {
+		http.Redirect(w, r, dashboardURL, http.StatusFound)
+		return true
+	}
+	w.Header().Set("Retry-After", "5")
+	// Only page loads get the waiting page; other requests of the instance's pages are retried by the browser or app
+	if r.Method != http.MethodGet || !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		return true
+	}
+
+	name := instance.DisplayName
+	if name == "" {
+		name = instance.Type
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if err := waitingTemplate.Execute(w, map[string]any{
+		"Name":         name,
+		"Status":       status,
+		"WaitURL":      basePrefix + instanceWaitPath,
+		"DashboardURL": dashboardURL,
+	}); err != nil {
+		h.logger.Error("Failed to render waiting page", "id", instance.InstanceID, "error", err)
+	}
+	return true
+}
+
+// serveInstanceWait responds with the status of the instance once it differs from the state query, or after
+// instanceWaitTimeout, so the waiting page follows the startup without polling rapidly.
+func (h *GatewayHandler) serveInstanceWait(w http.ResponseWriter, r *http.Request, instance *model.Instance) {
+	since := r.URL.Query().Get("state")
+	timeout := time.NewTimer(instanceWaitTimeout)
+	defer timeout.Stop()
+	ticker := time.NewTicker(instanceWaitInterval)
+	defer ticker.Stop()
+
+	status := newInstanceWaitStatus(instance)
+wait:
+	for status.State == since && status.Waiting && !status.Ready {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timeout.C:
+			break wait
+		case <-ticker.C:
+		}
+
+		current, err := h.instanceUsecase.GetInstance(r.Context(), instance.InstanceID)
+		if err != nil {
+			// Like the other routes of the instance, the page goes back to the dashboard
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		status = newInstanceWaitStatus(current)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		h.logger.Error("Failed to write instance status", "id", instance.InstanceID, "error", err)
+	}
+}
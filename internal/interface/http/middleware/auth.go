@@ -35,6 +35,16 @@ func (m *AuthMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
+		// Instance hosts only accept the tokens issued for their instance, which are not renewed
+		if instanceID, _ := config.InstanceIDFromHost(r.Host); instanceID != "" {
+			user, err := m.authUsecase.VerifyInstanceToken(r.Context(), cookie.Value, instanceID)
+			if err == nil {
+				r = r.WithContext(context.WithValue(r.Context(), UserContextKey, user))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		user, newToken, err := m.authUsecase.VerifySession(r.Context(), cookie.Value)
 		if err != nil {
 			// Invalid session, proceed without user
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -12,6 +13,10 @@ import (
 
 	"github.com/aplulu/hakoniwa/internal/api/hakoniwa"
 	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/database"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/file"
 	"github.com/aplulu/hakoniwa/internal/infrastructure/kubernetes"
 	"github.com/aplulu/hakoniwa/internal/infrastructure/memory"
 	"github.com/aplulu/hakoniwa/internal/interface/background"
@@ -23,11 +28,15 @@ import (
 var (
 	server        *http.Server
 	cleanerCancel context.CancelFunc
+	db            *sql.DB
 )
 
 func StartServer(log *slog.Logger, staticDir string) error {
 	// Infrastructure
-	instanceRepository := memory.NewInstanceRepository()
+	instanceRepository, err := newInstanceRepository(log)
+	if err != nil {
+		return fmt.Errorf("server.StartServer: failed to create instance repository: %w", err)
+	}
 	k8sClient, err := kubernetes.NewClient(log)
 	if err != nil {
 		log.Error("failed to create k8s client", "error", err)
@@ -38,20 +47,55 @@ func StartServer(log *slog.Logger, staticDir string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	cleanerCancel = cancel
 
+	if err := k8sClient.Start(ctx); err != nil {
+		return fmt.Errorf("server.StartServer: failed to start pod cache: %w", err)
+	}
+
+	reapPolicy, err := background.NewReapPolicyChain(config.ReapPolicies())
+	if err != nil {
+		return fmt.Errorf("server.StartServer: failed to create reap policies: %w", err)
+	}
 	cleaner := background.NewInactivityCleaner(
 		instanceRepository,
 		k8sClient,
 		log,
-		config.InstanceInactivityTimeout(),
+		reapPolicy,
+		config.InstanceInactivityAction(),
+		config.ReapWarningPeriod(),
+		config.ReapDryRun(),
 	)
-	go cleaner.Start(ctx)
 
 	syncer := background.NewInstanceSyncer(
 		instanceRepository,
 		k8sClient,
 		log,
 	)
-	go syncer.Start(ctx, 5*time.Second)
+
+	quotaUsecase := usecase.NewQuotaInteractor(instanceRepository)
+	instanceUsecase := usecase.NewInstanceInteractor(instanceRepository, k8sClient, quotaUsecase)
+	queueWorker := background.NewQueueWorker(instanceUsecase, log)
+	warmPoolWorker := background.NewWarmPoolWorker(usecase.NewWarmPoolInteractor(k8sClient), log)
+
+	runWorkers := func(ctx context.Context) {
+		go cleaner.Start(ctx)
+		go syncer.Start(ctx, 30*time.Second)
+		// Runs even with the queue disabled, so instances queued before it was disabled are still provisioned.
+		go queueWorker.Start(ctx, 5*time.Second)
+		go warmPoolWorker.Start(ctx, 10*time.Second)
+	}
+	if config.LeaderElectionEnabled() {
+		if config.DatabaseDriver() != "postgres" {
+			log.Warn("Leader election is enabled without a shared database; replicas will not share instance state", "driver", config.DatabaseDriver())
+		}
+		// Only the leader reaps and reconciles; every replica still serves and proxies requests.
+		go func() {
+			if err := k8sClient.RunLeaderElection(ctx, config.LeaderElectionLeaseName(), runWorkers); err != nil {
+				log.Error("Leader election failed", "error", err)
+			}
+		}()
+	} else {
+		runWorkers(ctx)
+	}
 
 	// Usecase
 	authUsecase, err := usecase.NewAuthInteractor()
@@ -72,16 +116,42 @@ func StartServer(log *slog.Logger, staticDir string) error {
 		// So safe to return error.
 		return fmt.Errorf("server.StartServer: failed to initialize auth usecase: %w", err)
 	}
-	instanceUsecase := usecase.NewInstanceInteractor(instanceRepository, k8sClient)
+	volumeUsecase := usecase.NewVolumeInteractor(instanceRepository, k8sClient)
+
+	// Instance types are reloaded on every replica when the pod template changes.
+	var templateSource repository.TemplateSource
+	if config.PodTemplateConfigMap() != "" {
+		templateSource = k8sClient.NewConfigMapTemplateSource(config.PodTemplateConfigMap(), config.PodTemplateConfigMapKey())
+	} else {
+		templateSource = file.NewTemplateSource(config.PodTemplatePath(), config.PodTemplateReloadInterval())
+	}
+	instanceTypeUsecase := usecase.NewInstanceTypeInteractor(templateSource, k8sClient, log)
+	if config.PodTemplateConfigMap() != "" {
+		// LoadConf only read POD_TEMPLATE_PATH or the embedded default
+		if _, err := instanceTypeUsecase.ReloadInstanceTypes(ctx); err != nil {
+			return fmt.Errorf("server.StartServer: failed to load instance types: %w", err)
+		}
+	}
+	// Problems are only logged so a template the dry-run rejects doesn't take the whole server down.
+	go func() {
+		if _, err := instanceTypeUsecase.ValidateInstanceTypes(ctx); err != nil {
+			log.Error("Failed to validate instance types", "error", err)
+		}
+	}()
+	go background.NewInstanceTypeWatcher(templateSource, instanceTypeUsecase, log).Start(ctx)
 
 	// Handlers
-	apiHandler := handler.NewAPIHandler(authUsecase, instanceUsecase)
+	apiHandler := handler.NewAPIHandler(authUsecase, instanceUsecase, volumeUsecase, instanceTypeUsecase)
 	apiServer, err := hakoniwa.NewServer(apiHandler)
 	if err != nil {
 		return fmt.Errorf("server.StartServer: failed to create api server: %w", err)
 	}
 
 	proxyHandler := handler.NewProxyHandler(instanceUsecase, log)
+	// Every replica proxies requests, so every replica evicts the proxies of pods that went away
+	if err := k8sClient.WatchInstancePods(ctx, proxyHandler); err != nil {
+		return fmt.Errorf("server.StartServer: failed to watch instance pods: %w", err)
+	}
 
 	gatewayHandler := handler.NewGatewayHandler(
 		authUsecase,
@@ -127,6 +197,33 @@ func StartServer(log *slog.Logger, staticDir string) error {
 	return nil
 }
 
+// ValidateTemplates validates the pod templates of all instance types against the cluster without starting the server.
+func ValidateTemplates(ctx context.Context, log *slog.Logger) ([]*model.InstanceTypeValidation, error) {
+	k8sClient, err := kubernetes.NewClient(log)
+	if err != nil {
+		return nil, fmt.Errorf("server.ValidateTemplates: failed to create k8s client: %w", err)
+	}
+
+	var templateSource repository.TemplateSource
+	if config.PodTemplateConfigMap() != "" {
+		templateSource = k8sClient.NewConfigMapTemplateSource(config.PodTemplateConfigMap(), config.PodTemplateConfigMapKey())
+	} else {
+		templateSource = file.NewTemplateSource(config.PodTemplatePath(), config.PodTemplateReloadInterval())
+	}
+	instanceTypeUsecase := usecase.NewInstanceTypeInteractor(templateSource, k8sClient, log)
+	if config.PodTemplateConfigMap() != "" {
+		if _, err := instanceTypeUsecase.ReloadInstanceTypes(ctx); err != nil {
+			return nil, fmt.Errorf("server.ValidateTemplates: failed to load instance types: %w", err)
+		}
+	}
+
+	results, err := instanceTypeUsecase.ValidateInstanceTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server.ValidateTemplates: %w", err)
+	}
+	return results, nil
+}
+
 func StopServer(ctx context.Context) error {
 	if cleanerCancel != nil {
 		cleanerCancel()
@@ -134,5 +231,25 @@ func StopServer(ctx context.Context) error {
 	if err := server.Shutdown(ctx); err != nil {
 		return fmt.Errorf("server.StopServer: failed to stop server: %w", err)
 	}
+	if db != nil {
+		if err := db.Close(); err != nil {
+			return fmt.Errorf("server.StopServer: failed to close database: %w", err)
+		}
+	}
 	return nil
 }
+
+func newInstanceRepository(log *slog.Logger) (repository.InstanceRepository, error) {
+	switch config.DatabaseDriver() {
+	case "", "memory":
+		return memory.NewInstanceRepository(), nil
+	default:
+		var err error
+		db, err = database.Open(context.Background(), config.DatabaseDriver(), config.DatabaseDSN())
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Using database instance repository", "driver", config.DatabaseDriver())
+		return database.NewInstanceRepository(db, config.DatabaseDriver()), nil
+	}
+}
@@ -22,6 +22,10 @@ type Auth interface {
 	LoginAnonymous(ctx context.Context) (string, *model.User, error) // returns token, user, error
 	LoginOIDC(ctx context.Context, redirectURI string) (string, error)
 	CallbackOIDC(ctx context.Context, code string, state string, redirectURI string) (string, *model.User, error)
+	// CreateInstanceToken issues a token signing the user in to the host of the instance only.
+	CreateInstanceToken(ctx context.Context, user *model.User, instanceID string, expiration time.Duration) (string, error)
+	// VerifyInstanceToken verifies a token issued by CreateInstanceToken for the instance.
+	VerifyInstanceToken(ctx context.Context, token string, instanceID string) (*model.User, error)
 }
 
 type AuthInteractor struct {
@@ -31,8 +35,9 @@ type AuthInteractor struct {
 }
 
 type CustomClaims struct {
-	UserID   string `json:"user_id"`
-	UserType string `json:"user_type"`
+	UserID     string   `json:"user_id"`
+	UserType   string   `json:"user_type"`
+	UserGroups []string `json:"user_groups,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -78,10 +83,12 @@ func (a *AuthInteractor) VerifySession(ctx context.Context, tokenString string)
 		return nil, "", fmt.Errorf("failed to parse token: %w", err)
 	}
 
-	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
+	// Tokens issued for an instance host are not sessions of the dashboard
+	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid && len(claims.Audience) == 0 {
 		user := &model.User{
-			ID:   claims.UserID,
-			Type: model.UserType(claims.UserType),
+			ID:     claims.UserID,
+			Type:   model.UserType(claims.UserType),
+			Groups: claims.UserGroups,
 		}
 
 		// Sliding Session Check
@@ -169,16 +176,15 @@ func (a *AuthInteractor) CallbackOIDC(ctx context.Context, code string, state st
 		return "", nil, fmt.Errorf("failed to verify ID Token: %w", err)
 	}
 
-	var claims struct {
-		Subject string `json:"sub"`
-	}
+	var claims map[string]any
 	if err := idToken.Claims(&claims); err != nil {
 		return "", nil, fmt.Errorf("failed to parse claims: %w", err)
 	}
 
 	user := &model.User{
-		ID:   "oidc:" + claims.Subject,
-		Type: model.UserTypeOIDC,
+		ID:     "oidc:" + idToken.Subject,
+		Type:   model.UserTypeOIDC,
+		Groups: groupsFromClaim(claims[config.OIDCGroupsClaim()]),
 	}
 
 	token, err := a.createToken(user)
@@ -189,15 +195,53 @@ func (a *AuthInteractor) CallbackOIDC(ctx context.Context, code string, state st
 	return token, user, nil
 }
 
+func (a *AuthInteractor) CreateInstanceToken(ctx context.Context, user *model.User, instanceID string, expiration time.Duration) (string, error) {
+	return a.signToken(user, expiration, instanceAudience(instanceID))
+}
+
+func (a *AuthInteractor) VerifyInstanceToken(ctx context.Context, tokenString string, instanceID string) (*model.User, error) {
+	if tokenString == "" {
+		return nil, model.ErrUnauthorized
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.JWTSecret()), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(instanceAudience(instanceID)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	claims, ok := token.Claims.(*CustomClaims)
+	if !ok || !token.Valid {
+		return nil, model.ErrUnauthorized
+	}
+	return &model.User{
+		ID:     claims.UserID,
+		Type:   model.UserType(claims.UserType),
+		Groups: claims.UserGroups,
+	}, nil
+}
+
+// instanceAudience is the audience of the tokens issued for an instance host.
+func instanceAudience(instanceID string) string {
+	return "instance:" + instanceID
+}
+
 func (a *AuthInteractor) createToken(user *model.User) (string, error) {
+	return a.signToken(user, config.SessionExpiration())
+}
+
+func (a *AuthInteractor) signToken(user *model.User, expiration time.Duration, audience ...string) (string, error) {
 	claims := CustomClaims{
 		user.ID,
 		string(user.Type),
+		user.Groups,
 		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.SessionExpiration())),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "hakoniwa",
+			Audience:  audience,
 		},
 	}
 
@@ -209,6 +253,23 @@ func (a *AuthInteractor) createToken(user *model.User) (string, error) {
 	return ss, nil
 }
 
+// groupsFromClaim reads the groups claim, which providers send as a list or a single string.
+func groupsFromClaim(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, item := range v {
+			if g, ok := item.(string); ok {
+				groups = append(groups, g)
+			}
+		}
+		return groups
+	}
+	return nil
+}
+
 func generateRandomState() (string, error) {
 	b := make([]byte, 32)
 	_, err := rand.Read(b)
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,30 +17,35 @@ import (
 type InstanceManagement interface {
 	ListInstances(ctx context.Context, userID string) ([]*model.Instance, error)
 	GetInstance(ctx context.Context, instanceID string) (*model.Instance, error)
-	CreateInstance(ctx context.Context, userID, instanceType string) (*model.Instance, error)
+	CreateInstance(ctx context.Context, user *model.User, instanceType string, params map[string]any) (*model.Instance, error)
 	DeleteInstance(ctx context.Context, userID, instanceID string) error
+	StopInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error)
+	StartInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error)
+	ExtendInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error)
 	UpdateLastActive(ctx context.Context, instanceID string) error
+	// ProvisionQueuedInstances creates the pods of queued instances in queue order while the cluster has capacity.
+	ProvisionQueuedInstances(ctx context.Context) error
 }
 
 type InstanceInteractor struct {
 	instanceRepo repository.InstanceRepository
 	k8sClient    repository.KubernetesClient
+	quota        Quota
 }
 
-func NewInstanceInteractor(instanceRepo repository.InstanceRepository, k8sClient repository.KubernetesClient) InstanceManagement {
+func NewInstanceInteractor(instanceRepo repository.InstanceRepository, k8sClient repository.KubernetesClient, quota Quota) InstanceManagement {
 	return &InstanceInteractor{
 		instanceRepo: instanceRepo,
 		k8sClient:    k8sClient,
+		quota:        quota,
 	}
 }
 
 func (i *InstanceInteractor) UpdateLastActive(ctx context.Context, instanceID string) error {
-	instance, err := i.instanceRepo.FindByID(ctx, instanceID)
-	if err != nil {
+	if _, err := i.instanceRepo.FindByID(ctx, instanceID); err != nil {
 		return nil // Ignore if not found
 	}
-	instance.LastActiveAt = time.Now()
-	return i.instanceRepo.Save(ctx, instance)
+	return i.instanceRepo.UpdateLastActiveAt(ctx, instanceID, time.Now())
 }
 
 func (i *InstanceInteractor) ListInstances(ctx context.Context, userID string) ([]*model.Instance, error) {
@@ -46,6 +53,9 @@ func (i *InstanceInteractor) ListInstances(ctx context.Context, userID string) (
 	if err != nil {
 		return nil, err
 	}
+	if err := i.setQueuePositions(ctx, instances); err != nil {
+		return nil, err
+	}
 	return instances, nil
 }
 
@@ -55,30 +65,47 @@ func (i *InstanceInteractor) GetInstance(ctx context.Context, instanceID string)
 		return nil, err
 	}
 
+	// Stopped and queued instances have no pod to sync with
+	if instance.Status == model.InstanceStatusQueued {
+		if err := i.setQueuePositions(ctx, []*model.Instance{instance}); err != nil {
+			return nil, err
+		}
+		return instance, nil
+	}
+	if instance.Status == model.InstanceStatusStopped {
+		return instance, nil
+	}
+
 	// Sync with K8s
-	ip, err := i.k8sClient.GetPodIP(ctx, instance.PodName)
+	podStatus, err := i.k8sClient.GetPodStatus(ctx, instance.PodName)
 	if err != nil {
 		return nil, err
 	}
+	// A missing or terminating pod is left to the syncer, which removes the instance
+	if podStatus == nil || podStatus.Status == model.InstanceStatusTerminating {
+		return instance, nil
+	}
 
-	newStatus := instance.Status
-	if ip != "" {
-		newStatus = model.InstanceStatusRunning
-	} else {
-		// If it was running but now no IP, maybe it crashed or is terminating?
-		// Or just not ready?
-		if instance.Status == model.InstanceStatusRunning {
-			// Keep running if transient?
-			// For now simple logic: No IP = Pending/Terminating
-			newStatus = model.InstanceStatusPending
-		}
+	// GetInstance is on the proxy hot path; only write when the pod state actually changed.
+	// Activity is recorded separately by UpdateLastActive.
+	if instance.Status == podStatus.Status && instance.PodIP == podStatus.PodIP &&
+		instance.StatusReason == podStatus.Reason && instance.StatusMessage == podStatus.Message &&
+		model.EqualStartupPhases(instance.StartupPhases, podStatus.Phases) {
+		return instance, nil
 	}
 
-	instance.Status = newStatus
-	instance.PodIP = ip
-	instance.LastActiveAt = time.Now()
+	previous := instance.Status
+	instance.Status = podStatus.Status
+	instance.PodIP = podStatus.PodIP
+	instance.StatusReason = podStatus.Reason
+	instance.StatusMessage = podStatus.Message
+	instance.StartupPhases = podStatus.Phases
 
-	if err := i.instanceRepo.Save(ctx, instance); err != nil {
+	if err := i.instanceRepo.UpdateStatus(ctx, instance, previous); err != nil {
+		if errors.Is(err, model.ErrInvalidInstanceState) {
+			// Stopped or restarted meanwhile; its current record is newer than the pod status read
+			return i.instanceRepo.FindByID(ctx, instanceID)
+		}
 		return nil, err
 	}
 
@@ -95,71 +122,318 @@ func (i *InstanceInteractor) DeleteInstance(ctx context.Context, userID, instanc
 		return fmt.Errorf("instance not found") // Obfuscate
 	}
 
-	if err := i.k8sClient.DeletePod(ctx, instance.PodName); err != nil {
+	if !instance.HasPod() {
+		// Queued, or its pod could not be created; there is no pod to delete
+		return i.instanceRepo.Delete(ctx, instanceID)
+	}
+
+	if err := i.k8sClient.DeleteInstanceWorkload(ctx, instance); err != nil {
 		// Log warning but continue to delete from repo?
 		// Ideally we want consistency. If delete pod fails, maybe keep it?
 		// But if pod is gone, we should delete from repo.
-		// k8sClient.DeletePod should return nil if not found.
+		// k8sClient.DeleteInstanceWorkload should return nil if not found.
 		return err
 	}
 
 	return i.instanceRepo.Delete(ctx, instanceID)
 }
 
-func (i *InstanceInteractor) CreateInstance(ctx context.Context, userID, instanceTypeID string) (*model.Instance, error) {
-	// Check Global Limit
-	globalCount, err := i.instanceRepo.Count(ctx)
+// StopInstance deletes the instance pod but keeps the instance record (and its volumes) so it can be started again.
+func (i *InstanceInteractor) StopInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error) {
+	instance, err := i.findUserInstance(ctx, userID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance.Status == model.InstanceStatusStopped {
+		return nil, model.ErrInvalidInstanceState
+	}
+
+	// Mark as stopped before deleting the pod so the syncer keeps the record when the pod disappears.
+	stopped := *instance
+	stopped.Status = model.InstanceStatusStopped
+	stopped.PodIP = ""
+	stopped.StatusReason = ""
+	stopped.StatusMessage = ""
+	stopped.StartupPhases = nil
+	if err := i.instanceRepo.UpdateStatus(ctx, &stopped, instance.Status); err != nil {
+		return nil, err
+	}
+	if !instance.HasPod() {
+		// Queued, or its pod could not be created; there is no pod to delete
+		return &stopped, nil
+	}
+
+	if err := i.k8sClient.DeleteInstanceWorkload(ctx, &stopped); err != nil {
+		// Restore the previous state so the pod is not orphaned
+		_ = i.instanceRepo.UpdateStatus(ctx, instance, model.InstanceStatusStopped)
+		return nil, err
+	}
+
+	return &stopped, nil
+}
+
+// StartInstance recreates the pod of a stopped instance.
+func (i *InstanceInteractor) StartInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error) {
+	instance, err := i.findUserInstance(ctx, userID, instanceID)
 	if err != nil {
 		return nil, err
 	}
-	if globalCount >= config.MaxPodCount() {
-		return nil, fmt.Errorf("max pod count reached")
+	if instance.Status != model.InstanceStatusStopped {
+		return nil, model.ErrInvalidInstanceState
 	}
 
-	// Check User Limit
-	userCount, err := i.instanceRepo.CountByUser(ctx, userID)
+	// Recreate the pod from the template the instance was created with, even if the instance types were reloaded since.
+	content := instance.Template
+	if len(content) == 0 {
+		// Instances created before the template was recorded
+		it, ok := config.GetInstanceType(instance.Type)
+		if !ok {
+			return nil, fmt.Errorf("invalid instance type: %s", instance.Type)
+		}
+		resolved, err := it.ResolveParameters(instance.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		content, err = it.Render(resolved)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requests, err := config.PodResourceRequests(content)
 	if err != nil {
 		return nil, err
 	}
-	if userCount >= config.MaxInstancesPerUser() {
-		return nil, fmt.Errorf("max instances per user reached")
+	started := *instance
+	started.Resources = requests
+	started.Status = model.InstanceStatusPending
+	started.Template = content
+	started.PodIP = ""
+	started.StatusReason = ""
+	started.StatusMessage = ""
+	started.StartupPhases = nil
+	// The instance may be routed differently since it was stopped
+	if it, ok := config.GetInstanceType(instance.Type); ok {
+		started.BaseURL = config.InstanceBaseURL(instance.InstanceID, it)
+	}
+	started.LastActiveAt = time.Now()
+	started.StartedAt = started.LastActiveAt
+	started.ReapReason = ""
+	started.ExpiresAt = time.Time{}
+	started.ExtendedUntil = time.Time{}
+
+	if err := i.quota.ReserveStart(ctx, &started); err != nil {
+		return nil, err
+	}
+	if started.Status == model.InstanceStatusQueued {
+		if err := i.setQueuePositions(ctx, []*model.Instance{&started}); err != nil {
+			return nil, err
+		}
+		return &started, nil
+	}
+
+	if err := i.createInstancePod(ctx, &started, content); err != nil {
+		// Release the reservation by stopping it again, unless something else took it over meanwhile
+		if restoreErr := i.instanceRepo.UpdateStatus(ctx, instance, model.InstanceStatusPending); restoreErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("failed to stop instance %s again: %w", instance.InstanceID, restoreErr))
+		}
+		return nil, err
+	}
+
+	if err := i.instanceRepo.UpdateStatus(ctx, &started, model.InstanceStatusPending); err != nil {
+		return nil, err
 	}
 
-	// Check Type Limit
-	typeCount, err := i.instanceRepo.CountByUserAndType(ctx, userID, instanceTypeID)
+	return &started, nil
+}
+
+// ExtendInstance extends the lease of an instance so the reaping policies leave it alone for a while.
+func (i *InstanceInteractor) ExtendInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error) {
+	instance, err := i.findUserInstance(ctx, userID, instanceID)
 	if err != nil {
 		return nil, err
 	}
-	if typeCount >= config.MaxInstancesPerUserPerType() {
-		return nil, fmt.Errorf("max instances for this type reached")
+	if !instance.Status.HasPod() {
+		return nil, model.ErrInvalidInstanceState
+	}
+
+	now := time.Now()
+	extended := *instance
+	extended.LastActiveAt = now
+	extended.ExpiresAt = time.Time{}
+	extended.ExtendedUntil = now.Add(config.ReapLeaseExtension())
+	if err := i.instanceRepo.UpdateLease(ctx, instanceID, extended.ExpiresAt, extended.ExtendedUntil); err != nil {
+		return nil, err
+	}
+	if err := i.instanceRepo.UpdateLastActiveAt(ctx, instanceID, extended.LastActiveAt); err != nil {
+		return nil, err
+	}
+
+	return &extended, nil
+}
+
+// findUserInstance returns the instance if it exists and belongs to the user.
+func (i *InstanceInteractor) findUserInstance(ctx context.Context, userID, instanceID string) (*model.Instance, error) {
+	instance, err := i.instanceRepo.FindByID(ctx, instanceID)
+	if err != nil {
+		return nil, model.ErrNotFound
+	}
+	if instance.UserID != userID {
+		return nil, model.ErrNotFound // Obfuscate
 	}
+	return instance, nil
+}
+
+func (i *InstanceInteractor) CreateInstance(ctx context.Context, user *model.User, instanceTypeID string, params map[string]any) (*model.Instance, error) {
+	userID := user.ID
 
 	// Get Template
 	it, ok := config.GetInstanceType(instanceTypeID)
-	if !ok {
+	if !ok || !it.Allows(user) {
+		// Types the user may not use are reported as unknown so they stay hidden
 		return nil, fmt.Errorf("invalid instance type: %s", instanceTypeID)
 	}
 
+	resolved, err := it.ResolveParameters(params)
+	if err != nil {
+		return nil, err
+	}
+	content, err := it.Render(resolved)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := config.PodResourceRequests(content)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate ID
 	instanceID := uuid.New().String()
 
+	now := time.Now()
 	instance := &model.Instance{
-		InstanceID:   instanceID,
-		UserID:       userID,
-		Type:         instanceTypeID,
-		DisplayName:  it.DisplayName,
-		Status:       model.InstanceStatusPending,
-		LastActiveAt: time.Now(),
+		InstanceID:    instanceID,
+		UserID:        userID,
+		Type:          instanceTypeID,
+		DisplayName:   it.DisplayName,
+		Status:        model.InstanceStatusPending,
+		LastActiveAt:  now,
+		CreatedAt:     now,
+		StartedAt:     now,
+		Parameters:    resolved,
+		TargetPort:    it.TargetPort,
+		Template:      content,
+		Workload:      it.Workload,
+		Resources:     requests,
+		QueuePriority: it.QueuePriority,
+		BaseURL:       config.InstanceBaseURL(instanceID, it),
 		// PodName set by k8s client
 	}
 
-	if err := i.k8sClient.CreateInstancePod(ctx, instance, it.Content); err != nil {
+	// The instance is saved as pending before its pod exists, holding its share of the quotas.
+	if err := i.quota.ReserveCreate(ctx, user, it, instance); err != nil {
+		return nil, err
+	}
+	if instance.Status == model.InstanceStatusQueued {
+		if err := i.setQueuePositions(ctx, []*model.Instance{instance}); err != nil {
+			return nil, err
+		}
+		return instance, nil
+	}
+
+	if err := i.createInstancePod(ctx, instance, content); err != nil {
+		// Release the reservation
+		_ = i.instanceRepo.Delete(ctx, instanceID)
 		return nil, err
 	}
 
-	if err := i.instanceRepo.Save(ctx, instance); err != nil {
+	if err := i.instanceRepo.UpdateStatus(ctx, instance, model.InstanceStatusPending); err != nil {
 		return nil, err
 	}
 
 	return instance, nil
-}
\ No newline at end of file
+}
+
+func (i *InstanceInteractor) ProvisionQueuedInstances(ctx context.Context) error {
+	queued, err := i.instanceRepo.ListQueued(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, instance := range queued {
+		now := time.Now()
+		provisioned := *instance
+		provisioned.Status = model.InstanceStatusPending
+		provisioned.LastActiveAt = now
+		provisioned.StartedAt = now
+
+		if err := i.quota.ReserveQueued(ctx, &provisioned); err != nil {
+			if isCapacityExhausted(err) {
+				// Keep the queue order; the rest waits until the head fits
+				return nil
+			}
+			if errors.Is(err, model.ErrMaxInstancesReached) {
+				// The type is at its cap, which doesn't hold up instances of other types
+				continue
+			}
+			return errors.Join(append(errs, err)...)
+		}
+
+		if err := i.createInstancePod(ctx, &provisioned, provisioned.Template); err != nil {
+			if errors.Is(err, model.ErrInvalidInstanceState) {
+				// The previous pod is still being deleted; back to its place in the queue to retry the head first
+				errs = append(errs, fmt.Errorf("failed to provision queued instance %s: %w", instance.InstanceID, err))
+				if err := i.instanceRepo.Requeue(ctx, instance); err != nil {
+					errs = append(errs, err)
+				}
+				return errors.Join(errs...)
+			}
+			// Retrying won't create the pod, e.g. the API server rejects it, so it must not hold up the queue
+			provisioned.Status = model.InstanceStatusFailed
+			provisioned.StatusReason = model.StatusReasonProvisioningFailed
+			provisioned.StatusMessage = err.Error()
+			errs = append(errs, fmt.Errorf("failed to provision queued instance %s: %w", instance.InstanceID, err))
+		}
+		if err := i.instanceRepo.UpdateStatus(ctx, &provisioned, model.InstanceStatusPending); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// createInstancePod claims a pod from the warm pool of the instance's type, or creates one if none matches.
+func (i *InstanceInteractor) createInstancePod(ctx context.Context, instance *model.Instance, content []byte) error {
+	it, ok := config.GetInstanceType(instance.Type)
+	if ok && it.WarmPoolSize > 0 && (instance.Workload == "" || instance.Workload == model.WorkloadKindPod) {
+		claimed, err := i.k8sClient.ClaimWarmPoolPod(ctx, instance, content)
+		if err != nil {
+			return err
+		}
+		if claimed {
+			return nil
+		}
+	}
+	return i.k8sClient.CreateInstancePod(ctx, instance, content)
+}
+
+// setQueuePositions sets the queue position of the queued instances.
+func (i *InstanceInteractor) setQueuePositions(ctx context.Context, instances []*model.Instance) error {
+	if !slices.ContainsFunc(instances, func(inst *model.Instance) bool { return inst.Status == model.InstanceStatusQueued }) {
+		return nil
+	}
+	queued, err := i.instanceRepo.ListQueued(ctx)
+	if err != nil {
+		return err
+	}
+	positions := make(map[string]int, len(queued))
+	for n, inst := range queued {
+		positions[inst.InstanceID] = n + 1
+	}
+	for _, inst := range instances {
+		if inst.Status == model.InstanceStatusQueued {
+			inst.QueuePosition = positions[inst.InstanceID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,515 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/database"
+	"github.com/aplulu/hakoniwa/internal/infrastructure/memory"
+)
+
+const testPodTemplate = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: webtop
+  annotations:
+    hakoniwa.aplulu.me/port: "3000"
+spec:
+  containers:
+  - name: desktop
+    image: lscr.io/linuxserver/webtop:latest
+    resources:
+      requests:
+        cpu: 500m
+        memory: 1Gi
+`
+
+// fakeKubernetesClient creates pods slowly enough for concurrent requests to overlap.
+type fakeKubernetesClient struct {
+	repository.KubernetesClient
+	err error
+	// rejected is an instance whose pod is always rejected.
+	rejected string
+	// deleted are the instances whose pod was deleted.
+	deleted []string
+}
+
+func (f *fakeKubernetesClient) CreateInstancePod(ctx context.Context, instance *model.Instance, template []byte) error {
+	time.Sleep(5 * time.Millisecond)
+	if f.err != nil {
+		return f.err
+	}
+	if instance.InstanceID == f.rejected {
+		return errors.New("pods is forbidden")
+	}
+	instance.PodName = "hakoniwa-" + instance.InstanceID
+	return nil
+}
+
+func (f *fakeKubernetesClient) DeleteInstanceWorkload(ctx context.Context, instance *model.Instance) error {
+	if instance.PodName == "" {
+		// Like the API server
+		return errors.New("resource name may not be empty")
+	}
+	f.deleted = append(f.deleted, instance.InstanceID)
+	return nil
+}
+
+func loadTestConfig(t *testing.T, env map[string]string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pod_template.yaml")
+	if err := os.WriteFile(path, []byte(testPodTemplate), 0o600); err != nil {
+		t.Fatalf("Failed to write pod template: %v", err)
+	}
+	t.Setenv("POD_TEMPLATE_PATH", path)
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	if err := config.LoadConf(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+}
+
+func testRepositories(t *testing.T) map[string]repository.InstanceRepository {
+	t.Helper()
+	db, err := database.Open(context.Background(), "sqlite", filepath.Join(t.TempDir(), "hakoniwa.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return map[string]repository.InstanceRepository{
+		"memory": memory.NewInstanceRepository(),
+		"sqlite": database.NewInstanceRepository(db, "sqlite"),
+	}
+}
+
+// createConcurrently calls CreateInstance n times at once and returns the number of instances created.
+func createConcurrently(t *testing.T, uc InstanceManagement, n int, user func(i int) *model.User) int {
+	t.Helper()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := uc.CreateInstance(context.Background(), user(i), "webtop", nil)
+			if err != nil && !errors.Is(err, model.ErrMaxInstancesReached) && !errors.Is(err, model.ErrBudgetExceeded) {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return created
+}
+
+func TestCreateInstance_ConcurrentRequestsRespectQuotas(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		user func(i int) *model.User
+		want int
+	}{
+		{
+			name: "per type",
+			env:  map[string]string{"MAX_INSTANCES_PER_USER_PER_TYPE": "1"},
+			user: func(int) *model.User { return &model.User{ID: "alice"} },
+			want: 1,
+		},
+		{
+			name: "global",
+			env:  map[string]string{"MAX_POD_COUNT": "3"},
+			user: func(i int) *model.User { return &model.User{ID: fmt.Sprintf("user-%d", i)} },
+			want: 3,
+		},
+		{
+			name: "resource budget",
+			env:  map[string]string{"MAX_TOTAL_CPU": "2"},
+			user: func(i int) *model.User { return &model.User{ID: fmt.Sprintf("user-%d", i)} },
+			want: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loadTestConfig(t, tt.env)
+			for name, repo := range testRepositories(t) {
+				uc := NewInstanceInteractor(repo, &fakeKubernetesClient{}, NewQuotaInteractor(repo))
+				if got := createConcurrently(t, uc, 20, tt.user); got != tt.want {
+					t.Errorf("%s: created %d instances, want %d", name, got, tt.want)
+				}
+				if count, _ := repo.Count(context.Background()); count != tt.want {
+					t.Errorf("%s: repository has %d instances, want %d", name, count, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateInstance_ReleasesReservationOnFailure(t *testing.T) {
+	loadTestConfig(t, nil)
+	for name, repo := range testRepositories(t) {
+		uc := NewInstanceInteractor(repo, &fakeKubernetesClient{err: errors.New("boom")}, NewQuotaInteractor(repo))
+		if _, err := uc.CreateInstance(context.Background(), &model.User{ID: "alice"}, "webtop", nil); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+		if count, _ := repo.Count(context.Background()); count != 0 {
+			t.Errorf("%s: expected the reservation to be released, got %d instances", name, count)
+		}
+	}
+}
+
+func TestCreateInstance_QueuesWhenCapacityIsExhausted(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_POD_COUNT": "2", "INSTANCE_QUEUE_ENABLED": "true"})
+	ctx := context.Background()
+	for name, repo := range testRepositories(t) {
+		uc := NewInstanceInteractor(repo, &fakeKubernetesClient{}, NewQuotaInteractor(repo))
+
+		var instances []*model.Instance
+		for n := 0; n < 4; n++ {
+			inst, err := uc.CreateInstance(ctx, &model.User{ID: fmt.Sprintf("user-%d", n)}, "webtop", nil)
+			if err != nil {
+				t.Fatalf("%s: failed to create instance: %v", name, err)
+			}
+			instances = append(instances, inst)
+		}
+		for n, inst := range instances {
+			wantStatus, wantPosition := model.InstanceStatusPending, 0
+			if n >= 2 {
+				wantStatus, wantPosition = model.InstanceStatusQueued, n-1
+			}
+			if inst.Status != wantStatus || inst.QueuePosition != wantPosition {
+				t.Errorf("%s: instance %d is %s at %d, want %s at %d", name, n, inst.Status, inst.QueuePosition, wantStatus, wantPosition)
+			}
+		}
+
+		// Nothing frees up, so the queue stays as it is
+		if err := uc.ProvisionQueuedInstances(ctx); err != nil {
+			t.Fatalf("%s: failed to provision queued instances: %v", name, err)
+		}
+		if queued, _ := repo.CountQueued(ctx); queued != 2 {
+			t.Errorf("%s: expected 2 queued instances, got %d", name, queued)
+		}
+
+		if err := repo.Delete(ctx, instances[0].InstanceID); err != nil {
+			t.Fatalf("%s: failed to delete instance: %v", name, err)
+		}
+		if err := uc.ProvisionQueuedInstances(ctx); err != nil {
+			t.Fatalf("%s: failed to provision queued instances: %v", name, err)
+		}
+		first, err := repo.FindByID(ctx, instances[2].InstanceID)
+		if err != nil {
+			t.Fatalf("%s: failed to get instance: %v", name, err)
+		}
+		if first.Status == model.InstanceStatusQueued || first.PodName == "" {
+			t.Errorf("%s: expected the head of the queue to be provisioned, got %+v", name, first)
+		}
+		second, err := uc.GetInstance(ctx, instances[3].InstanceID)
+		if err != nil {
+			t.Fatalf("%s: failed to get instance: %v", name, err)
+		}
+		if second.Status != model.InstanceStatusQueued || second.QueuePosition != 1 {
+			t.Errorf("%s: expected the second instance to move up the queue, got %s at %d", name, second.Status, second.QueuePosition)
+		}
+	}
+}
+
+func TestProvisionQueuedInstances_FailedHeadDoesNotBlockQueue(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_POD_COUNT": "2", "INSTANCE_QUEUE_ENABLED": "true"})
+	ctx := context.Background()
+	for name, repo := range testRepositories(t) {
+		k8s := &fakeKubernetesClient{}
+		uc := NewInstanceInteractor(repo, k8s, NewQuotaInteractor(repo))
+
+		var instances []*model.Instance
+		for n := 0; n < 4; n++ {
+			inst, err := uc.CreateInstance(ctx, &model.User{ID: fmt.Sprintf("user-%d", n)}, "webtop", nil)
+			if err != nil {
+				t.Fatalf("%s: failed to create instance: %v", name, err)
+			}
+			instances = append(instances, inst)
+		}
+		for _, inst := range instances[:2] {
+			if err := repo.Delete(ctx, inst.InstanceID); err != nil {
+				t.Fatalf("%s: failed to delete instance: %v", name, err)
+			}
+		}
+
+		k8s.rejected = instances[2].InstanceID
+		if err := uc.ProvisionQueuedInstances(ctx); err == nil {
+			t.Errorf("%s: expected the failure to be reported", name)
+		}
+		head, err := repo.FindByID(ctx, instances[2].InstanceID)
+		if err != nil {
+			t.Fatalf("%s: failed to get instance: %v", name, err)
+		}
+		if head.Status != model.InstanceStatusFailed || head.StatusReason != model.StatusReasonProvisioningFailed || head.StatusMessage == "" {
+			t.Errorf("%s: expected the head of the queue to fail, got %+v", name, head)
+		}
+		second, err := repo.FindByID(ctx, instances[3].InstanceID)
+		if err != nil {
+			t.Fatalf("%s: failed to get instance: %v", name, err)
+		}
+		if second.Status == model.InstanceStatusQueued || second.PodName == "" {
+			t.Errorf("%s: expected the second instance to be provisioned, got %+v", name, second)
+		}
+
+		// The failed instance is not retried
+		if err := uc.ProvisionQueuedInstances(ctx); err != nil {
+			t.Errorf("%s: failed to provision queued instances: %v", name, err)
+		}
+	}
+}
+
+func TestProvisionQueuedInstances_RequeuesHeadWhilePreviousPodIsDeleted(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_POD_COUNT": "1", "INSTANCE_QUEUE_ENABLED": "true"})
+	ctx := context.Background()
+	for name, repo := range testRepositories(t) {
+		k8s := &fakeKubernetesClient{}
+		uc := NewInstanceInteractor(repo, k8s, NewQuotaInteractor(repo))
+
+		var instances []*model.Instance
+		for n := 0; n < 3; n++ {
+			inst, err := uc.CreateInstance(ctx, &model.User{ID: fmt.Sprintf("user-%d", n)}, "webtop", nil)
+			if err != nil {
+				t.Fatalf("%s: failed to create instance: %v", name, err)
+			}
+			instances = append(instances, inst)
+		}
+		if err := repo.Delete(ctx, instances[0].InstanceID); err != nil {
+			t.Fatalf("%s: failed to delete instance: %v", name, err)
+		}
+
+		k8s.err = fmt.Errorf("pod already exists: %w", model.ErrInvalidInstanceState)
+		if err := uc.ProvisionQueuedInstances(ctx); !errors.Is(err, model.ErrInvalidInstanceState) {
+			t.Errorf("%s: expected the conflict to be reported, got %v", name, err)
+		}
+		queued, err := repo.ListQueued(ctx)
+		if err != nil {
+			t.Fatalf("%s: failed to list queued instances: %v", name, err)
+		}
+		if len(queued) != 2 || queued[0].InstanceID != instances[1].InstanceID {
+			t.Errorf("%s: expected the head to stay at the head of the queue, got %+v", name, queued)
+		}
+	}
+}
+
+func TestProvisionQueuedInstances_FailedInstanceCanBeStoppedOrDeleted(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_POD_COUNT": "1", "INSTANCE_QUEUE_ENABLED": "true"})
+	ctx := context.Background()
+	for _, action := range []string{"stop", "delete"} {
+		for name, repo := range testRepositories(t) {
+			k8s := &fakeKubernetesClient{}
+			uc := NewInstanceInteractor(repo, k8s, NewQuotaInteractor(repo))
+
+			var instances []*model.Instance
+			for n := 0; n < 3; n++ {
+				inst, err := uc.CreateInstance(ctx, &model.User{ID: fmt.Sprintf("user-%d", n)}, "webtop", nil)
+				if err != nil {
+					t.Fatalf("%s: failed to create instance: %v", name, err)
+				}
+				instances = append(instances, inst)
+			}
+			if err := repo.Delete(ctx, instances[0].InstanceID); err != nil {
+				t.Fatalf("%s: failed to delete instance: %v", name, err)
+			}
+			k8s.rejected = instances[1].InstanceID
+			_ = uc.ProvisionQueuedInstances(ctx)
+
+			// The failed instance has no pod, so it leaves the capacity to the next one
+			if count, err := repo.Count(ctx); err != nil || count != 1 {
+				t.Errorf("%s: expected 1 instance to take up capacity, got %d (%v)", name, count, err)
+			}
+			provisioned, err := repo.FindByID(ctx, instances[2].InstanceID)
+			if err != nil {
+				t.Fatalf("%s: failed to get instance: %v", name, err)
+			}
+			if provisioned.PodName == "" {
+				t.Errorf("%s: expected the next instance to be provisioned, got %+v", name, provisioned)
+			}
+
+			failed := instances[1]
+			switch action {
+			case "stop":
+				stopped, err := uc.StopInstance(ctx, failed.UserID, failed.InstanceID)
+				if err != nil {
+					t.Fatalf("%s: failed to stop the failed instance: %v", name, err)
+				}
+				if stopped.Status != model.InstanceStatusStopped {
+					t.Errorf("%s: expected the instance to be stopped, got %s", name, stopped.Status)
+				}
+			case "delete":
+				if err := uc.DeleteInstance(ctx, failed.UserID, failed.InstanceID); err != nil {
+					t.Fatalf("%s: failed to delete the failed instance: %v", name, err)
+				}
+				if _, err := repo.FindByID(ctx, failed.InstanceID); err == nil {
+					t.Errorf("%s: expected the instance to be deleted", name)
+				}
+			}
+		}
+	}
+}
+
+func TestStopInstance_KeepsRecordAndReleasesQuota(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_POD_COUNT": "1"})
+	ctx := context.Background()
+	user := &model.User{ID: "alice"}
+	for name, repo := range testRepositories(t) {
+		k8s := &fakeKubernetesClient{}
+		uc := NewInstanceInteractor(repo, k8s, NewQuotaInteractor(repo))
+
+		inst, err := uc.CreateInstance(ctx, user, "webtop", nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+		if _, err := uc.StopInstance(ctx, user.ID, inst.InstanceID); err != nil {
+			t.Fatalf("%s: failed to stop instance: %v", name, err)
+		}
+
+		if len(k8s.deleted) != 1 || k8s.deleted[0] != inst.InstanceID {
+			t.Errorf("%s: expected the pod to be deleted, got %v", name, k8s.deleted)
+		}
+		got, err := repo.FindByID(ctx, inst.InstanceID)
+		if err != nil {
+			t.Fatalf("%s: expected the record to be kept: %v", name, err)
+		}
+		if got.Status != model.InstanceStatusStopped || got.PodIP != "" {
+			t.Errorf("%s: expected the instance to be stopped, got %+v", name, got)
+		}
+		// The pod count is free for another instance
+		if _, err := uc.CreateInstance(ctx, &model.User{ID: "bob"}, "webtop", nil); err != nil {
+			t.Errorf("%s: expected the quota to be released, got %v", name, err)
+		}
+		if _, err := uc.StopInstance(ctx, user.ID, inst.InstanceID); !errors.Is(err, model.ErrInvalidInstanceState) {
+			t.Errorf("%s: expected a stopped instance not to stop again, got %v", name, err)
+		}
+	}
+}
+
+func TestStartInstance_ReservesQuotaAgain(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_POD_COUNT": "1"})
+	ctx := context.Background()
+	user := &model.User{ID: "alice"}
+	for name, repo := range testRepositories(t) {
+		uc := NewInstanceInteractor(repo, &fakeKubernetesClient{}, NewQuotaInteractor(repo))
+
+		inst, err := uc.CreateInstance(ctx, user, "webtop", nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+		if _, err := uc.StopInstance(ctx, user.ID, inst.InstanceID); err != nil {
+			t.Fatalf("%s: failed to stop instance: %v", name, err)
+		}
+		other, err := uc.CreateInstance(ctx, &model.User{ID: "bob"}, "webtop", nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+
+		// The cluster is full while the other instance runs
+		if _, err := uc.StartInstance(ctx, user.ID, inst.InstanceID); !errors.Is(err, model.ErrMaxInstancesReached) {
+			t.Errorf("%s: expected the start to be refused, got %v", name, err)
+		}
+		if err := uc.DeleteInstance(ctx, other.UserID, other.InstanceID); err != nil {
+			t.Fatalf("%s: failed to delete instance: %v", name, err)
+		}
+		started, err := uc.StartInstance(ctx, user.ID, inst.InstanceID)
+		if err != nil {
+			t.Fatalf("%s: failed to start instance: %v", name, err)
+		}
+		if started.Status != model.InstanceStatusPending || started.PodName == "" {
+			t.Errorf("%s: expected the pod to be created again, got %+v", name, started)
+		}
+		if count, err := repo.Count(ctx); err != nil || count != 1 {
+			t.Errorf("%s: expected the started instance to take up capacity, got %d (%v)", name, count, err)
+		}
+		if _, err := uc.StartInstance(ctx, user.ID, inst.InstanceID); !errors.Is(err, model.ErrInvalidInstanceState) {
+			t.Errorf("%s: expected a running instance not to start again, got %v", name, err)
+		}
+	}
+}
+
+func TestStartInstance_StopsAgainOnFailure(t *testing.T) {
+	loadTestConfig(t, nil)
+	ctx := context.Background()
+	user := &model.User{ID: "alice"}
+	for name, repo := range testRepositories(t) {
+		k8s := &fakeKubernetesClient{}
+		uc := NewInstanceInteractor(repo, k8s, NewQuotaInteractor(repo))
+
+		inst, err := uc.CreateInstance(ctx, user, "webtop", nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+		if _, err := uc.StopInstance(ctx, user.ID, inst.InstanceID); err != nil {
+			t.Fatalf("%s: failed to stop instance: %v", name, err)
+		}
+
+		k8s.err = errors.New("pods is forbidden")
+		if _, err := uc.StartInstance(ctx, user.ID, inst.InstanceID); err == nil {
+			t.Fatalf("%s: expected the start to fail", name)
+		}
+		got, err := repo.FindByID(ctx, inst.InstanceID)
+		if err != nil {
+			t.Fatalf("%s: failed to get instance: %v", name, err)
+		}
+		if got.Status != model.InstanceStatusStopped {
+			t.Errorf("%s: expected the instance to be stopped again, got %s", name, got.Status)
+		}
+		if count, err := repo.Count(ctx); err != nil || count != 0 {
+			t.Errorf("%s: expected the reservation to be released, got %d (%v)", name, count, err)
+		}
+	}
+}
+
+func TestCreateInstance_OneRunningInstancePerHomeVolume(t *testing.T) {
+	loadTestConfig(t, map[string]string{"MAX_INSTANCES_PER_USER_PER_TYPE": "3", "MAX_INSTANCES_PER_USER": "3"})
+	it, _ := config.GetInstanceType("webtop")
+	it.VolumeSize = "1Gi"
+	it.VolumeMountPath = "/config"
+	config.SetInstanceTypes(map[string]config.InstanceType{it.ID: it})
+
+	ctx := context.Background()
+	user := &model.User{ID: "alice"}
+	for name, repo := range testRepositories(t) {
+		uc := NewInstanceInteractor(repo, &fakeKubernetesClient{}, NewQuotaInteractor(repo))
+
+		first, err := uc.CreateInstance(ctx, user, "webtop", nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+		if _, err := uc.CreateInstance(ctx, user, "webtop", nil); !errors.Is(err, model.ErrMaxInstancesReached) {
+			t.Errorf("%s: expected a second instance on the home volume to be refused, got %v", name, err)
+		}
+		if _, err := uc.CreateInstance(ctx, &model.User{ID: "bob"}, "webtop", nil); err != nil {
+			t.Errorf("%s: expected other users to have their own volume, got %v", name, err)
+		}
+
+		// Once the first one is stopped, another can use the volume, and the first can't start next to it
+		stopped := *first
+		stopped.Status = model.InstanceStatusStopped
+		if err := repo.UpdateStatus(ctx, &stopped, first.Status); err != nil {
+			t.Fatalf("%s: failed to stop instance: %v", name, err)
+		}
+		if _, err := uc.CreateInstance(ctx, user, "webtop", nil); err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+		if _, err := uc.StartInstance(ctx, user.ID, first.InstanceID); !errors.Is(err, model.ErrMaxInstancesReached) {
+			t.Errorf("%s: expected the stopped instance not to start next to the other, got %v", name, err)
+		}
+	}
+}
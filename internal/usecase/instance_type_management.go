@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+)
+
+type InstanceTypeManagement interface {
+	// ReloadInstanceTypes reads, parses and validates the pod template, then atomically swaps the instance types.
+	// On error the current instance types are kept; a template that doesn't parse is model.ErrInvalidPodTemplate.
+	ReloadInstanceTypes(ctx context.Context) (config.InstanceTypeChanges, error)
+	// ValidateInstanceTypes validates the templates of the current instance types against the cluster.
+	ValidateInstanceTypes(ctx context.Context) ([]*model.InstanceTypeValidation, error)
+}
+
+type InstanceTypeInteractor struct {
+	source    repository.TemplateSource
+	k8sClient repository.KubernetesClient
+	logger    *slog.Logger
+	mu        sync.Mutex // serializes reloads so changes are reported against the right previous state
+}
+
+func NewInstanceTypeInteractor(source repository.TemplateSource, k8sClient repository.KubernetesClient, logger *slog.Logger) InstanceTypeManagement {
+	return &InstanceTypeInteractor{
+		source:    source,
+		k8sClient: k8sClient,
+		logger:    logger,
+	}
+}
+
+func (i *InstanceTypeInteractor) ReloadInstanceTypes(ctx context.Context) (config.InstanceTypeChanges, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	content, err := i.source.Read(ctx)
+	if err != nil {
+		return config.InstanceTypeChanges{}, fmt.Errorf("usecase.ReloadInstanceTypes: %w", err)
+	}
+	types, err := config.ParseInstanceTypes(content)
+	if err != nil {
+		return config.InstanceTypeChanges{}, fmt.Errorf("usecase.ReloadInstanceTypes: %w: %w", model.ErrInvalidPodTemplate, err)
+	}
+
+	changes := config.SetInstanceTypes(types)
+	if changes.Empty() {
+		i.logger.Info("Instance types reloaded without changes", "count", len(types))
+		return changes, nil
+	}
+	i.logger.Info("Instance types reloaded", "count", len(types), "added", changes.Added, "removed", changes.Removed, "changed", changes.Changed)
+
+	// Problems are reported but don't block the reload; the cluster may accept the pod later (e.g. a missing namespace quota).
+	for _, it := range types {
+		if !slices.Contains(changes.Added, it.ID) && !slices.Contains(changes.Changed, it.ID) {
+			continue
+		}
+		if _, err := i.validateInstanceType(ctx, it); err != nil {
+			i.logger.Error("Failed to validate instance type", "type", it.ID, "error", err)
+		}
+	}
+	return changes, nil
+}
+
+func (i *InstanceTypeInteractor) ValidateInstanceTypes(ctx context.Context) ([]*model.InstanceTypeValidation, error) {
+	types := config.GetInstanceTypes()
+	results := make([]*model.InstanceTypeValidation, 0, len(types))
+	for _, it := range types {
+		result, err := i.validateInstanceType(ctx, it)
+		if err != nil {
+			return nil, fmt.Errorf("usecase.ValidateInstanceTypes: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// validateInstanceType renders the template with sample parameters, validates it and logs the problems found.
+func (i *InstanceTypeInteractor) validateInstanceType(ctx context.Context, it config.InstanceType) (*model.InstanceTypeValidation, error) {
+	result := &model.InstanceTypeValidation{InstanceType: it.ID}
+
+	content, err := it.Render(it.SampleParameters())
+	if err != nil {
+		result.Problems = []model.TemplateProblem{{Check: model.TemplateCheckRender, Message: err.Error()}}
+	} else {
+		problems, err := i.k8sClient.ValidateInstancePod(ctx, it.ID, content, it.TargetPort)
+		if err != nil {
+			return nil, fmt.Errorf("instance type %s: %w", it.ID, err)
+		}
+		result.Problems = problems
+	}
+
+	for _, p := range result.Problems {
+		i.logger.Warn("Instance type template has a problem", "type", it.ID, "check", p.Check, "message", p.Message)
+	}
+	return result, nil
+}
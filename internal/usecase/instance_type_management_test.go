@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// fakeTemplateSource returns a fixed pod template, or fails to read it.
+type fakeTemplateSource struct {
+	content []byte
+	err     error
+}
+
+func (f *fakeTemplateSource) Read(ctx context.Context) ([]byte, error) {
+	return f.content, f.err
+}
+
+func (f *fakeTemplateSource) Watch(ctx context.Context, onChange func(ctx context.Context)) {}
+
+func TestReloadInstanceTypes_ReportsInvalidTemplates(t *testing.T) {
+	loadTestConfig(t, nil)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	invalid := NewInstanceTypeInteractor(&fakeTemplateSource{content: []byte("kind: [")}, nil, logger)
+	if _, err := invalid.ReloadInstanceTypes(context.Background()); !errors.Is(err, model.ErrInvalidPodTemplate) {
+		t.Errorf("Expected an invalid pod template, got %v", err)
+	}
+
+	unreadable := NewInstanceTypeInteractor(&fakeTemplateSource{err: errors.New("permission denied")}, nil, logger)
+	if _, err := unreadable.ReloadInstanceTypes(context.Background()); err == nil || errors.Is(err, model.ErrInvalidPodTemplate) {
+		t.Errorf("Expected a read failure, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+)
+
+// Quota resolves the instance limits that apply to a user and instance type, and reserves instances within them.
+// A reservation checks the limits and saves the instance atomically, so concurrent requests can't exceed them.
+// When the waiting queue is enabled, an instance that doesn't fit in the cluster capacity is saved as queued instead.
+type Quota interface {
+	// ReserveCreate saves the new instance if the user may create another instance of the type. Otherwise it returns
+	// an error wrapping model.ErrMaxInstancesReached, or model.ErrBudgetExceeded if the instance's resources don't fit in the budgets.
+	ReserveCreate(ctx context.Context, user *model.User, it config.InstanceType, instance *model.Instance) error
+	// ReserveStart saves the instance being started if it fits in the limits and budgets, returning the same errors as ReserveCreate.
+	// Stopped instances still count towards the per-user instance limits, so only the global and per-type caps apply,
+	// along with the single running instance per user of types with a home volume.
+	ReserveStart(ctx context.Context, instance *model.Instance) error
+	// ReserveQueued saves a queued instance being provisioned if it fits in the cluster capacity and the cap of its type.
+	// It returns an error for which isCapacityExhausted is true while the cluster capacity is exhausted.
+	ReserveQueued(ctx context.Context, instance *model.Instance) error
+}
+
+// capacityError marks errors of the cluster-wide limits, which queued instances wait for.
+type capacityError struct {
+	err error
+}
+
+func (e *capacityError) Error() string {
+	return e.err.Error()
+}
+
+func (e *capacityError) Unwrap() error {
+	return e.err
+}
+
+func isCapacityExhausted(err error) bool {
+	var capErr *capacityError
+	return errors.As(err, &capErr)
+}
+
+type QuotaInteractor struct {
+	instanceRepo repository.InstanceRepository
+}
+
+func NewQuotaInteractor(instanceRepo repository.InstanceRepository) Quota {
+	return &QuotaInteractor{
+		instanceRepo: instanceRepo,
+	}
+}
+
+func (q *QuotaInteractor) ReserveCreate(ctx context.Context, user *model.User, it config.InstanceType, instance *model.Instance) error {
+	return q.instanceRepo.Reserve(ctx, instance, func(ctx context.Context, counter repository.InstanceCounter) error {
+		// Check User Limit (queued and stopped instances count)
+		userCount, err := counter.CountByUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if limit := maxInstancesPerUser(user); userCount >= limit {
+			return fmt.Errorf("%w: max instances per user (%d) reached", model.ErrMaxInstancesReached, limit)
+		}
+
+		// Check Type Limit
+		typeCount, err := counter.CountByUserAndType(ctx, user.ID, it.ID)
+		if err != nil {
+			return err
+		}
+		if typeCount >= it.MaxInstancesPerUser {
+			return fmt.Errorf("%w: max instances for this type (%d) reached", model.ErrMaxInstancesReached, it.MaxInstancesPerUser)
+		}
+
+		if err := checkTypeCap(ctx, counter, it.ID); err != nil {
+			return err
+		}
+		if err := checkHomeVolume(ctx, counter, user.ID, it.ID); err != nil {
+			return err
+		}
+		if err := checkUserBudget(ctx, counter, user.ID, instance.Resources); err != nil {
+			return err
+		}
+		return reserveCapacity(ctx, counter, instance)
+	})
+}
+
+func (q *QuotaInteractor) ReserveStart(ctx context.Context, instance *model.Instance) error {
+	return q.instanceRepo.Reserve(ctx, instance, func(ctx context.Context, counter repository.InstanceCounter) error {
+		if err := checkTypeCap(ctx, counter, instance.Type); err != nil {
+			return err
+		}
+		if err := checkHomeVolume(ctx, counter, instance.UserID, instance.Type); err != nil {
+			return err
+		}
+		if err := checkUserBudget(ctx, counter, instance.UserID, instance.Resources); err != nil {
+			return err
+		}
+		return reserveCapacity(ctx, counter, instance)
+	})
+}
+
+func (q *QuotaInteractor) ReserveQueued(ctx context.Context, instance *model.Instance) error {
+	return q.instanceRepo.Reserve(ctx, instance, func(ctx context.Context, counter repository.InstanceCounter) error {
+		// Queued instances already count towards the per-user limits and budgets
+		if err := checkTypeCap(ctx, counter, instance.Type); err != nil {
+			return err
+		}
+		if err := checkHomeVolume(ctx, counter, instance.UserID, instance.Type); err != nil {
+			return err
+		}
+		return checkCapacity(ctx, counter, instance.Resources)
+	})
+}
+
+// reserveCapacity checks that the instance fits in the cluster capacity, or queues it if the queue is enabled.
+// While instances are waiting, new instances queue behind them even if they would fit, so the queue stays fair.
+func reserveCapacity(ctx context.Context, counter repository.InstanceCounter, instance *model.Instance) error {
+	capErr := checkCapacity(ctx, counter, instance.Resources)
+	if capErr != nil && !isCapacityExhausted(capErr) {
+		return capErr
+	}
+	if !config.InstanceQueueEnabled() {
+		return capErr
+	}
+
+	queued, err := counter.CountQueued(ctx)
+	if err != nil {
+		return err
+	}
+	if capErr == nil && queued == 0 {
+		return nil
+	}
+	if maxLength := config.InstanceQueueMaxLength(); maxLength > 0 && queued >= maxLength {
+		return &capacityError{fmt.Errorf("%w: the waiting queue is full (%d)", model.ErrMaxInstancesReached, maxLength)}
+	}
+	instance.Status = model.InstanceStatusQueued
+	return nil
+}
+
+// checkCapacity checks the global limit and the total resource budget, which instances can wait for in the queue.
+func checkCapacity(ctx context.Context, counter repository.InstanceCounter, requests model.Resources) error {
+	// Check Global Limit (stopped and queued instances do not count)
+	globalCount, err := counter.Count(ctx)
+	if err != nil {
+		return err
+	}
+	if globalCount >= config.MaxPodCount() {
+		return &capacityError{fmt.Errorf("%w: max pod count reached", model.ErrMaxInstancesReached)}
+	}
+
+	if budget := config.TotalResourceBudget(); budget != (model.Resources{}) {
+		used, err := counter.SumResources(ctx)
+		if err != nil {
+			return err
+		}
+		if err := checkBudget("total", budget, used, requests); err != nil {
+			return &capacityError{err}
+		}
+	}
+	return nil
+}
+
+// checkTypeCap checks the cap of the type across all users.
+func checkTypeCap(ctx context.Context, counter repository.InstanceCounter, instanceType string) error {
+	it, ok := config.GetInstanceType(instanceType)
+	if !ok || it.MaxInstances <= 0 {
+		return nil
+	}
+	count, err := counter.CountByType(ctx, instanceType)
+	if err != nil {
+		return err
+	}
+	if count >= it.MaxInstances {
+		return fmt.Errorf("%w: max instances of type %s (%d) reached", model.ErrMaxInstancesReached, instanceType, it.MaxInstances)
+	}
+	return nil
+}
+
+// checkHomeVolume allows each user one instance with a pod of a type with a home volume. The volume is a
+// ReadWriteOnce claim per user and type, which pods on different nodes can't mount at the same time.
+func checkHomeVolume(ctx context.Context, counter repository.InstanceCounter, userID, instanceType string) error {
+	it, ok := config.GetInstanceType(instanceType)
+	if !ok || it.VolumeSize == "" {
+		return nil
+	}
+	count, err := counter.CountByUserAndTypeWithPod(ctx, userID, instanceType)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: an instance of type %s is already using the home volume", model.ErrMaxInstancesReached, instanceType)
+	}
+	return nil
+}
+
+// checkUserBudget checks that the requested resources fit in the user's CPU and memory budget
+// next to the user's running and queued instances.
+func checkUserBudget(ctx context.Context, counter repository.InstanceCounter, userID string, requests model.Resources) error {
+	budget := config.UserResourceBudget()
+	if budget == (model.Resources{}) {
+		return nil
+	}
+	used, err := counter.SumResourcesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return checkBudget("per-user", budget, used, requests)
+}
+
+// checkBudget returns an error naming the budget and resource that the requests would exceed.
+func checkBudget(name string, budget, used, requests model.Resources) error {
+	if budget.CPUMillis > 0 && used.CPUMillis+requests.CPUMillis > budget.CPUMillis {
+		return fmt.Errorf("%w: %s CPU budget of %s exceeded (requested %s, in use %s)", model.ErrBudgetExceeded, name,
+			formatCPU(budget.CPUMillis), formatCPU(requests.CPUMillis), formatCPU(used.CPUMillis))
+	}
+	if budget.MemoryBytes > 0 && used.MemoryBytes+requests.MemoryBytes > budget.MemoryBytes {
+		return fmt.Errorf("%w: %s memory budget of %s exceeded (requested %s, in use %s)", model.ErrBudgetExceeded, name,
+			formatMemory(budget.MemoryBytes), formatMemory(requests.MemoryBytes), formatMemory(used.MemoryBytes))
+	}
+	return nil
+}
+
+func formatCPU(millis int64) string {
+	return resource.NewMilliQuantity(millis, resource.DecimalSI).String()
+}
+
+func formatMemory(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
+
+// maxInstancesPerUser resolves the per-user limit from the quota profiles: the highest limit of the user's
+// groups, then the limit of the user's auth method, then MAX_INSTANCES_PER_USER.
+func maxInstancesPerUser(user *model.User) int {
+	limit, byGroup, byAuthMethod := 0, false, false
+	for _, p := range config.QuotaProfiles() {
+		if p.Group != "" && slices.Contains(user.Groups, p.Group) {
+			if !byGroup || p.MaxInstancesPerUser > limit {
+				limit = p.MaxInstancesPerUser
+			}
+			byGroup = true
+		}
+	}
+	if byGroup {
+		return limit
+	}
+	for _, p := range config.QuotaProfiles() {
+		if p.AuthMethod != "" && p.AuthMethod == user.Type {
+			limit, byAuthMethod = p.MaxInstancesPerUser, true
+		}
+	}
+	if byAuthMethod {
+		return limit
+	}
+	return config.MaxInstancesPerUser()
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+)
+
+type VolumeManagement interface {
+	ListVolumes(ctx context.Context, userID string) ([]*model.Volume, error)
+	DeleteVolume(ctx context.Context, userID, volumeID string) error
+}
+
+type VolumeInteractor struct {
+	instanceRepo repository.InstanceRepository
+	k8sClient    repository.KubernetesClient
+}
+
+func NewVolumeInteractor(instanceRepo repository.InstanceRepository, k8sClient repository.KubernetesClient) VolumeManagement {
+	return &VolumeInteractor{
+		instanceRepo: instanceRepo,
+		k8sClient:    k8sClient,
+	}
+}
+
+func (v *VolumeInteractor) ListVolumes(ctx context.Context, userID string) ([]*model.Volume, error) {
+	return v.k8sClient.ListUserVolumes(ctx, userID)
+}
+
+func (v *VolumeInteractor) DeleteVolume(ctx context.Context, userID, volumeID string) error {
+	volumes, err := v.k8sClient.ListUserVolumes(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var volume *model.Volume
+	for _, vol := range volumes {
+		if vol.Name == volumeID {
+			volume = vol
+			break
+		}
+	}
+	if volume == nil {
+		return model.ErrNotFound
+	}
+
+	// The volume is shared by all of the user's instances of its type.
+	count, err := v.instanceRepo.CountByUserAndType(ctx, userID, volume.InstanceType)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return model.ErrVolumeInUse
+	}
+
+	return v.k8sClient.DeleteVolume(ctx, volume.Name)
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+	"github.com/aplulu/hakoniwa/internal/domain/repository"
+)
+
+// WarmPoolManagement keeps the warm pools of the instance types at their configured size.
+type WarmPoolManagement interface {
+	// ReconcileWarmPools starts pods for the pools that are short, and deletes pool pods that have failed,
+	// were started from an outdated template or are beyond the pool size.
+	ReconcileWarmPools(ctx context.Context) error
+}
+
+type WarmPoolInteractor struct {
+	k8sClient repository.KubernetesClient
+}
+
+func NewWarmPoolInteractor(k8sClient repository.KubernetesClient) WarmPoolManagement {
+	return &WarmPoolInteractor{
+		k8sClient: k8sClient,
+	}
+}
+
+type warmPool struct {
+	size     int
+	template []byte
+	hash     string
+	pods     []*model.WarmPoolPod
+}
+
+func (w *WarmPoolInteractor) ReconcileWarmPools(ctx context.Context) error {
+	var errs []error
+	pools := make(map[string]*warmPool)
+	for _, it := range config.GetInstanceTypes() {
+		if it.WarmPoolSize <= 0 {
+			continue
+		}
+		template, err := it.WarmPoolTemplate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("warm pool %s: %w", it.ID, err))
+			continue
+		}
+		pools[it.ID] = &warmPool{size: it.WarmPoolSize, template: template, hash: config.TemplateHash(template)}
+	}
+
+	pods, err := w.k8sClient.ListWarmPoolPods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		// Pods of removed types and outdated templates would never be claimed
+		pool, ok := pools[pod.InstanceType]
+		if !ok || pod.Failed || pod.TemplateHash != pool.hash {
+			if err := w.k8sClient.DeleteWarmPoolPod(ctx, pod.PodName); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		pool.pods = append(pool.pods, pod)
+	}
+
+	for id, pool := range pools {
+		// Keep the ready pods, then the oldest ones, which are closest to ready
+		sort.SliceStable(pool.pods, func(i, j int) bool {
+			if pool.pods[i].Ready != pool.pods[j].Ready {
+				return pool.pods[i].Ready
+			}
+			return pool.pods[i].CreatedAt.Before(pool.pods[j].CreatedAt)
+		})
+		for _, pod := range pool.pods[min(pool.size, len(pool.pods)):] {
+			if err := w.k8sClient.DeleteWarmPoolPod(ctx, pod.PodName); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for n := len(pool.pods); n < pool.size; n++ {
+			if err := w.k8sClient.CreateWarmPoolPod(ctx, id, pool.template); err != nil {
+				errs = append(errs, err)
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aplulu/hakoniwa/internal/config"
+	"github.com/aplulu/hakoniwa/internal/domain/model"
+)
+
+// fakeWarmPoolClient keeps the pool pods in memory and claims them in order.
+type fakeWarmPoolClient struct {
+	fakeKubernetesClient
+	mu      sync.Mutex
+	pods    []*model.WarmPoolPod
+	created int
+}
+
+func (f *fakeWarmPoolClient) ListWarmPoolPods(ctx context.Context) ([]*model.WarmPoolPod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.WarmPoolPod(nil), f.pods...), nil
+}
+
+func (f *fakeWarmPoolClient) CreateWarmPoolPod(ctx context.Context, instanceType string, template []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	f.pods = append(f.pods, &model.WarmPoolPod{
+		PodName:      fmt.Sprintf("hakoniwa-pool-%d", f.created),
+		InstanceType: instanceType,
+		TemplateHash: config.TemplateHash(template),
+		Ready:        true,
+	})
+	return nil
+}
+
+func (f *fakeWarmPoolClient) ClaimWarmPoolPod(ctx context.Context, instance *model.Instance, template []byte) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for n, pod := range f.pods {
+		if pod.InstanceType == instance.Type && pod.TemplateHash == config.TemplateHash(template) {
+			f.pods = append(f.pods[:n], f.pods[n+1:]...)
+			instance.PodName = pod.PodName
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeWarmPoolClient) DeleteWarmPoolPod(ctx context.Context, podName string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for n, pod := range f.pods {
+		if pod.PodName == podName {
+			f.pods = append(f.pods[:n], f.pods[n+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestWarmPool_ClaimAndRefill(t *testing.T) {
+	loadTestConfig(t, nil)
+	it, _ := config.GetInstanceType("webtop")
+	it.WarmPoolSize = 2
+	config.SetInstanceTypes(map[string]config.InstanceType{it.ID: it})
+
+	ctx := context.Background()
+	k8s := &fakeWarmPoolClient{pods: []*model.WarmPoolPod{
+		{PodName: "outdated", InstanceType: "webtop", TemplateHash: "0000000000000000"},
+		{PodName: "removed-type", InstanceType: "jupyter", TemplateHash: config.TemplateHash(it.Content)},
+	}}
+	pool := NewWarmPoolInteractor(k8s)
+	if err := pool.ReconcileWarmPools(ctx); err != nil {
+		t.Fatalf("Failed to reconcile warm pools: %v", err)
+	}
+	pods, _ := k8s.ListWarmPoolPods(ctx)
+	if len(pods) != 2 || pods[0].PodName != "hakoniwa-pool-1" || pods[1].PodName != "hakoniwa-pool-2" {
+		t.Fatalf("expected the pool to be replaced with 2 new pods, got %+v", pods)
+	}
+
+	for name, repo := range testRepositories(t) {
+		uc := NewInstanceInteractor(repo, k8s, NewQuotaInteractor(repo))
+		instance, err := uc.CreateInstance(ctx, &model.User{ID: "alice"}, "webtop", nil)
+		if err != nil {
+			t.Fatalf("%s: failed to create instance: %v", name, err)
+		}
+		if instance.PodName != pods[0].PodName {
+			t.Errorf("%s: expected the instance to claim %s, got %q", name, pods[0].PodName, instance.PodName)
+		}
+
+		if err := pool.ReconcileWarmPools(ctx); err != nil {
+			t.Fatalf("%s: failed to reconcile warm pools: %v", name, err)
+		}
+		pods, _ = k8s.ListWarmPoolPods(ctx)
+		if len(pods) != 2 {
+			t.Errorf("%s: expected the pool to be refilled to 2 pods, got %d", name, len(pods))
+		}
+	}
+
+	// Removing the pool size drains the pool
+	it.WarmPoolSize = 0
+	config.SetInstanceTypes(map[string]config.InstanceType{it.ID: it})
+	if err := pool.ReconcileWarmPools(ctx); err != nil {
+		t.Fatalf("Failed to reconcile warm pools: %v", err)
+	}
+	if pods, _ := k8s.ListWarmPoolPods(ctx); len(pods) != 0 {
+		t.Errorf("expected the pool of a type without a pool size to be drained, got %d pods", len(pods))
+	}
+}
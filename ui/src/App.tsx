@@ -13,7 +13,7 @@ import {
 import { AlertCircle } from 'lucide-react';
 
 // Types
-import type { AuthStatus, Configuration, Instance, InstanceType } from './types';
+import type { AuthStatus, Configuration, Instance, InstanceType, ParameterValue } from './types';
 
 // Components
 import { LoadingScreen } from './components/common/LoadingScreen';
@@ -88,7 +88,7 @@ function App() {
   );
 
   // Create Instance Action
-  const createInstance = useCallback(async (typeId: string) => {
+  const createInstance = useCallback(async (typeId: string, parameters: Record<string, ParameterValue>) => {
     setIsCreating(true);
     setAuthError('');
     try {
@@ -97,10 +97,15 @@ function App() {
         headers: {
           'Content-Type': 'application/json',
         },
-        body: JSON.stringify({ type: typeId }),
+        body: JSON.stringify({ type: typeId, parameters }),
       });
       if (res.status === 503) {
-        throw new Error(t('error.max_instances'));
+        const body = await res.json().catch(() => null);
+        throw new Error(body?.message ? t('error.limit_reached', { detail: body.message }) : t('error.max_instances'));
+      }
+      if (res.status === 400) {
+        const body = await res.json().catch(() => null);
+        throw new Error(body?.message || t('error.generic_desc'));
       }
       if (!res.ok) throw new Error('Failed to create instance');
       await mutate('/_hakoniwa/api/instances');
@@ -127,6 +132,49 @@ function App() {
     }
   }, []);
 
+  // Stop / Start Instance Actions
+  const stopInstance = useCallback(async (id: string) => {
+    try {
+      const res = await fetch(`/_hakoniwa/api/instances/${id}/stop`, {
+        method: 'POST',
+      });
+      if (!res.ok) throw new Error('Failed to stop instance');
+      await mutate('/_hakoniwa/api/instances');
+    } catch (err: any) {
+      console.error(err);
+    }
+  }, []);
+
+  const startInstance = useCallback(async (id: string) => {
+    setAuthError('');
+    try {
+      const res = await fetch(`/_hakoniwa/api/instances/${id}/start`, {
+        method: 'POST',
+      });
+      if (res.status === 503) {
+        const body = await res.json().catch(() => null);
+        throw new Error(body?.message ? t('error.limit_reached', { detail: body.message }) : t('error.max_instances'));
+      }
+      if (!res.ok) throw new Error('Failed to start instance');
+      await mutate('/_hakoniwa/api/instances');
+    } catch (err: any) {
+      console.error(err);
+      setAuthError(err.message || t('error.generic_desc'));
+    }
+  }, [t]);
+
+  const extendInstance = useCallback(async (id: string) => {
+    try {
+      const res = await fetch(`/_hakoniwa/api/instances/${id}/extend`, {
+        method: 'POST',
+      });
+      if (!res.ok) throw new Error('Failed to extend instance');
+      await mutate('/_hakoniwa/api/instances');
+    } catch (err: any) {
+      console.error(err);
+    }
+  }, []);
+
   // Login Anonymous Action
   const loginAnonymous = useCallback(async () => {
     setAuthError('');
@@ -237,7 +285,19 @@ function App() {
                         typeInfo={typeInfo}
                         index={index}
                         onDelete={deleteInstance}
+                        onStop={stopInstance}
+                        onStart={startInstance}
+                        onExtend={extendInstance}
                         onOpen={(id) => {
+                           if (config?.instance_routing === 'subdomain') {
+                             // Each instance has its own host, so it opens beside the dashboard
+                             window.open(`/_hakoniwa/open/${encodeURIComponent(id)}`, '_blank');
+                             return;
+                           }
+                           if (config?.instance_routing === 'path') {
+                             window.open(`/w/${encodeURIComponent(id)}/`, '_blank');
+                             return;
+                           }
                            document.cookie = `hakoniwa_instance_id=${id}; path=/`;
                            window.location.href = '/';
                         }}
@@ -2,19 +2,32 @@ import { useState } from 'react';
 import { Flex, Box, Heading, Text, Grid, Card, AspectRatio, Button } from '@radix-ui/themes';
 import { ArrowLeft, AlertCircle, Terminal, CheckCircle2 } from 'lucide-react';
 import { useTranslation } from 'react-i18next';
-import type { InstanceType } from '../../types';
+import type { InstanceType, ParameterValue } from '../../types';
+import { InstanceParameterField } from './InstanceParameterField';
 
 interface CreateInstanceViewProps {
   instanceTypes?: InstanceType[];
   isCreating: boolean;
   error?: string;
   onBack: () => void;
-  onCreate: (typeId: string) => void;
+  onCreate: (typeId: string, parameters: Record<string, ParameterValue>) => void;
 }
 
 export function CreateInstanceView({ instanceTypes, isCreating, error, onBack, onCreate }: CreateInstanceViewProps) {
   const { t } = useTranslation();
   const [selectedTypeId, setSelectedTypeId] = useState<string | null>(null);
+  const [parameters, setParameters] = useState<Record<string, ParameterValue>>({});
+
+  const selectedType = instanceTypes?.find((type) => type.id === selectedTypeId);
+
+  const selectType = (type: InstanceType) => {
+    setSelectedTypeId(type.id);
+    const defaults: Record<string, ParameterValue> = {};
+    type.parameters?.forEach((p) => {
+      if (p.default !== undefined) defaults[p.name] = p.default;
+    });
+    setParameters(defaults);
+  };
 
   return (
     <Flex direction="column" gap="6" className="anim-entry" style={{ height: '100%' }}>
@@ -46,7 +59,7 @@ export function CreateInstanceView({ instanceTypes, isCreating, error, onBack, o
           return (
             <Box 
               key={type.id}
-              onClick={() => setSelectedTypeId(type.id)}
+              onClick={() => selectType(type)}
               style={{ 
                 cursor: isCreating ? 'wait' : 'pointer',
                 position: 'relative',
@@ -123,6 +136,26 @@ export function CreateInstanceView({ instanceTypes, isCreating, error, onBack, o
         })}
       </Grid>
 
+      {/* Parameters */}
+      {selectedType?.parameters && selectedType.parameters.length > 0 && (
+        <Card size="3">
+          <Flex direction="column" gap="4">
+            <Heading size="4" weight="medium">{t('workspace.create.parameters_title')}</Heading>
+            <Grid columns={{ initial: '1', sm: '2' }} gap="4">
+              {selectedType.parameters.map((p) => (
+                <InstanceParameterField
+                  key={p.name}
+                  parameter={p}
+                  value={parameters[p.name]}
+                  disabled={isCreating}
+                  onChange={(value) => setParameters((prev) => ({ ...prev, [p.name]: value }))}
+                />
+              ))}
+            </Grid>
+          </Flex>
+        </Card>
+      )}
+
       {/* Footer Action */}
       <Box 
         mt="auto" 
@@ -136,7 +169,7 @@ export function CreateInstanceView({ instanceTypes, isCreating, error, onBack, o
         <Button 
           size="4" 
           disabled={!selectedTypeId || isCreating} 
-          onClick={() => selectedTypeId && onCreate(selectedTypeId)}
+          onClick={() => selectedTypeId && onCreate(selectedTypeId, parameters)}
           style={{ 
             width: '100%', 
             maxWidth: '240px', 
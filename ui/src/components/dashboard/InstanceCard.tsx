@@ -1,8 +1,9 @@
 import { useState } from 'react';
 import { Card, Box, Flex, Heading, DropdownMenu, IconButton, Text, Button } from '@radix-ui/themes';
-import { Terminal, MoreVertical, Trash2, Loader2 } from 'lucide-react';
+import { Terminal, MoreVertical, Trash2, Loader2, Square, Clock } from 'lucide-react';
 import { useTranslation } from 'react-i18next';
 import type { Instance, InstanceType } from '../../types';
+import { StartupProgress } from './StartupProgress';
 
 interface InstanceCardProps {
   instance: Instance;
@@ -10,9 +11,12 @@ interface InstanceCardProps {
   index: number;
   onDelete: (id: string) => void;
   onOpen: (id: string) => void;
+  onStop: (id: string) => void;
+  onStart: (id: string) => void;
+  onExtend: (id: string) => void;
 }
 
-export function InstanceCard({ instance, typeInfo, index, onDelete, onOpen }: InstanceCardProps) {
+export function InstanceCard({ instance, typeInfo, index, onDelete, onOpen, onStop, onStart, onExtend }: InstanceCardProps) {
   const { t } = useTranslation();
   const [isHovered, setIsHovered] = useState(false);
 
@@ -72,6 +76,12 @@ export function InstanceCard({ instance, typeInfo, index, onDelete, onOpen }: In
               </IconButton>
             </DropdownMenu.Trigger>
             <DropdownMenu.Content>
+              {instance.status !== 'stopped' && (
+                <DropdownMenu.Item onClick={() => onStop(instance.id)}>
+                  <Square size={14} style={{ marginRight: 8 }} />
+                  {t('workspace.action.stop')}
+                </DropdownMenu.Item>
+              )}
               <DropdownMenu.Item color="red" onClick={() => onDelete(instance.id)}>
                 <Trash2 size={14} style={{ marginRight: 8 }} />
                 {t('workspace.action.delete')}
@@ -91,19 +101,53 @@ export function InstanceCard({ instance, typeInfo, index, onDelete, onOpen }: In
                  width: 8, 
                  height: 8, 
                  borderRadius: '50%', 
-                 backgroundColor: instance.status === 'running' ? 'var(--green-9)' : instance.status === 'pending' ? 'var(--orange-9)' : 'var(--red-9)',
+                 backgroundColor: instance.status === 'running' ? 'var(--green-9)' : instance.status === 'pending' || instance.status === 'starting' || instance.status === 'queued' ? 'var(--orange-9)' : instance.status === 'stopped' ? 'var(--gray-9)' : 'var(--red-9)',
                }} 
              />
              <Text size="2" weight="medium" style={{ 
-               color: instance.status === 'running' ? 'var(--green-9)' : instance.status === 'pending' ? 'var(--orange-9)' : 'var(--red-9)',
+               color: instance.status === 'running' ? 'var(--green-9)' : instance.status === 'pending' || instance.status === 'starting' || instance.status === 'queued' ? 'var(--orange-9)' : instance.status === 'stopped' ? 'var(--gray-9)' : 'var(--red-9)',
                textTransform: 'capitalize' 
              }}>
                {t(`workspace.status.${instance.status}` as any)}
              </Text>
           </Flex>
+          {instance.status === 'queued' && instance.queue_position && (
+            <Text size="1" color="gray">
+              {t('workspace.queue_position', { position: instance.queue_position })}
+            </Text>
+          )}
+          {(instance.status === 'pending' || instance.status === 'starting') && instance.startup_phases?.length ? (
+            <StartupProgress phases={instance.startup_phases} />
+          ) : null}
+          {(instance.status_message || instance.status_reason) && (
+            <Text size="1" color={instance.status === 'failed' ? 'red' : 'gray'} title={instance.status_reason}>
+              {instance.status_message || instance.status_reason}
+            </Text>
+          )}
+          {instance.status === 'stopped' && instance.reap_reason && (
+            <Text size="1" color="gray">
+              {t(`workspace.reap_reason.${instance.reap_reason}` as any)}
+            </Text>
+          )}
         </Flex>
       </Flex>
 
+      {instance.expires_at && instance.status !== 'stopped' && (
+        <Box mt="3" p="2" style={{ background: 'var(--amber-3)', borderRadius: 'var(--radius-2)' }}>
+          <Flex align="center" justify="between" gap="2">
+            <Flex align="center" gap="2">
+              <Clock size={14} color="var(--amber-11)" />
+              <Text size="1" style={{ color: 'var(--amber-11)' }}>
+                {t('workspace.expiring', { time: new Date(instance.expires_at).toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' }) })}
+              </Text>
+            </Flex>
+            <Button size="1" variant="soft" color="amber" style={{ cursor: 'pointer' }} onClick={() => onExtend(instance.id)}>
+              {t('workspace.action.extend')}
+            </Button>
+          </Flex>
+        </Box>
+      )}
+
       <Box mt="4">
          {instance.status === 'running' ? (
            <Button 
@@ -114,6 +158,26 @@ export function InstanceCard({ instance, typeInfo, index, onDelete, onOpen }: In
            >
              {t('workspace.action.open')}
            </Button>
+         ) : instance.status === 'pending' || instance.status === 'starting' ? (
+           // The gateway shows a waiting page and switches to the workspace once it is ready
+           <Button 
+             size="3" 
+             variant="soft" 
+             style={{ width: '100%', cursor: 'pointer' }}
+             onClick={() => onOpen(instance.id)}
+           >
+             <Loader2 className="animate-spin" size={16} style={{ marginRight: 8 }} />
+             {t('workspace.action.open_when_ready')}
+           </Button>
+         ) : instance.status === 'stopped' ? (
+           <Button 
+             size="3" 
+             variant="soft" 
+             style={{ width: '100%', cursor: 'pointer' }}
+             onClick={() => onStart(instance.id)}
+           >
+             {t('workspace.action.start')}
+           </Button>
          ) : (
             <Button 
               size="3" 
@@ -122,9 +186,6 @@ export function InstanceCard({ instance, typeInfo, index, onDelete, onOpen }: In
               color="gray" 
               style={{ width: '100%' }}
             >
-              {instance.status === 'pending' ? (
-                <Loader2 className="animate-spin" size={16} style={{ marginRight: 8 }} />
-              ) : null}
               {t(`workspace.status.${instance.status}` as any)}
             </Button>
          )}
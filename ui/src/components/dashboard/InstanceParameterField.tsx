@@ -0,0 +1,88 @@
+import { Flex, Text, TextField, Select, Switch, Checkbox } from '@radix-ui/themes';
+import type { InstanceParameter, ParameterValue } from '../../types';
+
+interface InstanceParameterFieldProps {
+  parameter: InstanceParameter;
+  value: ParameterValue | undefined;
+  disabled?: boolean;
+  onChange: (value: ParameterValue) => void;
+}
+
+export function InstanceParameterField({ parameter, value, disabled, onChange }: InstanceParameterFieldProps) {
+  const label = parameter.display_name || parameter.name;
+  const range = [parameter.minimum, parameter.maximum].some(Boolean)
+    ? `${parameter.minimum ?? ''} - ${parameter.maximum ?? ''}`
+    : undefined;
+
+  const renderInput = () => {
+    switch (parameter.type) {
+      case 'boolean':
+        return (
+          <Switch
+            checked={value === true}
+            disabled={disabled}
+            onCheckedChange={(checked) => onChange(checked)}
+          />
+        );
+      case 'array': {
+        const items = Array.isArray(value) ? value : [];
+        return (
+          <Flex gap="3" wrap="wrap">
+            {parameter.enum?.map((item) => (
+              <Text as="label" size="2" key={item}>
+                <Flex gap="2" align="center">
+                  <Checkbox
+                    checked={items.includes(item)}
+                    disabled={disabled}
+                    onCheckedChange={(checked) =>
+                      onChange(checked ? [...items, item] : items.filter((i) => i !== item))
+                    }
+                  />
+                  {item}
+                </Flex>
+              </Text>
+            ))}
+          </Flex>
+        );
+      }
+      default:
+        if (parameter.enum && parameter.enum.length > 0) {
+          return (
+            <Select.Root
+              value={value === undefined ? undefined : String(value)}
+              disabled={disabled}
+              onValueChange={(v) => onChange(parameter.type === 'integer' ? Number(v) : v)}
+            >
+              <Select.Trigger />
+              <Select.Content>
+                {parameter.enum.map((item) => (
+                  <Select.Item key={item} value={item}>{item}</Select.Item>
+                ))}
+              </Select.Content>
+            </Select.Root>
+          );
+        }
+        return (
+          <TextField.Root
+            type={parameter.type === 'integer' ? 'number' : 'text'}
+            value={value === undefined ? '' : String(value)}
+            placeholder={range}
+            disabled={disabled}
+            onChange={(e) =>
+              onChange(parameter.type === 'integer' ? Number(e.target.value) : e.target.value)
+            }
+          />
+        );
+    }
+  };
+
+  return (
+    <Flex direction="column" gap="1">
+      <Text size="2" weight="medium">{label}</Text>
+      {renderInput()}
+      {parameter.description && (
+        <Text size="1" color="gray">{parameter.description}</Text>
+      )}
+    </Flex>
+  );
+}
@@ -0,0 +1,30 @@
+import { Flex, Progress, Text } from '@radix-ui/themes';
+import { useTranslation } from 'react-i18next';
+import type { StartupPhase, StartupPhaseTime } from '../../types';
+
+const PHASES: StartupPhase[] = ['scheduled', 'pulling_image', 'initializing', 'waiting_for_readiness', 'ready'];
+
+interface StartupProgressProps {
+  phases: StartupPhaseTime[];
+}
+
+export function StartupProgress({ phases }: StartupProgressProps) {
+  const { t } = useTranslation();
+  const current = phases[phases.length - 1];
+  const step = PHASES.indexOf(current.phase) + 1;
+  const elapsed = Math.max(0, Math.round((Date.now() - new Date(current.at).getTime()) / 1000));
+
+  return (
+    <Flex direction="column" gap="1">
+      <Progress value={(step / PHASES.length) * 100} size="1" />
+      <Text size="1" color="gray">
+        {t('workspace.startup_phase.progress', {
+          step,
+          total: PHASES.length,
+          phase: t(`workspace.startup_phase.${current.phase}` as any),
+          elapsed,
+        })}
+      </Text>
+    </Flex>
+  );
+}
@@ -20,12 +20,36 @@ const resources = {
       },
       workspace: {
         status: {
-          pending: 'Starting',
+          pending: 'Scheduling',
+          starting: 'Starting',
           running: 'Running',
+          failed: 'Failed to start',
           terminating: 'Stopping',
+          stopped: 'Stopped',
+          queued: 'Queued',
+        },
+        queue_position: 'Waiting for capacity (#{{position}} in queue)',
+        startup_phase: {
+          progress: '{{step}}/{{total}} {{phase}} ({{elapsed}}s)',
+          scheduled: 'Scheduled',
+          pulling_image: 'Pulling image',
+          initializing: 'Initializing',
+          waiting_for_readiness: 'Waiting for readiness',
+          ready: 'Ready',
+        },
+        expiring: 'Will be shut down at {{time}}',
+        reap_reason: {
+          idle_timeout: 'Stopped automatically after being idle',
+          max_lifetime: 'Stopped automatically after reaching its maximum lifetime',
+          off_hours: 'Stopped automatically for off-hours',
+          over_quota: 'Stopped automatically to stay within instance limits',
         },
         action: {
           open: 'Open',
+          open_when_ready: 'Open when ready',
+          start: 'Start',
+          extend: 'Keep alive',
+          stop: 'Stop Workspace',
           delete: 'Delete Workspace',
           cancel: 'Cancel',
           back_to_list: 'Back to list',
@@ -39,6 +63,7 @@ const resources = {
           submit: 'Launch Workspace',
           placeholder_select: 'Select type',
           no_types: 'No instance types available',
+          parameters_title: 'Options',
         },
       },
       user: {
@@ -51,6 +76,7 @@ const resources = {
         connection_failed: 'Failed to connect to server',
         login_failed: 'Login failed',
         max_instances: 'Maximum number of instances reached. Please try again later.',
+        limit_reached: 'Usage limit reached ({{detail}}). Please try again later.',
       },
       action: {
         retry: 'Retry',
@@ -88,12 +114,36 @@ const resources = {
       },
       workspace: {
         status: {
-          pending: '起動中',
+          pending: 'スケジュール中',
+          starting: '起動中',
           running: '実行中',
+          failed: '起動失敗',
           terminating: '停止中',
+          stopped: '停止済み',
+          queued: '待機中',
+        },
+        queue_position: '空きを待っています（{{position}}番目）',
+        startup_phase: {
+          progress: '{{step}}/{{total}} {{phase}}（{{elapsed}}秒）',
+          scheduled: 'スケジュール済み',
+          pulling_image: 'イメージを取得中',
+          initializing: '初期化中',
+          waiting_for_readiness: '準備完了を待機中',
+          ready: '準備完了',
+        },
+        expiring: '{{time}}に停止されます',
+        reap_reason: {
+          idle_timeout: '一定時間操作がなかったため自動停止されました',
+          max_lifetime: '最大稼働時間に達したため自動停止されました',
+          off_hours: '利用時間外のため自動停止されました',
+          over_quota: 'インスタンス数の上限を超えたため自動停止されました',
         },
         action: {
           open: '開く',
+          open_when_ready: '準備ができたら開く',
+          start: '起動',
+          extend: '延長する',
+          stop: 'ワークスペースを停止',
           delete: 'ワークスペースを削除',
           cancel: 'キャンセル',
           back_to_list: '一覧に戻る',
@@ -107,6 +157,7 @@ const resources = {
           submit: '作成する',
           placeholder_select: 'タイプを選択',
           no_types: '利用可能なタイプがありません',
+          parameters_title: 'オプション',
         },
       },
       user: {
@@ -119,6 +170,7 @@ const resources = {
         connection_failed: 'サーバーへの接続に失敗しました',
         login_failed: 'ログインに失敗しました',
         max_instances: 'インスタンス数の上限に達しました。しばらくしてから再度お試しください。',
+        limit_reached: '利用上限に達しました（{{detail}}）。しばらくしてから再度お試しください。',
       },
       action: {
         retry: '再試行',
@@ -1,11 +1,50 @@
-export type InstanceStatus = 'pending' | 'running' | 'terminating';
+export type InstanceStatus = 'pending' | 'starting' | 'running' | 'failed' | 'terminating' | 'stopped' | 'queued';
+
+export type StartupPhase = 'scheduled' | 'pulling_image' | 'initializing' | 'waiting_for_readiness' | 'ready';
+
+export interface StartupPhaseTime {
+  phase: StartupPhase;
+  at: string;
+}
+
+export type ReapReason = 'idle_timeout' | 'max_lifetime' | 'off_hours' | 'over_quota';
 
 export interface Instance {
   id: string;
   name: string;
   type: string;
   status: InstanceStatus;
+  status_reason?: string;
+  status_message?: string;
+  startup_phase?: StartupPhase;
+  startup_phases?: StartupPhaseTime[];
+  queue_position?: number;
   pod_ip?: string;
+  reap_reason?: ReapReason;
+  expires_at?: string;
+  extended_until?: string;
+  parameters?: Record<string, ParameterValue>;
+}
+
+export interface Volume {
+  id: string;
+  instance_type: string;
+  size: string;
+  status: string;
+  created_at: string;
+}
+
+export type ParameterValue = string | number | boolean | string[];
+
+export interface InstanceParameter {
+  name: string;
+  display_name?: string;
+  description?: string;
+  type: 'string' | 'integer' | 'boolean' | 'quantity' | 'array';
+  default?: ParameterValue;
+  enum?: string[];
+  minimum?: string;
+  maximum?: string;
 }
 
 export interface InstanceType {
@@ -13,6 +52,7 @@ export interface InstanceType {
   name: string;
   description?: string;
   logo_url?: string;
+  parameters?: InstanceParameter[];
 }
 
 export interface User {
@@ -33,4 +73,5 @@ export interface Configuration {
   auth_methods: string[];
   oidc_name: string;
   auth_auto_login: boolean;
+  instance_routing: 'cookie' | 'subdomain' | 'path';
 }